@@ -0,0 +1,62 @@
+#!/bin/bash
+
+# Copyright 2017 The Kubernetes Authors.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+
+# Generates the messages and the service of the gRPC transport of scheduler
+# extenders from plugin/pkg/scheduler/api/extender/v1alpha1/api.proto.
+
+set -o errexit
+set -o nounset
+set -o pipefail
+
+KUBE_ROOT=$(dirname "${BASH_SOURCE}")/..
+KUBE_SCHEDULER_EXTENDER_ROOT="${KUBE_SCHEDULER_EXTENDER_ROOT:-${KUBE_ROOT}/plugin/pkg/scheduler/api/extender/v1alpha1}"
+source "${KUBE_ROOT}/hack/lib/init.sh"
+
+kube::golang::setup_env
+
+BINS=(
+	vendor/github.com/golang/protobuf/protoc-gen-go
+)
+make -C "${KUBE_ROOT}" WHAT="${BINS[*]}"
+
+if [[ -z "$(which protoc)" || "$(protoc --version)" != "libprotoc 3."* ]]; then
+  echo "Generating protobuf requires protoc 3.0.0-beta1 or newer. Please download and"
+  echo "install the platform appropriate Protobuf package for your OS: "
+  echo
+  echo "  https://github.com/google/protobuf/releases"
+  echo
+  echo "WARNING: Protobuf changes are not being validated"
+  exit 1
+fi
+
+function cleanup {
+	rm -f ${KUBE_SCHEDULER_EXTENDER_ROOT}/api.pb.go.bak
+}
+
+trap cleanup EXIT
+
+gopath=$(dirname $(kube::util::find-binary "protoc-gen-go"))
+
+PATH="${gopath}:${PATH}" \
+  protoc \
+  --proto_path="${KUBE_SCHEDULER_EXTENDER_ROOT}" \
+  --go_out=plugins=grpc:${KUBE_SCHEDULER_EXTENDER_ROOT} ${KUBE_SCHEDULER_EXTENDER_ROOT}/api.proto
+
+# Update boilerplate for the generated file.
+echo "$(cat hack/boilerplate/boilerplate.go.txt ${KUBE_SCHEDULER_EXTENDER_ROOT}/api.pb.go)" > ${KUBE_SCHEDULER_EXTENDER_ROOT}/api.pb.go
+sed -i".bak" "s/Copyright YEAR/Copyright $(date '+%Y')/g" ${KUBE_SCHEDULER_EXTENDER_ROOT}/api.pb.go
+
+gofmt -l -s -w ${KUBE_SCHEDULER_EXTENDER_ROOT}/api.pb.go
@@ -0,0 +1,44 @@
+#!/bin/bash
+
+# Copyright 2017 The Kubernetes Authors.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+
+set -o errexit
+set -o nounset
+set -o pipefail
+
+KUBE_ROOT=$(dirname "${BASH_SOURCE}")/..
+KUBE_SCHEDULER_EXTENDER_ROOT="${KUBE_ROOT}/plugin/pkg/scheduler/api/extender/v1alpha1"
+source "${KUBE_ROOT}/hack/lib/init.sh"
+
+kube::golang::setup_env
+
+_tmp="${KUBE_ROOT}/_tmp"
+
+cleanup() {
+	rm -rf "${_tmp}"
+}
+
+trap "cleanup" EXIT SIGINT
+
+mkdir -p ${_tmp}
+cp ${KUBE_SCHEDULER_EXTENDER_ROOT}/api.proto ${_tmp}/
+
+KUBE_SCHEDULER_EXTENDER_ROOT=${_tmp} "${KUBE_ROOT}/hack/update-generated-scheduler-extender.sh"
+# The year of the boilerplate is the one the file was generated in.
+if ! diff -I "Copyright" -Naupr ${KUBE_SCHEDULER_EXTENDER_ROOT}/api.pb.go ${_tmp}/api.pb.go; then
+  echo "${KUBE_SCHEDULER_EXTENDER_ROOT}/api.pb.go is out of date. Please run hack/update-generated-scheduler-extender.sh"
+  exit 1
+fi
+echo "Generated scheduler extender api is up to date."
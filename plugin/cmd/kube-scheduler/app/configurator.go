@@ -101,7 +101,7 @@ func CreateScheduler(
 	)
 
 	// Rebuild the configurator with a default Create(...) method.
-	configurator = &schedulerConfigurator{
+	sc := &schedulerConfigurator{
 		configurator,
 		s.PolicyConfigFile,
 		s.AlgorithmProvider,
@@ -110,9 +110,17 @@ func CreateScheduler(
 		s.UseLegacyPolicyConfig,
 	}
 
-	return scheduler.NewFromConfigurator(configurator, func(cfg *scheduler.Config) {
+	sched, err := scheduler.NewFromConfigurator(sc, func(cfg *scheduler.Config) {
 		cfg.Recorder = recorder
 	})
+	if err != nil {
+		return nil, err
+	}
+	if s.PolicyReloadPeriod > 0 {
+		reloader := newPolicyReloader(sc, sched.Reload, recorder, s)
+		go reloader.run(s.PolicyReloadPeriod, sched.Config().StopEverything)
+	}
+	return sched, nil
 }
 
 // schedulerConfigurator is an interface wrapper that provides a way to create
@@ -19,6 +19,7 @@ package options
 
 import (
 	"fmt"
+	"time"
 
 	utilfeature "k8s.io/apiserver/pkg/util/feature"
 	"k8s.io/kubernetes/pkg/api"
@@ -49,6 +50,9 @@ type SchedulerServer struct {
 	// Kubeconfig is Path to kubeconfig file with authorization and master
 	// location information.
 	Kubeconfig string
+	// PolicyReloadPeriod is how often the policy ConfigMap or file is checked for changes,
+	// which are reloaded without restarting the scheduler. Changes are not reloaded if zero.
+	PolicyReloadPeriod time.Duration
 	// Dynamic conifguration for scheduler features.
 }
 
@@ -75,6 +79,7 @@ func (s *SchedulerServer) AddFlags(fs *pflag.FlagSet) {
 	fs.StringVar(&s.PolicyConfigMapName, "policy-configmap", s.PolicyConfigMapName, usage)
 	fs.StringVar(&s.PolicyConfigMapNamespace, "policy-configmap-namespace", s.PolicyConfigMapNamespace, "The namespace where policy ConfigMap is located. The system namespace will be used if this is not provided or is empty.")
 	fs.BoolVar(&s.UseLegacyPolicyConfig, "use-legacy-policy-config", false, "When set to true, scheduler will ignore policy ConfigMap and uses policy config file")
+	fs.DurationVar(&s.PolicyReloadPeriod, "policy-reload-period", 0, "How often the policy ConfigMap or file is checked for changes, which are reloaded without restarting the scheduler. A changed policy setting a different groupWorkers is rejected, the number of group workers only changes on restart. Reloading is disabled if 0.")
 	fs.BoolVar(&s.EnableProfiling, "profiling", true, "Enable profiling via web interface host:port/debug/pprof/")
 	fs.BoolVar(&s.EnableContentionProfiling, "contention-profiling", false, "Enable lock contention profiling, if profiling is enabled")
 	fs.StringVar(&s.Master, "master", s.Master, "The address of the Kubernetes API server (overrides any value in kubeconfig)")
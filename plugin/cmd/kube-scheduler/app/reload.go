@@ -0,0 +1,126 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+	clientv1 "k8s.io/client-go/pkg/api/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/kubernetes/plugin/cmd/kube-scheduler/app/options"
+	"k8s.io/kubernetes/plugin/pkg/scheduler"
+	schedulerapi "k8s.io/kubernetes/plugin/pkg/scheduler/api"
+	"k8s.io/kubernetes/plugin/pkg/scheduler/metrics"
+
+	"github.com/golang/glog"
+)
+
+// policyReloader polls the policy ConfigMap or file the scheduler was started with and hands a
+// scheduler config built from a changed policy to apply. A policy that fails validation is
+// reported and ignored until it changes again, the scheduler keeps using the last good one.
+type policyReloader struct {
+	configurator *schedulerConfigurator
+	apply        func(*scheduler.Config)
+	recorder     record.EventRecorder
+	// source is the object the reload events are reported against.
+	source *clientv1.ObjectReference
+
+	policy    *schedulerapi.Policy
+	rejected  *schedulerapi.Policy
+	lastError string
+}
+
+func newPolicyReloader(sc *schedulerConfigurator, apply func(*scheduler.Config), recorder record.EventRecorder, s *options.SchedulerServer) *policyReloader {
+	source := &clientv1.ObjectReference{Kind: "Endpoints", Namespace: s.LockObjectNamespace, Name: s.LockObjectName}
+	if !sc.useLegacyPolicyConfig && len(sc.policyConfigMap) != 0 {
+		source = &clientv1.ObjectReference{Kind: "ConfigMap", Namespace: sc.policyConfigMapNamespace, Name: sc.policyConfigMap}
+	}
+	// The scheduler has just been created from the policy in place, so it is the baseline.
+	policy, err := sc.getSchedulerPolicyConfig()
+	if err != nil {
+		glog.Errorf("Failed to get the scheduler policy the scheduler started with: %v", err)
+	}
+	return &policyReloader{
+		configurator: sc,
+		apply:        apply,
+		recorder:     recorder,
+		source:       source,
+		policy:       policy,
+	}
+}
+
+// run checks the policy for changes every period until stop is closed.
+func (r *policyReloader) run(period time.Duration, stop <-chan struct{}) {
+	wait.Until(r.reload, period, stop)
+}
+
+// reload creates a new scheduler config if the policy changed since it was last loaded.
+func (r *policyReloader) reload() {
+	policy, err := r.configurator.getSchedulerPolicyConfig()
+	if err != nil {
+		metrics.PolicyReloads.WithLabelValues("error").Inc()
+		// Only report a failure to read the policy once, not on every poll.
+		if err.Error() != r.lastError {
+			r.recorder.Eventf(r.source, clientv1.EventTypeWarning, "FailedPolicyReload", "Failed to read the scheduler policy: %v", err)
+		}
+		r.lastError = err.Error()
+		return
+	}
+	r.lastError = ""
+	if reflect.DeepEqual(policy, r.policy) || (r.rejected != nil && reflect.DeepEqual(policy, r.rejected)) {
+		return
+	}
+
+	cfg, err := r.create(policy)
+	if err != nil {
+		glog.Errorf("Rejected the changed scheduler policy: %v", err)
+		r.rejected = policy
+		metrics.PolicyReloads.WithLabelValues("invalid").Inc()
+		r.recorder.Eventf(r.source, clientv1.EventTypeWarning, "PolicyRejected", "Keeping the current scheduler policy, the changed one is invalid: %v", err)
+		return
+	}
+	r.apply(cfg)
+	r.policy, r.rejected = policy, nil
+	metrics.PolicyReloads.WithLabelValues("success").Inc()
+	metrics.PolicyLastReloadTimestamp.Set(float64(time.Now().Unix()))
+	glog.Infof("Reloaded the scheduler policy")
+	r.recorder.Eventf(r.source, clientv1.EventTypeNormal, "PolicyReloaded", "Scheduling with the changed scheduler policy")
+}
+
+// create builds the scheduler config of a policy, falling back to the algorithm provider like
+// schedulerConfigurator.Create when the policy was removed. CreateFromConfig validates the policy.
+// A policy changing the number of group workers is rejected, the workers are only started once.
+func (r *policyReloader) create(policy *schedulerapi.Policy) (*scheduler.Config, error) {
+	if current, changed := groupWorkers(r.policy), groupWorkers(policy); current != changed {
+		return nil, fmt.Errorf("groupWorkers cannot be changed from %d to %d without restarting the scheduler", current, changed)
+	}
+	if policy == nil {
+		return r.configurator.CreateFromProvider(r.configurator.algorithmProvider)
+	}
+	return r.configurator.CreateFromConfig(*policy)
+}
+
+// groupWorkers returns the number of group workers a policy sets, 0 if there is no policy.
+func groupWorkers(policy *schedulerapi.Policy) int {
+	if policy == nil {
+		return 0
+	}
+	return policy.GroupWorkers
+}
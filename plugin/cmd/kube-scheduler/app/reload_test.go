@@ -0,0 +1,102 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"k8s.io/client-go/tools/record"
+	"k8s.io/kubernetes/plugin/cmd/kube-scheduler/app/options"
+	"k8s.io/kubernetes/plugin/pkg/scheduler"
+	schedulerapi "k8s.io/kubernetes/plugin/pkg/scheduler/api"
+	"k8s.io/kubernetes/plugin/pkg/scheduler/api/validation"
+)
+
+// fakeConfigurator creates an empty scheduler config from every valid policy.
+type fakeConfigurator struct {
+	scheduler.Configurator
+}
+
+func (c fakeConfigurator) CreateFromConfig(policy schedulerapi.Policy) (*scheduler.Config, error) {
+	if err := validation.ValidatePolicy(policy); err != nil {
+		return nil, err
+	}
+	return &scheduler.Config{}, nil
+}
+
+func (c fakeConfigurator) CreateFromProvider(providerName string) (*scheduler.Config, error) {
+	return &scheduler.Config{}, nil
+}
+
+func TestPolicyReloader(t *testing.T) {
+	policyFile, err := ioutil.TempFile("", "policy")
+	if err != nil {
+		t.Fatalf("Failed to create policy file: %v", err)
+	}
+	defer os.Remove(policyFile.Name())
+	writePolicy := func(weight, groupWorkers string) {
+		policy := `{"kind": "Policy", "apiVersion": "v1", "priorities": [{"name": "LeastRequestedPriority", "weight": ` + weight + `}], "groupWorkers": ` + groupWorkers + `}`
+		if err := ioutil.WriteFile(policyFile.Name(), []byte(policy), 0644); err != nil {
+			t.Fatalf("Failed to write policy file: %v", err)
+		}
+	}
+	writePolicy("1", "0")
+
+	applied := 0
+	recorder := record.NewFakeRecorder(10)
+	sc := &schedulerConfigurator{Configurator: fakeConfigurator{}, policyFile: policyFile.Name()}
+	reloader := newPolicyReloader(sc, func(*scheduler.Config) { applied++ }, recorder, options.NewSchedulerServer())
+
+	tests := []struct {
+		name         string
+		weight       string
+		groupWorkers string
+		applied      int
+		event        string
+	}{
+		{name: "unchanged policy", weight: "1"},
+		{name: "changed policy", weight: "2", applied: 1, event: "Normal PolicyReloaded"},
+		{name: "invalid policy", weight: "0", applied: 1, event: "Warning PolicyRejected"},
+		{name: "rejected policy", weight: "0", applied: 1},
+		{name: "fixed policy", weight: "3", applied: 2, event: "Normal PolicyReloaded"},
+		{name: "changed group workers", weight: "3", groupWorkers: "2", applied: 2, event: "Warning PolicyRejected"},
+	}
+	for _, test := range tests {
+		groupWorkers := test.groupWorkers
+		if groupWorkers == "" {
+			groupWorkers = "0"
+		}
+		writePolicy(test.weight, groupWorkers)
+		reloader.reload()
+		if applied != test.applied {
+			t.Errorf("%s: expected %d reloads, got %d", test.name, test.applied, applied)
+		}
+		select {
+		case event := <-recorder.Events:
+			if test.event == "" || !strings.HasPrefix(event, test.event) {
+				t.Errorf("%s: unexpected event %q", test.name, event)
+			}
+		default:
+			if test.event != "" {
+				t.Errorf("%s: expected event %q", test.name, test.event)
+			}
+		}
+	}
+}
@@ -18,6 +18,7 @@ limitations under the License.
 package app
 
 import (
+	"encoding/json"
 	"fmt"
 	"net"
 	"net/http"
@@ -35,6 +36,7 @@ import (
 	"k8s.io/kubernetes/pkg/util/configz"
 	"k8s.io/kubernetes/plugin/cmd/kube-scheduler/app/options"
 	_ "k8s.io/kubernetes/plugin/pkg/scheduler/algorithmprovider"
+	"k8s.io/kubernetes/plugin/pkg/scheduler/core"
 	"k8s.io/kubernetes/plugin/pkg/scheduler/factory"
 
 	"github.com/golang/glog"
@@ -165,6 +167,7 @@ func startHTTP(s *options.SchedulerServer) {
 	}
 	configz.InstallHandler(mux)
 	mux.Handle("/metrics", prometheus.Handler())
+	mux.HandleFunc("/debug/extenders", extendersHealth)
 
 	server := &http.Server{
 		Addr:    net.JoinHostPort(s.Address, strconv.Itoa(int(s.Port))),
@@ -172,3 +175,11 @@ func startHTTP(s *options.SchedulerServer) {
 	}
 	glog.Fatal(server.ListenAndServe())
 }
+
+// extendersHealth serves the health of the scheduler extenders as JSON.
+func extendersHealth(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(core.ExtendersHealth()); err != nil {
+		glog.Errorf("Failed to write extenders health: %v", err)
+	}
+}
@@ -0,0 +1,98 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package predicates
+
+import (
+	"fmt"
+
+	"github.com/golang/glog"
+	"k8s.io/kubernetes/pkg/api/v1"
+	"k8s.io/kubernetes/plugin/pkg/scheduler/algorithm"
+	schedulerapi "k8s.io/kubernetes/plugin/pkg/scheduler/api"
+	"k8s.io/kubernetes/plugin/pkg/scheduler/schedulercache"
+	"k8s.io/kubernetes/plugin/pkg/scheduler/tools"
+)
+
+// AcceleratorTypeChecker keeps the members of a role on the accelerator types declared in the
+// scheduling group annotation.
+type AcceleratorTypeChecker struct {
+	GroupMemberChecker
+}
+
+// NewAcceleratorTypePredicate creates a predicate which checks MiniGroup.Accelerator.
+func NewAcceleratorTypePredicate(info NodeInfo, podLister algorithm.PodLister) algorithm.FitPredicate {
+	checker := &AcceleratorTypeChecker{
+		GroupMemberChecker{
+			info:      info,
+			podLister: podLister,
+		},
+	}
+	return checker.CheckAcceleratorType
+}
+
+// CheckAcceleratorType checks if the accelerator type of the node is one of the types accepted
+// by the pod's role and the same as the type of the members of the role already placed.
+// The order of the types is a preference which is left to the scheduler.
+func (c *AcceleratorTypeChecker) CheckAcceleratorType(pod *v1.Pod, meta interface{}, nodeInfo *schedulercache.NodeInfo) (bool, []algorithm.PredicateFailureReason, error) {
+	node := nodeInfo.Node()
+	if node == nil {
+		return false, nil, fmt.Errorf("node not found")
+	}
+	podGroup, members, err := c.getGroupMembers(pod, meta)
+	if err != nil {
+		return false, nil, err
+	}
+	if podGroup == nil || podGroup.Accelerator == nil {
+		return true, nil, nil
+	}
+	if !acceleratorTypeFits(pod, podGroup, members, node) {
+		return false, []algorithm.PredicateFailureReason{ErrAcceleratorTypeNotMatch}, nil
+	}
+	return true, nil, nil
+}
+
+// acceleratorTypeFits checks if the accelerator type of the node is accepted by the role of
+// the pod and the same as the type of the members of the role already placed.
+func acceleratorTypeFits(pod *v1.Pod, podGroup *schedulerapi.MiniGroup, members []tools.GroupMember, node *v1.Node) bool {
+	accelerator := podGroup.Accelerator
+	nodeType, ok := node.Labels[accelerator.Label]
+	if !ok {
+		return false
+	}
+	accepted := false
+	for _, t := range accelerator.Types {
+		if t == nodeType {
+			accepted = true
+			break
+		}
+	}
+	if !accepted {
+		return false
+	}
+
+	for _, member := range members {
+		if member.Role != podGroup.Role || isSamePod(member.Pod, pod) {
+			continue
+		}
+		if memberType := member.Node.Labels[accelerator.Label]; memberType != nodeType {
+			glog.V(10).Infof("Node %s has accelerator %s, role %s of group %s is placed on %s",
+				node.Name, nodeType, podGroup.Role, podGroup.Group, memberType)
+			return false
+		}
+	}
+	return true
+}
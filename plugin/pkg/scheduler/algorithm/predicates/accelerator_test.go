@@ -0,0 +1,105 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package predicates
+
+import (
+	"reflect"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kubernetes/pkg/api/v1"
+	"k8s.io/kubernetes/plugin/pkg/scheduler/algorithm"
+	schedulerapi "k8s.io/kubernetes/plugin/pkg/scheduler/api"
+	"k8s.io/kubernetes/plugin/pkg/scheduler/schedulercache"
+)
+
+func TestAcceleratorType(t *testing.T) {
+	acceleratorKey := "accelerator"
+	nodes := []*v1.Node{
+		{ObjectMeta: metav1.ObjectMeta{Name: "v100-1", Labels: map[string]string{acceleratorKey: "v100"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "v100-2", Labels: map[string]string{acceleratorKey: "v100"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "p100-1", Labels: map[string]string{acceleratorKey: "p100"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "k80-1", Labels: map[string]string{acceleratorKey: "k80"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "cpu-1"}},
+	}
+	accelerator := &schedulerapi.AcceleratorPreference{Label: acceleratorKey, Types: []string{"v100", "p100"}}
+	worker := schedulerapi.MiniGroup{Group: "default/job", Role: "worker", Accelerator: accelerator}
+	ps := schedulerapi.MiniGroup{Group: "default/job", Role: "ps"}
+
+	tests := []struct {
+		pod      *v1.Pod
+		existing []*v1.Pod
+		node     string
+		fits     bool
+		test     string
+	}{
+		{
+			pod:  groupPod("ps-0", "", ps),
+			node: "cpu-1",
+			fits: true,
+			test: "role without accelerator",
+		},
+		{
+			pod:  groupPod("worker-0", "", worker),
+			node: "p100-1",
+			fits: true,
+			test: "fallback type accepted",
+		},
+		{
+			pod:  groupPod("worker-0", "", worker),
+			node: "k80-1",
+			fits: false,
+			test: "type not accepted",
+		},
+		{
+			pod:  groupPod("worker-0", "", worker),
+			node: "cpu-1",
+			fits: false,
+			test: "node without accelerator",
+		},
+		{
+			pod:      groupPod("worker-1", "", worker),
+			existing: []*v1.Pod{groupPod("worker-0", "v100-1", worker)},
+			node:     "p100-1",
+			fits:     false,
+			test:     "type differs from placed members",
+		},
+		{
+			pod:      groupPod("worker-1", "", worker),
+			existing: []*v1.Pod{groupPod("worker-0", "v100-1", worker), groupPod("ps-0", "p100-1", ps)},
+			node:     "v100-2",
+			fits:     true,
+			test:     "same type as placed members",
+		},
+	}
+	expectedFailureReasons := []algorithm.PredicateFailureReason{ErrAcceleratorTypeNotMatch}
+
+	for _, test := range tests {
+		nodeInfoMap := schedulercache.CreateNodeNameToInfoMap(test.existing, nodes)
+		fit := AcceleratorTypeChecker{}
+		fits, reasons, err := fit.CheckAcceleratorType(test.pod, PredicateMetadata(test.pod, nodeInfoMap), nodeInfoMap[test.node])
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.test, err)
+		}
+		if !fits && !reflect.DeepEqual(reasons, expectedFailureReasons) {
+			t.Errorf("%s: unexpected failure reasons: %v, want: %v", test.test, reasons, expectedFailureReasons)
+		}
+		if fits != test.fits {
+			t.Errorf("%s: expected %v got %v", test.test, test.fits, fits)
+		}
+	}
+}
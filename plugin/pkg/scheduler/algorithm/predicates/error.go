@@ -38,6 +38,9 @@ var (
 	ErrNodeUnderMemoryPressure   = newPredicateFailureError("NodeUnderMemoryPressure")
 	ErrNodeUnderDiskPressure     = newPredicateFailureError("NodeUnderDiskPressure")
 	ErrVolumeNodeConflict        = newPredicateFailureError("NoVolumeNodeConflict")
+	ErrRoleConstraintsNotMatch   = newPredicateFailureError("MatchRoleConstraints")
+	ErrRoleSpreadNotMatch        = newPredicateFailureError("MatchRoleSpread")
+	ErrAcceleratorTypeNotMatch   = newPredicateFailureError("MatchAcceleratorType")
 	// ErrFakePredicate is used for test only. The fake predicates returning false also returns error
 	// as ErrFakePredicate.
 	ErrFakePredicate = newPredicateFailureError("FakePredicateError")
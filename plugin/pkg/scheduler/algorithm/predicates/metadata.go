@@ -21,6 +21,7 @@ import (
 	"k8s.io/kubernetes/pkg/api/v1"
 	"k8s.io/kubernetes/plugin/pkg/scheduler/algorithm"
 	"k8s.io/kubernetes/plugin/pkg/scheduler/schedulercache"
+	"k8s.io/kubernetes/plugin/pkg/scheduler/tools"
 	schedutil "k8s.io/kubernetes/plugin/pkg/scheduler/util"
 )
 
@@ -52,9 +53,35 @@ func (pfactory *PredicateMetadataFactory) GetMetadata(pod *v1.Pod, nodeNameToInf
 		podPorts:                  schedutil.GetUsedPorts(pod),
 		matchingAntiAffinityTerms: matchingTerms,
 	}
+	if _, ok := pod.Annotations[tools.SchedulingGroup]; ok {
+		predicateMetadata.podGroup = tools.GetSchedulingGroup(pod)
+		if predicateMetadata.podGroup != nil {
+			predicateMetadata.groupMembers = tools.GetGroupMembers(predicateMetadata.podGroup.Group, nodeNameToInfoMap)
+			if predicateMetadata.podGroup.Spread != nil {
+				nodes := make([]*v1.Node, 0, len(nodeNameToInfoMap))
+				for _, info := range nodeNameToInfoMap {
+					if info.Node() != nil {
+						nodes = append(nodes, info.Node())
+					}
+				}
+				predicateMetadata.SetCandidateNodes(nodes)
+			}
+		}
+	}
 	for predicateName, precomputeFunc := range predicatePrecomputations {
 		glog.V(10).Info("Precompute: %v", predicateName)
 		precomputeFunc(predicateMetadata)
 	}
 	return predicateMetadata
 }
+
+// SetCandidateNodes restricts the domains the role of the pod is spread over to the ones of
+// the nodes the pod is scheduled among, e.g. the nodes of the topology domain a gang is packed
+// into.
+func (m *predicateMetadata) SetCandidateNodes(nodes []*v1.Node) {
+	if m.podGroup == nil || m.podGroup.Spread == nil {
+		return
+	}
+	m.spreadCounts = tools.CountRoleMembers(m.pod, m.groupMembers, m.podGroup.Role, m.podGroup.Spread.TopologyKey,
+		spreadNodes(m.pod, m.podGroup, m.groupMembers, nodes))
+}
@@ -40,7 +40,9 @@ import (
 	volumeutil "k8s.io/kubernetes/pkg/volume/util"
 	"k8s.io/kubernetes/plugin/pkg/scheduler/algorithm"
 	priorityutil "k8s.io/kubernetes/plugin/pkg/scheduler/algorithm/priorities/util"
+	schedulerapi "k8s.io/kubernetes/plugin/pkg/scheduler/api"
 	"k8s.io/kubernetes/plugin/pkg/scheduler/schedulercache"
+	"k8s.io/kubernetes/plugin/pkg/scheduler/tools"
 	schedutil "k8s.io/kubernetes/plugin/pkg/scheduler/util"
 	"k8s.io/metrics/pkg/client/clientset_generated/clientset"
 )
@@ -123,6 +125,13 @@ type predicateMetadata struct {
 	matchingAntiAffinityTerms          []matchingPodAntiAffinityTerm
 	serviceAffinityMatchingPodList     []*v1.Pod
 	serviceAffinityMatchingPodServices []*v1.Service
+	// podGroup is the scheduling group of the pod, nil if the pod isn't annotated.
+	podGroup *schedulerapi.MiniGroup
+	// groupMembers are the members of podGroup already placed, assumed ones included.
+	groupMembers []tools.GroupMember
+	// spreadCounts are the members of the pod's role in each domain of podGroup.Spread,
+	// nil if the role isn't spread.
+	spreadCounts map[string]int
 }
 
 func isVolumeConflict(volume v1.Volume, pod *v1.Pod) bool {
@@ -516,6 +525,8 @@ func GetResourceRequest(pod *v1.Pod) *schedulercache.Resource {
 			default:
 				if v1helper.IsOpaqueIntResourceName(rName) {
 					result.AddOpaque(rName, rQuantity.Value())
+				} else if schedulercache.IsExtendedResourceName(rName) {
+					result.AddExtended(rName, rQuantity.Value())
 				}
 			}
 		}
@@ -556,6 +567,10 @@ func GetResourceRequest(pod *v1.Pod) *schedulercache.Resource {
 					if value > result.OpaqueIntResources[rName] {
 						result.SetOpaque(rName, value)
 					}
+				} else if schedulercache.IsExtendedResourceName(rName) {
+					if value := rQuantity.Value(); value > result.ExtendedResources[rName] {
+						result.SetExtended(rName, value)
+					}
 				}
 			}
 		}
@@ -586,7 +601,8 @@ func PodFitsResources(pod *v1.Pod, meta interface{}, nodeInfo *schedulercache.No
 		// We couldn't parse metadata - fallback to computing it.
 		podRequest = GetResourceRequest(pod)
 	}
-	if podRequest.MilliCPU == 0 && podRequest.Memory == 0 && podRequest.NvidiaGPU == 0 && len(podRequest.OpaqueIntResources) == 0 {
+	if podRequest.MilliCPU == 0 && podRequest.Memory == 0 && podRequest.NvidiaGPU == 0 &&
+		len(podRequest.OpaqueIntResources) == 0 && len(podRequest.ExtendedResources) == 0 {
 		return len(predicateFails) == 0, predicateFails, nil
 	}
 
@@ -623,6 +639,12 @@ func PodFitsResources(pod *v1.Pod, meta interface{}, nodeInfo *schedulercache.No
 		}
 	}
 
+	for rName, rQuant := range podRequest.ExtendedResources {
+		if allocatable.ExtendedResources[rName] < rQuant+nodeInfo.RequestedResource().ExtendedResources[rName] {
+			predicateFails = append(predicateFails, NewInsufficientResourceError(rName, podRequest.ExtendedResources[rName], nodeInfo.RequestedResource().ExtendedResources[rName], allocatable.ExtendedResources[rName]))
+		}
+	}
+
 	if glog.V(10) && len(predicateFails) == 0 {
 		// We explicitly don't do glog.V(10).Infof() to avoid computing all the parameters if this is
 		// not logged. There is visible performance gain from it.
@@ -502,6 +502,75 @@ func TestPodFitsResources(t *testing.T) {
 
 }
 
+func TestPodFitsExtendedResources(t *testing.T) {
+	extendedResourceA := v1.ResourceName("example.com/aaa")
+	tests := []struct {
+		pod      *v1.Pod
+		nodeInfo *schedulercache.NodeInfo
+		fits     bool
+		test     string
+		reasons  []algorithm.PredicateFailureReason
+	}{
+		{
+			pod: newResourcePod(schedulercache.Resource{MilliCPU: 1, Memory: 1, ExtendedResources: map[v1.ResourceName]int64{extendedResourceA: 2}}),
+			nodeInfo: schedulercache.NewNodeInfo(
+				newResourcePod(schedulercache.Resource{MilliCPU: 0, Memory: 0, ExtendedResources: map[v1.ResourceName]int64{extendedResourceA: 3}})),
+			fits: true,
+			test: "extended resource fits",
+		},
+		{
+			pod: newResourcePod(schedulercache.Resource{MilliCPU: 1, Memory: 1, ExtendedResources: map[v1.ResourceName]int64{extendedResourceA: 10}}),
+			nodeInfo: schedulercache.NewNodeInfo(
+				newResourcePod(schedulercache.Resource{MilliCPU: 0, Memory: 0})),
+			fits:    false,
+			test:    "extended resource capacity enforced",
+			reasons: []algorithm.PredicateFailureReason{NewInsufficientResourceError(extendedResourceA, 10, 0, 5)},
+		},
+		{
+			pod: newResourcePod(schedulercache.Resource{MilliCPU: 1, Memory: 1, ExtendedResources: map[v1.ResourceName]int64{extendedResourceA: 3}}),
+			nodeInfo: schedulercache.NewNodeInfo(
+				newResourcePod(schedulercache.Resource{MilliCPU: 0, Memory: 0, ExtendedResources: map[v1.ResourceName]int64{extendedResourceA: 3}})),
+			fits:    false,
+			test:    "extended resource allocatable enforced for existing pods",
+			reasons: []algorithm.PredicateFailureReason{NewInsufficientResourceError(extendedResourceA, 3, 3, 5)},
+		},
+		{
+			pod: newResourceInitPod(newResourcePod(schedulercache.Resource{MilliCPU: 1, Memory: 1}),
+				schedulercache.Resource{MilliCPU: 1, Memory: 1, ExtendedResources: map[v1.ResourceName]int64{extendedResourceA: 6}}),
+			nodeInfo: schedulercache.NewNodeInfo(
+				newResourcePod(schedulercache.Resource{MilliCPU: 0, Memory: 0})),
+			fits:    false,
+			test:    "extended resource capacity enforced for init container",
+			reasons: []algorithm.PredicateFailureReason{NewInsufficientResourceError(extendedResourceA, 6, 0, 5)},
+		},
+		{
+			pod: newResourcePod(schedulercache.Resource{MilliCPU: 1, Memory: 1, ExtendedResources: map[v1.ResourceName]int64{"hugepages-2Mi": 4 * 1024 * 1024}}),
+			nodeInfo: schedulercache.NewNodeInfo(
+				newResourcePod(schedulercache.Resource{MilliCPU: 0, Memory: 0})),
+			fits:    false,
+			test:    "huge pages are enforced when the node has none",
+			reasons: []algorithm.PredicateFailureReason{NewInsufficientResourceError("hugepages-2Mi", 4*1024*1024, 0, 0)},
+		},
+	}
+
+	for _, test := range tests {
+		allocatable := makeAllocatableResources(10, 20, 0, 32, 0, 20)
+		allocatable[extendedResourceA] = *resource.NewQuantity(5, resource.DecimalSI)
+		node := v1.Node{Status: v1.NodeStatus{Capacity: allocatable, Allocatable: allocatable}}
+		test.nodeInfo.SetNode(&node)
+		fits, reasons, err := PodFitsResources(test.pod, PredicateMetadata(test.pod, nil), test.nodeInfo)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.test, err)
+		}
+		if !fits && !reflect.DeepEqual(reasons, test.reasons) {
+			t.Errorf("%s: unexpected failure reasons: %v, want: %v", test.test, reasons, test.reasons)
+		}
+		if fits != test.fits {
+			t.Errorf("%s: expected: %v got %v", test.test, test.fits, fits)
+		}
+	}
+}
+
 func TestPodFitsHost(t *testing.T) {
 	tests := []struct {
 		pod  *v1.Pod
@@ -0,0 +1,187 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package predicates
+
+import (
+	"fmt"
+
+	"github.com/golang/glog"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/kubernetes/pkg/api/v1"
+	kubeletapis "k8s.io/kubernetes/pkg/kubelet/apis"
+	"k8s.io/kubernetes/plugin/pkg/scheduler/algorithm"
+	priorityutil "k8s.io/kubernetes/plugin/pkg/scheduler/algorithm/priorities/util"
+	schedulerapi "k8s.io/kubernetes/plugin/pkg/scheduler/api"
+	"k8s.io/kubernetes/plugin/pkg/scheduler/schedulercache"
+	"k8s.io/kubernetes/plugin/pkg/scheduler/tools"
+)
+
+// GroupMemberChecker looks up the placed members of a scheduling group. It is shared by
+// the predicates which enforce gang placement rules.
+type GroupMemberChecker struct {
+	info      NodeInfo
+	podLister algorithm.PodLister
+}
+
+// getGroupMembers returns the scheduling group of the pod and the members of that group
+// already placed, taken from the metadata if possible. The group is nil for pods which
+// are not annotated.
+func (c *GroupMemberChecker) getGroupMembers(pod *v1.Pod, meta interface{}) (*schedulerapi.MiniGroup, []tools.GroupMember, error) {
+	if predicateMeta, ok := meta.(*predicateMetadata); ok {
+		return predicateMeta.podGroup, predicateMeta.groupMembers, nil
+	}
+	// We couldn't parse metadata - fallback to computing it.
+	if _, ok := pod.Annotations[tools.SchedulingGroup]; !ok {
+		return nil, nil, nil
+	}
+	podGroup := tools.GetSchedulingGroup(pod)
+	if podGroup == nil {
+		return nil, nil, nil
+	}
+	allPods, err := c.podLister.List(labels.Everything())
+	if err != nil {
+		return nil, nil, err
+	}
+	var members []tools.GroupMember
+	for _, existingPod := range allPods {
+		if len(existingPod.Spec.NodeName) == 0 {
+			continue
+		}
+		if _, ok := existingPod.Annotations[tools.SchedulingGroup]; !ok {
+			continue
+		}
+		existingGroup := tools.GetSchedulingGroup(existingPod)
+		if existingGroup == nil || existingGroup.Group != podGroup.Group {
+			continue
+		}
+		node, err := c.info.GetNodeInfo(existingPod.Spec.NodeName)
+		if err != nil {
+			return nil, nil, err
+		}
+		members = append(members, tools.GroupMember{Pod: existingPod, Role: existingGroup.Role, Node: node})
+	}
+	return podGroup, members, nil
+}
+
+// RoleConstraintsChecker enforces the role level placement constraints declared in the
+// scheduling group annotation.
+type RoleConstraintsChecker struct {
+	GroupMemberChecker
+}
+
+// NewRoleConstraintsPredicate creates a predicate which checks MiniGroup.Constraints.
+func NewRoleConstraintsPredicate(info NodeInfo, podLister algorithm.PodLister) algorithm.FitPredicate {
+	checker := &RoleConstraintsChecker{
+		GroupMemberChecker{
+			info:      info,
+			podLister: podLister,
+		},
+	}
+	return checker.CheckRoleConstraints
+}
+
+// CheckRoleConstraints checks if placing the pod on the node keeps the constraints of its
+// role satisfied, counting the members of the group already placed on the node and in its
+// topology domains. Pods without constraints always fit.
+func (c *RoleConstraintsChecker) CheckRoleConstraints(pod *v1.Pod, meta interface{}, nodeInfo *schedulercache.NodeInfo) (bool, []algorithm.PredicateFailureReason, error) {
+	node := nodeInfo.Node()
+	if node == nil {
+		return false, nil, fmt.Errorf("node not found")
+	}
+	podGroup, members, err := c.getGroupMembers(pod, meta)
+	if err != nil {
+		return false, nil, err
+	}
+	if podGroup == nil || podGroup.Constraints == nil {
+		return true, nil, nil
+	}
+	constraints := podGroup.Constraints
+
+	if constraints.MaxPerNode > 0 {
+		count := 0
+		for _, existingPod := range nodeInfo.Pods() {
+			if !isSamePod(existingPod, pod) && tools.IsMemberOfRole(existingPod, podGroup.Group, podGroup.Role) {
+				count++
+			}
+		}
+		if count >= constraints.MaxPerNode {
+			glog.V(10).Infof("Node %s already has %d members of role %s of group %s", node.Name, count, podGroup.Role, podGroup.Group)
+			return false, []algorithm.PredicateFailureReason{ErrRoleConstraintsNotMatch}, nil
+		}
+	}
+
+	if constraints.MaxPerDomain > 0 && len(constraints.TopologyKey) != 0 {
+		count := 0
+		for _, member := range members {
+			if member.Role == podGroup.Role && !isSamePod(member.Pod, pod) &&
+				priorityutil.NodesHaveSameTopologyKey(node, member.Node, constraints.TopologyKey) {
+				count++
+			}
+		}
+		if count >= constraints.MaxPerDomain {
+			glog.V(10).Infof("Domain %s of node %s already has %d members of role %s of group %s",
+				constraints.TopologyKey, node.Name, count, podGroup.Role, podGroup.Group)
+			return false, []algorithm.PredicateFailureReason{ErrRoleConstraintsNotMatch}, nil
+		}
+	}
+
+	for i := range constraints.CoLocate {
+		term := &constraints.CoLocate[i]
+		found, inDomain := anyMemberInDomain(pod, node, members, term)
+		// The first member placed can't be co-located with anything; the referenced role
+		// should be declared as a dependency so that it is placed first.
+		if found && !inDomain {
+			return false, []algorithm.PredicateFailureReason{ErrRoleConstraintsNotMatch}, nil
+		}
+	}
+
+	for i := range constraints.Avoid {
+		if _, inDomain := anyMemberInDomain(pod, node, members, &constraints.Avoid[i]); inDomain {
+			return false, []algorithm.PredicateFailureReason{ErrRoleConstraintsNotMatch}, nil
+		}
+	}
+
+	return true, nil, nil
+}
+
+// anyMemberInDomain checks the members referenced by the term. The first return value
+// indicates whether any of them is placed, the second whether one of them is in the same
+// topology domain as the node.
+func anyMemberInDomain(pod *v1.Pod, node *v1.Node, members []tools.GroupMember, term *schedulerapi.RoleTopologyTerm) (bool, bool) {
+	topologyKey := term.TopologyKey
+	if len(topologyKey) == 0 {
+		topologyKey = kubeletapis.LabelHostname
+	}
+	found := false
+	for _, member := range members {
+		if member.Role != term.Role || isSamePod(member.Pod, pod) {
+			continue
+		}
+		if len(term.Member) != 0 && member.Pod.Name != term.Member {
+			continue
+		}
+		found = true
+		if member.Node.Name == node.Name || priorityutil.NodesHaveSameTopologyKey(node, member.Node, topologyKey) {
+			return true, true
+		}
+	}
+	return found, false
+}
+
+func isSamePod(a, b *v1.Pod) bool {
+	return a.Namespace == b.Namespace && a.Name == b.Name
+}
@@ -0,0 +1,139 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package predicates
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kubernetes/pkg/api/v1"
+	"k8s.io/kubernetes/plugin/pkg/scheduler/algorithm"
+	schedulerapi "k8s.io/kubernetes/plugin/pkg/scheduler/api"
+	"k8s.io/kubernetes/plugin/pkg/scheduler/schedulercache"
+	"k8s.io/kubernetes/plugin/pkg/scheduler/tools"
+)
+
+func groupPod(name, nodeName string, miniGroup schedulerapi.MiniGroup) *v1.Pod {
+	data, _ := json.Marshal(miniGroup)
+	return &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   "default",
+			Annotations: map[string]string{tools.SchedulingGroup: string(data)},
+		},
+		Spec: v1.PodSpec{NodeName: nodeName},
+	}
+}
+
+func TestRoleConstraints(t *testing.T) {
+	zoneKey := "failure-domain.beta.kubernetes.io/zone"
+	nodes := []*v1.Node{
+		{ObjectMeta: metav1.ObjectMeta{Name: "node1", Labels: map[string]string{zoneKey: "z1"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "node2", Labels: map[string]string{zoneKey: "z1"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "node3", Labels: map[string]string{zoneKey: "z2"}}},
+	}
+	ps := schedulerapi.MiniGroup{Group: "default/job", Role: "ps", Constraints: &schedulerapi.RoleConstraints{MaxPerNode: 1}}
+	worker := schedulerapi.MiniGroup{Group: "default/job", Role: "worker", Constraints: &schedulerapi.RoleConstraints{MaxPerDomain: 1, TopologyKey: zoneKey}}
+	launcher := schedulerapi.MiniGroup{Group: "default/job", Role: "launcher", Constraints: &schedulerapi.RoleConstraints{
+		CoLocate: []schedulerapi.RoleTopologyTerm{{Role: "worker", Member: "worker-0"}},
+		Avoid:    []schedulerapi.RoleTopologyTerm{{Role: "ps"}},
+	}}
+	otherGroupPS := ps
+	otherGroupPS.Group = "default/other"
+
+	tests := []struct {
+		pod      *v1.Pod
+		existing []*v1.Pod
+		node     string
+		fits     bool
+		test     string
+	}{
+		{
+			pod:  groupPod("plain", "", schedulerapi.MiniGroup{Group: "default/job", Role: "ps"}),
+			node: "node1",
+			fits: true,
+			test: "role without constraints",
+		},
+		{
+			pod:      groupPod("ps-1", "", ps),
+			existing: []*v1.Pod{groupPod("ps-0", "node1", ps)},
+			node:     "node1",
+			fits:     false,
+			test:     "max per node reached",
+		},
+		{
+			pod:      groupPod("ps-1", "", ps),
+			existing: []*v1.Pod{groupPod("ps-0", "node1", otherGroupPS)},
+			node:     "node1",
+			fits:     true,
+			test:     "members of another group are not counted",
+		},
+		{
+			pod:      groupPod("worker-1", "", worker),
+			existing: []*v1.Pod{groupPod("worker-0", "node1", worker)},
+			node:     "node2",
+			fits:     false,
+			test:     "max per domain reached",
+		},
+		{
+			pod:      groupPod("worker-1", "", worker),
+			existing: []*v1.Pod{groupPod("worker-0", "node1", worker)},
+			node:     "node3",
+			fits:     true,
+			test:     "max per domain in another domain",
+		},
+		{
+			pod:      groupPod("launcher", "", launcher),
+			existing: []*v1.Pod{groupPod("worker-0", "node1", worker), groupPod("worker-1", "node3", worker)},
+			node:     "node3",
+			fits:     false,
+			test:     "not co-located with the referenced member",
+		},
+		{
+			pod:      groupPod("launcher", "", launcher),
+			existing: []*v1.Pod{groupPod("worker-0", "node1", worker), groupPod("ps-0", "node1", ps)},
+			node:     "node1",
+			fits:     false,
+			test:     "co-located but with an avoided role",
+		},
+		{
+			pod:      groupPod("launcher", "", launcher),
+			existing: []*v1.Pod{groupPod("worker-0", "node1", worker), groupPod("ps-0", "node2", ps)},
+			node:     "node1",
+			fits:     true,
+			test:     "co-located and avoiding",
+		},
+	}
+	expectedFailureReasons := []algorithm.PredicateFailureReason{ErrRoleConstraintsNotMatch}
+
+	for _, test := range tests {
+		nodeInfoMap := schedulercache.CreateNodeNameToInfoMap(test.existing, nodes)
+		fit := RoleConstraintsChecker{}
+		fits, reasons, err := fit.CheckRoleConstraints(test.pod, PredicateMetadata(test.pod, nodeInfoMap), nodeInfoMap[test.node])
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.test, err)
+		}
+		if !fits && !reflect.DeepEqual(reasons, expectedFailureReasons) {
+			t.Errorf("%s: unexpected failure reasons: %v, want: %v", test.test, reasons, expectedFailureReasons)
+		}
+		if fits != test.fits {
+			t.Errorf("%s: expected %v got %v", test.test, test.fits, fits)
+		}
+	}
+}
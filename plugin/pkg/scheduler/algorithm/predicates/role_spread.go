@@ -0,0 +1,124 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package predicates
+
+import (
+	"fmt"
+
+	"github.com/golang/glog"
+	"k8s.io/kubernetes/pkg/api/v1"
+	v1helper "k8s.io/kubernetes/pkg/api/v1/helper"
+	"k8s.io/kubernetes/plugin/pkg/scheduler/algorithm"
+	schedulerapi "k8s.io/kubernetes/plugin/pkg/scheduler/api"
+	"k8s.io/kubernetes/plugin/pkg/scheduler/schedulercache"
+	"k8s.io/kubernetes/plugin/pkg/scheduler/tools"
+)
+
+// RoleSpreadChecker keeps the members of a role evenly spread over the topology domains
+// declared in the scheduling group annotation.
+type RoleSpreadChecker struct {
+	GroupMemberChecker
+	nodeLister algorithm.NodeLister
+}
+
+// NewRoleSpreadPredicate creates a predicate which checks MiniGroup.Spread.
+func NewRoleSpreadPredicate(info NodeInfo, podLister algorithm.PodLister, nodeLister algorithm.NodeLister) algorithm.FitPredicate {
+	checker := &RoleSpreadChecker{
+		GroupMemberChecker: GroupMemberChecker{
+			info:      info,
+			podLister: podLister,
+		},
+		nodeLister: nodeLister,
+	}
+	return checker.CheckRoleSpread
+}
+
+// CheckRoleSpread checks if placing the pod on the node keeps the difference between the
+// number of members of its role in the node's domain and in the least used domain within
+// MaxSkew. Both the members assumed in the current attempt and the running members of the
+// group are counted. Only the domains of nodes the pod is eligible for are taken into account,
+// see spreadNodes. Nodes without the topology key don't fit spread pods.
+func (c *RoleSpreadChecker) CheckRoleSpread(pod *v1.Pod, meta interface{}, nodeInfo *schedulercache.NodeInfo) (bool, []algorithm.PredicateFailureReason, error) {
+	node := nodeInfo.Node()
+	if node == nil {
+		return false, nil, fmt.Errorf("node not found")
+	}
+	podGroup, members, err := c.getGroupMembers(pod, meta)
+	if err != nil {
+		return false, nil, err
+	}
+	if podGroup == nil || podGroup.Spread == nil {
+		return true, nil, nil
+	}
+	spread := podGroup.Spread
+
+	domain, ok := node.Labels[spread.TopologyKey]
+	if !ok {
+		return false, []algorithm.PredicateFailureReason{ErrRoleSpreadNotMatch}, nil
+	}
+
+	var counts map[string]int
+	if predicateMeta, ok := meta.(*predicateMetadata); ok && predicateMeta.spreadCounts != nil {
+		counts = predicateMeta.spreadCounts
+	} else {
+		nodes, err := c.nodeLister.List()
+		if err != nil {
+			return false, nil, err
+		}
+		counts = tools.CountRoleMembers(pod, members, podGroup.Role, spread.TopologyKey, spreadNodes(pod, podGroup, members, nodes))
+	}
+
+	minCount := counts[domain]
+	for _, count := range counts {
+		if count < minCount {
+			minCount = count
+		}
+	}
+	maxSkew := spread.MaxSkew
+	if maxSkew <= 0 {
+		maxSkew = 1
+	}
+	if counts[domain]+1-minCount > maxSkew {
+		glog.V(10).Infof("Domain %s of node %s has %d members of role %s of group %s, the least used domain has %d",
+			domain, node.Name, counts[domain], podGroup.Role, podGroup.Group, minCount)
+		return false, []algorithm.PredicateFailureReason{ErrRoleSpreadNotMatch}, nil
+	}
+	return true, nil, nil
+}
+
+// spreadNodes returns the nodes whose domains the members of the pod's role are spread over:
+// the nodes matching its node selector and affinity, with NoSchedule and NoExecute taints it
+// tolerates and, for roles placed on accelerators, of an accepted accelerator type. The
+// domains of other nodes never get a member, counting them would block the spread for good.
+func spreadNodes(pod *v1.Pod, podGroup *schedulerapi.MiniGroup, members []tools.GroupMember, nodes []*v1.Node) []*v1.Node {
+	result := make([]*v1.Node, 0, len(nodes))
+	for _, node := range nodes {
+		if !podMatchesNodeLabels(pod, node) {
+			continue
+		}
+		if !v1helper.TolerationsTolerateTaintsWithFilter(pod.Spec.Tolerations, node.Spec.Taints, func(t *v1.Taint) bool {
+			return t.Effect == v1.TaintEffectNoSchedule || t.Effect == v1.TaintEffectNoExecute
+		}) {
+			continue
+		}
+		if podGroup.Accelerator != nil && !acceleratorTypeFits(pod, podGroup, members, node) {
+			continue
+		}
+		result = append(result, node)
+	}
+	return result
+}
@@ -0,0 +1,135 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package predicates
+
+import (
+	"reflect"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kubernetes/pkg/api/v1"
+	"k8s.io/kubernetes/plugin/pkg/scheduler/algorithm"
+	schedulerapi "k8s.io/kubernetes/plugin/pkg/scheduler/api"
+	"k8s.io/kubernetes/plugin/pkg/scheduler/schedulercache"
+)
+
+func TestRoleSpread(t *testing.T) {
+	zoneKey := "failure-domain.beta.kubernetes.io/zone"
+	nodes := []*v1.Node{
+		{ObjectMeta: metav1.ObjectMeta{Name: "node1", Labels: map[string]string{zoneKey: "z1"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "node2", Labels: map[string]string{zoneKey: "z1"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "node3", Labels: map[string]string{zoneKey: "z2"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "node4"}},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "node5", Labels: map[string]string{zoneKey: "z3"}},
+			Spec:       v1.NodeSpec{Taints: []v1.Taint{{Key: "dedicated", Value: "other", Effect: v1.TaintEffectNoSchedule}}},
+		},
+	}
+	shard := schedulerapi.MiniGroup{Group: "default/db", Role: "shard", Spread: &schedulerapi.RoleSpread{TopologyKey: zoneKey}}
+	skewed := shard
+	skewed.Spread = &schedulerapi.RoleSpread{TopologyKey: zoneKey, MaxSkew: 2}
+	router := schedulerapi.MiniGroup{Group: "default/db", Role: "router"}
+
+	tests := []struct {
+		pod        *v1.Pod
+		existing   []*v1.Pod
+		candidates []string
+		node       string
+		fits       bool
+		test       string
+	}{
+		{
+			pod:      groupPod("router-1", "", router),
+			existing: []*v1.Pod{groupPod("router-0", "node1", router)},
+			node:     "node1",
+			fits:     true,
+			test:     "role without spread",
+		},
+		{
+			pod:  groupPod("shard-0", "", shard),
+			node: "node1",
+			fits: true,
+			test: "first member",
+		},
+		{
+			pod:  groupPod("shard-0", "", shard),
+			node: "node4",
+			fits: false,
+			test: "node without the topology key",
+		},
+		{
+			pod:      groupPod("shard-1", "", shard),
+			existing: []*v1.Pod{groupPod("shard-0", "node1", shard)},
+			node:     "node2",
+			fits:     false,
+			test:     "skew exceeded within the domain",
+		},
+		{
+			pod:      groupPod("shard-1", "", shard),
+			existing: []*v1.Pod{groupPod("shard-0", "node1", shard), groupPod("router-0", "node3", router)},
+			node:     "node3",
+			fits:     true,
+			test:     "other roles are not counted",
+		},
+		{
+			pod:      groupPod("shard-1", "", skewed),
+			existing: []*v1.Pod{groupPod("shard-0", "node1", skewed)},
+			node:     "node2",
+			fits:     true,
+			test:     "within a larger max skew",
+		},
+		{
+			pod:      groupPod("shard-2", "", shard),
+			existing: []*v1.Pod{groupPod("shard-0", "node1", shard), groupPod("shard-1", "node3", shard)},
+			node:     "node2",
+			fits:     true,
+			test:     "domains of nodes with taints not tolerated are not counted",
+		},
+		{
+			pod:        groupPod("shard-1", "", shard),
+			existing:   []*v1.Pod{groupPod("shard-0", "node1", shard)},
+			candidates: []string{"node1", "node2"},
+			node:       "node2",
+			fits:       true,
+			test:       "only the domains of the candidate nodes are counted",
+		},
+	}
+	expectedFailureReasons := []algorithm.PredicateFailureReason{ErrRoleSpreadNotMatch}
+
+	for _, test := range tests {
+		nodeInfoMap := schedulercache.CreateNodeNameToInfoMap(test.existing, nodes)
+		meta := PredicateMetadata(test.pod, nodeInfoMap)
+		if test.candidates != nil {
+			var candidates []*v1.Node
+			for _, name := range test.candidates {
+				candidates = append(candidates, nodeInfoMap[name].Node())
+			}
+			meta.(algorithm.CandidateNodesMetadata).SetCandidateNodes(candidates)
+		}
+		fit := RoleSpreadChecker{}
+		fits, reasons, err := fit.CheckRoleSpread(test.pod, meta, nodeInfoMap[test.node])
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.test, err)
+		}
+		if !fits && !reflect.DeepEqual(reasons, expectedFailureReasons) {
+			t.Errorf("%s: unexpected failure reasons: %v, want: %v", test.test, reasons, expectedFailureReasons)
+		}
+		if fits != test.fits {
+			t.Errorf("%s: expected %v got %v", test.test, test.fits, fits)
+		}
+	}
+}
@@ -0,0 +1,140 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package priorities
+
+import (
+	"fmt"
+
+	"github.com/golang/glog"
+	"k8s.io/kubernetes/pkg/api/v1"
+	"k8s.io/kubernetes/plugin/pkg/scheduler/algorithm"
+	"k8s.io/kubernetes/plugin/pkg/scheduler/algorithm/predicates"
+	schedulerapi "k8s.io/kubernetes/plugin/pkg/scheduler/api"
+	"k8s.io/kubernetes/plugin/pkg/scheduler/schedulercache"
+)
+
+type GangFragmentation struct {
+	podLister  algorithm.PodLister
+	nodeLister algorithm.NodeLister
+}
+
+// NewGangFragmentationPriority creates a priority which prefers the placements of a gang
+// leaving the least capacity stranded, so that whole nodes stay free for later gangs.
+func NewGangFragmentationPriority(podLister algorithm.PodLister, nodeLister algorithm.NodeLister) (algorithm.PriorityMapFunction, algorithm.PriorityReduceFunction) {
+	gangFragmentation := &GangFragmentation{
+		podLister:  podLister,
+		nodeLister: nodeLister,
+	}
+	return gangFragmentation.CalculateGangFragmentationPriorityMap, nil
+}
+
+// CalculateGangFragmentationPriorityMap scores the node by what the gang leaves behind on it.
+// The members of the pod's role still to be placed are assumed to follow the pod on the node
+// as long as they fit, since they share the pod's requests. The score is the used fraction
+// of CPU, memory and GPU of the node after that, less the fraction of GPUs left without the
+// CPU or memory to use them, on a scale of 0-10. Pods which are not in a group are scored
+// on their own.
+func (g *GangFragmentation) CalculateGangFragmentationPriorityMap(pod *v1.Pod, meta interface{}, nodeInfo *schedulercache.NodeInfo) (schedulerapi.HostPriority, error) {
+	node := nodeInfo.Node()
+	if node == nil {
+		return schedulerapi.HostPriority{}, fmt.Errorf("node not found")
+	}
+
+	podGroup, members, err := getGroupMembers(pod, meta, g.podLister, g.nodeLister)
+	if err != nil {
+		return schedulerapi.HostPriority{}, err
+	}
+	remaining := 1
+	if podGroup != nil {
+		remaining = podGroup.MinReplicas
+		for _, member := range members {
+			if member.Role == podGroup.Role && !(member.Pod.Namespace == pod.Namespace && member.Pod.Name == pod.Name) {
+				remaining--
+			}
+		}
+		if remaining < 1 {
+			remaining = 1
+		}
+	}
+
+	allocatable := nodeInfo.AllocatableResource()
+	requested := nodeInfo.RequestedResource()
+	podRequest := predicates.GetResourceRequest(pod)
+
+	count := int64(1)
+	if remaining > 1 {
+		more := int64(remaining - 1)
+		more = fitCount(more, allocatable.MilliCPU-requested.MilliCPU-podRequest.MilliCPU, podRequest.MilliCPU)
+		more = fitCount(more, allocatable.Memory-requested.Memory-podRequest.Memory, podRequest.Memory)
+		more = fitCount(more, allocatable.NvidiaGPU-requested.NvidiaGPU-podRequest.NvidiaGPU, podRequest.NvidiaGPU)
+		count += more
+	}
+
+	cpuUsed := usedFraction(requested.MilliCPU+count*podRequest.MilliCPU, allocatable.MilliCPU)
+	memoryUsed := usedFraction(requested.Memory+count*podRequest.Memory, allocatable.Memory)
+	used := (cpuUsed + memoryUsed) / 2
+	stranded := 0.0
+	if allocatable.NvidiaGPU > 0 {
+		gpuUsed := usedFraction(requested.NvidiaGPU+count*podRequest.NvidiaGPU, allocatable.NvidiaGPU)
+		used = (cpuUsed + memoryUsed + gpuUsed) / 3
+		// GPUs are stranded when less CPU or memory than GPU is left.
+		if leftover := (1 - gpuUsed) - (1 - maxFloat(cpuUsed, memoryUsed)); leftover > 0 {
+			stranded = leftover
+		}
+	}
+
+	score := int(float64(schedulerapi.MaxPriority) * (used - stranded))
+	if score < 0 {
+		score = 0
+	}
+	if glog.V(10) {
+		glog.Infof("%v -> %v: GangFragmentationPriority, %d members, used %f, stranded %f, Score: (%d)",
+			pod.Name, node.Name, count, used, stranded, score)
+	}
+	return schedulerapi.HostPriority{
+		Host:  node.Name,
+		Score: score,
+	}, nil
+}
+
+// fitCount caps n to the number of requests which fit in free.
+func fitCount(n, free, request int64) int64 {
+	if request <= 0 {
+		return n
+	}
+	if free < 0 {
+		return 0
+	}
+	if fit := free / request; fit < n {
+		return fit
+	}
+	return n
+}
+
+func usedFraction(requested, capacity int64) float64 {
+	if capacity <= 0 || requested >= capacity {
+		return 1
+	}
+	return float64(requested) / float64(capacity)
+}
+
+func maxFloat(a, b float64) float64 {
+	if a > b {
+		return a
+	}
+	return b
+}
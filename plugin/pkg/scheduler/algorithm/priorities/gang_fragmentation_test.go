@@ -0,0 +1,101 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package priorities
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/kubernetes/pkg/api/v1"
+	schedulerapi "k8s.io/kubernetes/plugin/pkg/scheduler/api"
+	"k8s.io/kubernetes/plugin/pkg/scheduler/schedulercache"
+	schedulertesting "k8s.io/kubernetes/plugin/pkg/scheduler/testing"
+)
+
+func withRequests(pod *v1.Pod, milliCPU, memory, gpu int64) *v1.Pod {
+	requests := v1.ResourceList{
+		v1.ResourceCPU:    *resource.NewMilliQuantity(milliCPU, resource.DecimalSI),
+		v1.ResourceMemory: *resource.NewQuantity(memory, resource.BinarySI),
+	}
+	if gpu > 0 {
+		requests[v1.ResourceNewNvidiaGPU] = *resource.NewQuantity(gpu, resource.DecimalSI)
+	}
+	pod.Spec.Containers = []v1.Container{{Resources: v1.ResourceRequirements{Requests: requests}}}
+	return pod
+}
+
+func TestGangFragmentationPriority(t *testing.T) {
+	gpuNode := makeNode("gpu", 4000, 4000)
+	gpuNode.Status.Allocatable[v1.ResourceNewNvidiaGPU] = *resource.NewQuantity(4, resource.DecimalSI)
+	worker := schedulerapi.MiniGroup{Group: "default/job", Role: "worker", MinReplicas: 4}
+
+	tests := []struct {
+		pod          *v1.Pod
+		pods         []*v1.Pod
+		nodes        []*v1.Node
+		expectedList schedulerapi.HostPriorityList
+		test         string
+	}{
+		{
+			pod:          withRequests(&v1.Pod{}, 1000, 1000, 0),
+			pods:         []*v1.Pod{withRequests(&v1.Pod{Spec: v1.PodSpec{NodeName: "half"}}, 2000, 2000, 0)},
+			nodes:        []*v1.Node{makeNode("empty", 4000, 4000), makeNode("half", 4000, 4000)},
+			expectedList: []schedulerapi.HostPriority{{Host: "empty", Score: 2}, {Host: "half", Score: 7}},
+			test:         "pod without group packs onto the used node",
+		},
+		{
+			pod:          withRequests(groupPod("worker-0", "", worker), 1000, 1000, 0),
+			pods:         []*v1.Pod{withRequests(&v1.Pod{Spec: v1.PodSpec{NodeName: "half"}}, 2000, 2000, 0)},
+			nodes:        []*v1.Node{makeNode("empty", 4000, 4000), makeNode("half", 4000, 4000), makeNode("large", 8000, 8000)},
+			expectedList: []schedulerapi.HostPriority{{Host: "empty", Score: 10}, {Host: "half", Score: 10}, {Host: "large", Score: 5}},
+			test:         "gang fills whole nodes and keeps the large node free",
+		},
+		{
+			pod: withRequests(groupPod("worker-3", "", worker), 1000, 1000, 0),
+			pods: []*v1.Pod{
+				withRequests(groupPod("worker-0", "large", worker), 1000, 1000, 0),
+				withRequests(groupPod("worker-1", "large", worker), 1000, 1000, 0),
+				withRequests(groupPod("worker-2", "large", worker), 1000, 1000, 0),
+			},
+			nodes:        []*v1.Node{makeNode("empty", 4000, 4000), makeNode("large", 8000, 8000)},
+			expectedList: []schedulerapi.HostPriority{{Host: "empty", Score: 2}, {Host: "large", Score: 5}},
+			test:         "placed members are not expected to follow",
+		},
+		{
+			pod:          withRequests(&v1.Pod{}, 4000, 4000, 0),
+			nodes:        []*v1.Node{makeNode("cpu", 4000, 4000), gpuNode},
+			expectedList: []schedulerapi.HostPriority{{Host: "cpu", Score: 10}, {Host: "gpu", Score: 0}},
+			test:         "GPUs left without CPU are stranded",
+		},
+	}
+
+	for _, test := range tests {
+		nodeNameToInfo := schedulercache.CreateNodeNameToInfoMap(test.pods, test.nodes)
+		mapFn, reduceFn := NewGangFragmentationPriority(schedulertesting.FakePodLister(test.pods), schedulertesting.FakeNodeLister(test.nodes))
+		list, err := priorityFunction(mapFn, reduceFn)(test.pod, nodeNameToInfo, test.nodes)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.test, err)
+		}
+		sort.Sort(test.expectedList)
+		sort.Sort(list)
+		if !reflect.DeepEqual(test.expectedList, list) {
+			t.Errorf("%s: expected %#v, got %#v", test.test, test.expectedList, list)
+		}
+	}
+}
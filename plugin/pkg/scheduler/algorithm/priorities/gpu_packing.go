@@ -0,0 +1,72 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package priorities
+
+import (
+	"fmt"
+
+	"github.com/golang/glog"
+	"k8s.io/kubernetes/pkg/api/v1"
+	"k8s.io/kubernetes/plugin/pkg/scheduler/algorithm"
+	"k8s.io/kubernetes/plugin/pkg/scheduler/algorithm/predicates"
+	schedulerapi "k8s.io/kubernetes/plugin/pkg/scheduler/api"
+	"k8s.io/kubernetes/plugin/pkg/scheduler/schedulercache"
+)
+
+type GPUPacking struct {
+	demand algorithm.GPUDemandLister
+}
+
+// NewGPUPackingPriority creates a priority which packs GPU pods onto the nodes with the fewest
+// free GPUs, and keeps nodes with all GPUs free for the queued gangs needing more GPUs per pod.
+func NewGPUPackingPriority(demand algorithm.GPUDemandLister) (algorithm.PriorityMapFunction, algorithm.PriorityReduceFunction) {
+	gpuPacking := &GPUPacking{
+		demand: demand,
+	}
+	return gpuPacking.CalculateGPUPackingPriorityMap, nil
+}
+
+// CalculateGPUPackingPriorityMap scores the node by the fraction of its free GPUs the pod
+// consumes, on a scale of 0-10; a pod taking all remaining GPUs gets 10. A node with all of
+// its GPUs free gets 0 if a pending member of a scheduling group needs more GPUs than the
+// pod and would fit on the node. Pods without GPU requests get 0 everywhere.
+func (g *GPUPacking) CalculateGPUPackingPriorityMap(pod *v1.Pod, meta interface{}, nodeInfo *schedulercache.NodeInfo) (schedulerapi.HostPriority, error) {
+	node := nodeInfo.Node()
+	if node == nil {
+		return schedulerapi.HostPriority{}, fmt.Errorf("node not found")
+	}
+
+	request := predicates.GetResourceRequest(pod).NvidiaGPU
+	allocatable := nodeInfo.AllocatableResource().NvidiaGPU
+	free := allocatable - nodeInfo.RequestedResource().NvidiaGPU
+
+	score := 0
+	if request > 0 && free >= request {
+		score = int(int64(schedulerapi.MaxPriority) * request / free)
+		if free == allocatable && request < free {
+			if demand := g.demand.MaxPendingGPURequest(); demand > request && demand <= free {
+				glog.V(10).Infof("%v -> %v: GPUPackingPriority, keeping %d free GPUs for pending requests of %d",
+					pod.Name, node.Name, free, demand)
+				score = 0
+			}
+		}
+	}
+	return schedulerapi.HostPriority{
+		Host:  node.Name,
+		Score: score,
+	}, nil
+}
@@ -0,0 +1,82 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package priorities
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/kubernetes/pkg/api/v1"
+	schedulerapi "k8s.io/kubernetes/plugin/pkg/scheduler/api"
+	"k8s.io/kubernetes/plugin/pkg/scheduler/schedulercache"
+)
+
+type fakeGPUDemand int64
+
+func (f fakeGPUDemand) MaxPendingGPURequest() int64 {
+	return int64(f)
+}
+
+func makeGPUNode(node string, gpu int64) *v1.Node {
+	result := makeNode(node, 32000, 32000)
+	result.Status.Allocatable[v1.ResourceNewNvidiaGPU] = *resource.NewQuantity(gpu, resource.DecimalSI)
+	return result
+}
+
+func TestGPUPackingPriority(t *testing.T) {
+	nodes := []*v1.Node{makeGPUNode("free8", 8), makeGPUNode("used8", 8), makeGPUNode("free2", 2), makeNode("cpu", 32000, 32000)}
+	pods := []*v1.Pod{withRequests(&v1.Pod{Spec: v1.PodSpec{NodeName: "used8"}}, 1000, 1000, 6)}
+
+	tests := []struct {
+		pod          *v1.Pod
+		demand       int64
+		expectedList schedulerapi.HostPriorityList
+		test         string
+	}{
+		{
+			pod:          withRequests(&v1.Pod{}, 1000, 1000, 0),
+			expectedList: []schedulerapi.HostPriority{{Host: "free8", Score: 0}, {Host: "used8", Score: 0}, {Host: "free2", Score: 0}, {Host: "cpu", Score: 0}},
+			test:         "pod without GPUs",
+		},
+		{
+			pod:          withRequests(&v1.Pod{}, 1000, 1000, 2),
+			expectedList: []schedulerapi.HostPriority{{Host: "free8", Score: 2}, {Host: "used8", Score: 10}, {Host: "free2", Score: 10}, {Host: "cpu", Score: 0}},
+			test:         "nodes consumed entirely preferred",
+		},
+		{
+			pod:          withRequests(&v1.Pod{}, 1000, 1000, 1),
+			demand:       8,
+			expectedList: []schedulerapi.HostPriority{{Host: "free8", Score: 0}, {Host: "used8", Score: 5}, {Host: "free2", Score: 5}, {Host: "cpu", Score: 0}},
+			test:         "whole nodes kept for pending multi-GPU members",
+		},
+	}
+
+	for _, test := range tests {
+		nodeNameToInfo := schedulercache.CreateNodeNameToInfoMap(pods, nodes)
+		list, err := priorityFunction(NewGPUPackingPriority(fakeGPUDemand(test.demand)))(test.pod, nodeNameToInfo, nodes)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.test, err)
+		}
+		sort.Sort(test.expectedList)
+		sort.Sort(list)
+		if !reflect.DeepEqual(test.expectedList, list) {
+			t.Errorf("%s: expected %#v, got %#v", test.test, test.expectedList, list)
+		}
+	}
+}
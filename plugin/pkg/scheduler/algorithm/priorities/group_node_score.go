@@ -0,0 +1,67 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package priorities
+
+import (
+	"fmt"
+
+	"k8s.io/kubernetes/pkg/api/v1"
+	schedulerapi "k8s.io/kubernetes/plugin/pkg/scheduler/api"
+	"k8s.io/kubernetes/plugin/pkg/scheduler/schedulercache"
+	"k8s.io/kubernetes/plugin/pkg/scheduler/tools"
+)
+
+// GroupNodeScorePriorityMap scores the node by the weighted sum of the scores the group
+// extenders gave it for the group of the pod in the current attempt. Nodes which weren't
+// scored, and all nodes for pods which are not placed with their group, are scored 0.
+func GroupNodeScorePriorityMap(pod *v1.Pod, meta interface{}, nodeInfo *schedulercache.NodeInfo) (schedulerapi.HostPriority, error) {
+	node := nodeInfo.Node()
+	if node == nil {
+		return schedulerapi.HostPriority{}, fmt.Errorf("node not found")
+	}
+
+	var scores map[string]int
+	if priorityMeta, ok := meta.(*priorityMetadata); ok {
+		scores = priorityMeta.groupNodeScores
+	} else {
+		// We couldn't parse metadata - fallback to the annotation of the pod.
+		scores = tools.GetGroupNodeScores(pod)
+	}
+	return schedulerapi.HostPriority{
+		Host:  node.Name,
+		Score: scores[node.Name],
+	}, nil
+}
+
+// GroupNodeScorePriorityReduce scales the scores to the range 0-10, the nodes scored highest
+// for the group get 10.
+func GroupNodeScorePriorityReduce(pod *v1.Pod, meta interface{}, nodeNameToInfo map[string]*schedulercache.NodeInfo, result schedulerapi.HostPriorityList) error {
+	var maxScore int
+	for i := range result {
+		if result[i].Score > maxScore {
+			maxScore = result[i].Score
+		}
+	}
+	for i := range result {
+		if maxScore > 0 {
+			result[i].Score = schedulerapi.MaxPriority * result[i].Score / maxScore
+		} else {
+			result[i].Score = 0
+		}
+	}
+	return nil
+}
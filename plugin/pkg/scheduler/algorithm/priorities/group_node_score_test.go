@@ -0,0 +1,76 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package priorities
+
+import (
+	"reflect"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kubernetes/pkg/api/v1"
+	schedulerapi "k8s.io/kubernetes/plugin/pkg/scheduler/api"
+	"k8s.io/kubernetes/plugin/pkg/scheduler/schedulercache"
+	"k8s.io/kubernetes/plugin/pkg/scheduler/tools"
+)
+
+func TestGroupNodeScorePriority(t *testing.T) {
+	nodes := []*v1.Node{
+		{ObjectMeta: metav1.ObjectMeta{Name: "machine1"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "machine2"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "machine3"}},
+	}
+	pod := &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "worker-0", Namespace: "bar"}}
+
+	tests := []struct {
+		pod          *v1.Pod
+		expectedList schedulerapi.HostPriorityList
+		test         string
+	}{
+		{
+			pod:          pod,
+			expectedList: []schedulerapi.HostPriority{{Host: "machine1", Score: 0}, {Host: "machine2", Score: 0}, {Host: "machine3", Score: 0}},
+			test:         "nodes are not scored without scores of the group",
+		},
+		{
+			pod:          tools.WithGroupNodeScores(pod, map[string]int{"machine1": 30, "machine3": 60}),
+			expectedList: []schedulerapi.HostPriority{{Host: "machine1", Score: 5}, {Host: "machine2", Score: 0}, {Host: "machine3", Score: 10}},
+			test:         "the node scored highest for the group gets the highest score",
+		},
+	}
+
+	for _, test := range tests {
+		nodeNameToInfo := schedulercache.CreateNodeNameToInfoMap(nil, nodes)
+		list, err := priorityFunction(GroupNodeScorePriorityMap, GroupNodeScorePriorityReduce)(test.pod, nodeNameToInfo, nodes)
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+		if !reflect.DeepEqual(test.expectedList, list) {
+			t.Errorf("%s: expected %#v, got %#v", test.test, test.expectedList, list)
+		}
+
+		meta := PriorityMetadata(test.pod, nodeNameToInfo)
+		for i, node := range nodes {
+			hostPriority, err := GroupNodeScorePriorityMap(test.pod, meta, nodeNameToInfo[node.Name])
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if scores := tools.GetGroupNodeScores(test.pod); hostPriority.Score != scores[node.Name] {
+				t.Errorf("%s: expected the score of node %d from the metadata to be %d, got %d", test.test, i, scores[node.Name], hostPriority.Score)
+			}
+		}
+	}
+}
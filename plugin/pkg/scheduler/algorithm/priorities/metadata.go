@@ -17,8 +17,13 @@ limitations under the License.
 package priorities
 
 import (
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/kubernetes/pkg/api/v1"
+	"k8s.io/kubernetes/plugin/pkg/scheduler/algorithm"
+	schedulerapi "k8s.io/kubernetes/plugin/pkg/scheduler/api"
 	"k8s.io/kubernetes/plugin/pkg/scheduler/schedulercache"
+	"k8s.io/kubernetes/plugin/pkg/scheduler/tools"
 )
 
 // priorityMetadata is a type that is passed as metadata for priority functions
@@ -26,6 +31,14 @@ type priorityMetadata struct {
 	nonZeroRequest *schedulercache.Resource
 	podTolerations []v1.Toleration
 	affinity       *v1.Affinity
+	podGroup       *schedulerapi.MiniGroup
+	// groupMembers are the members of the pod's group on the nodes, including the ones
+	// assumed earlier in the current gang attempt.
+	groupMembers []tools.GroupMember
+	// dependencyNodes are the nodes of the members of the roles the pod's role depends on.
+	dependencyNodes sets.String
+	// groupNodeScores are the scores the group extenders gave the nodes for the pod's group.
+	groupNodeScores map[string]int
 }
 
 // PriorityMetadata is a MetadataProducer.  Node info can be nil.
@@ -35,9 +48,63 @@ func PriorityMetadata(pod *v1.Pod, nodeNameToInfo map[string]*schedulercache.Nod
 		return nil
 	}
 	tolerationsPreferNoSchedule := getAllTolerationPreferNoSchedule(pod.Spec.Tolerations)
-	return &priorityMetadata{
-		nonZeroRequest: getNonZeroRequests(pod),
-		podTolerations: tolerationsPreferNoSchedule,
-		affinity:       schedulercache.ReconcileAffinity(pod),
+	meta := &priorityMetadata{
+		nonZeroRequest:  getNonZeroRequests(pod),
+		podTolerations:  tolerationsPreferNoSchedule,
+		affinity:        schedulercache.ReconcileAffinity(pod),
+		groupNodeScores: tools.GetGroupNodeScores(pod),
 	}
+	if _, ok := pod.Annotations[tools.SchedulingGroup]; ok {
+		meta.podGroup = tools.GetSchedulingGroup(pod)
+		if meta.podGroup != nil {
+			meta.groupMembers = tools.GetGroupMembers(meta.podGroup.Group, nodeNameToInfo)
+			meta.dependencyNodes = getDependencyNodes(meta.podGroup, meta.groupMembers)
+		}
+	}
+	return meta
+}
+
+// getDependencyNodes returns the names of the nodes the members of the roles podGroup depends
+// on are placed on, or nil if the role has no dependencies.
+func getDependencyNodes(podGroup *schedulerapi.MiniGroup, members []tools.GroupMember) sets.String {
+	if len(podGroup.DependsOn) == 0 {
+		return nil
+	}
+	roles := sets.NewString()
+	for _, dep := range podGroup.DependsOn {
+		roles.Insert(dep.Role)
+	}
+	nodes := sets.NewString()
+	for _, member := range members {
+		if roles.Has(member.Role) {
+			nodes.Insert(member.Node.Name)
+		}
+	}
+	return nodes
+}
+
+// getGroupMembers returns the scheduling group of the pod and the members of that group
+// already placed, taken from the metadata if possible. The group is nil for pods which are
+// not annotated.
+func getGroupMembers(pod *v1.Pod, meta interface{}, podLister algorithm.PodLister, nodeLister algorithm.NodeLister) (*schedulerapi.MiniGroup, []tools.GroupMember, error) {
+	if priorityMeta, ok := meta.(*priorityMetadata); ok {
+		return priorityMeta.podGroup, priorityMeta.groupMembers, nil
+	}
+	// We couldn't parse metadata - fallback to computing it.
+	if _, ok := pod.Annotations[tools.SchedulingGroup]; !ok {
+		return nil, nil, nil
+	}
+	podGroup := tools.GetSchedulingGroup(pod)
+	if podGroup == nil {
+		return nil, nil, nil
+	}
+	pods, err := podLister.List(labels.Everything())
+	if err != nil {
+		return nil, nil, err
+	}
+	nodes, err := nodeLister.List()
+	if err != nil {
+		return nil, nil, err
+	}
+	return podGroup, tools.GetGroupMembers(podGroup.Group, schedulercache.CreateNodeNameToInfoMap(pods, nodes)), nil
 }
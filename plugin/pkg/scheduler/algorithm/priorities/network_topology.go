@@ -0,0 +1,116 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package priorities
+
+import (
+	"fmt"
+
+	"github.com/golang/glog"
+	"k8s.io/kubernetes/pkg/api/v1"
+	"k8s.io/kubernetes/plugin/pkg/scheduler/algorithm"
+	schedulerapi "k8s.io/kubernetes/plugin/pkg/scheduler/api"
+	"k8s.io/kubernetes/plugin/pkg/scheduler/schedulercache"
+)
+
+type NetworkTopologyPrioritizer struct {
+	levels     []schedulerapi.NetworkTopologyLevel
+	podLister  algorithm.PodLister
+	nodeLister algorithm.NodeLister
+}
+
+// NewNetworkTopologyPriority creates a priority which scores nodes by their distance to the
+// members of the pod's scheduling group in the hierarchy given by levels, outermost first.
+func NewNetworkTopologyPriority(podLister algorithm.PodLister, nodeLister algorithm.NodeLister, levels []schedulerapi.NetworkTopologyLevel) (algorithm.PriorityMapFunction, algorithm.PriorityReduceFunction) {
+	prioritizer := &NetworkTopologyPrioritizer{
+		levels:     levels,
+		podLister:  podLister,
+		nodeLister: nodeLister,
+	}
+	return prioritizer.CalculateNetworkTopologyPriorityMap, CalculateNetworkTopologyPriorityReduce
+}
+
+// CalculateNetworkTopologyPriorityMap sums the closeness of the node to every member of the
+// pod's group already placed, including the members assumed in the current gang attempt.
+// The closeness to a member is the sum of the weights of the levels, from the outermost
+// down, at which the node and the member share a domain; the member's own node shares all
+// of them. Pods which are not in a group get 0.
+func (n *NetworkTopologyPrioritizer) CalculateNetworkTopologyPriorityMap(pod *v1.Pod, meta interface{}, nodeInfo *schedulercache.NodeInfo) (schedulerapi.HostPriority, error) {
+	node := nodeInfo.Node()
+	if node == nil {
+		return schedulerapi.HostPriority{}, fmt.Errorf("node not found")
+	}
+
+	podGroup, members, err := getGroupMembers(pod, meta, n.podLister, n.nodeLister)
+	if err != nil {
+		return schedulerapi.HostPriority{}, err
+	}
+
+	score := 0
+	if podGroup != nil {
+		for _, member := range members {
+			if member.Pod.Namespace == pod.Namespace && member.Pod.Name == pod.Name {
+				continue
+			}
+			score += n.closeness(node, member.Node)
+		}
+	}
+	return schedulerapi.HostPriority{
+		Host:  node.Name,
+		Score: score,
+	}, nil
+}
+
+func (n *NetworkTopologyPrioritizer) closeness(node, other *v1.Node) int {
+	sameNode := node.Name == other.Name
+	result := 0
+	for _, level := range n.levels {
+		if !sameNode {
+			value, ok := node.Labels[level.Label]
+			if !ok || value != other.Labels[level.Label] {
+				break
+			}
+		}
+		result += level.Weight
+	}
+	return result
+}
+
+// CalculateNetworkTopologyPriorityReduce scales the scores so that the closest node gets
+// MaxPriority.
+func CalculateNetworkTopologyPriorityReduce(pod *v1.Pod, meta interface{}, nodeNameToInfo map[string]*schedulercache.NodeInfo, result schedulerapi.HostPriorityList) error {
+	var maxCount int
+	for i := range result {
+		if result[i].Score > maxCount {
+			maxCount = result[i].Score
+		}
+	}
+	maxCountFloat := float64(maxCount)
+
+	var fScore float64
+	for i := range result {
+		if maxCount > 0 {
+			fScore = float64(schedulerapi.MaxPriority) * (float64(result[i].Score) / maxCountFloat)
+		} else {
+			fScore = 0
+		}
+		if glog.V(10) {
+			glog.Infof("%v -> %v: NetworkTopologyPriority, Score: (%d)", pod.Name, result[i].Host, int(fScore))
+		}
+		result[i].Score = int(fScore)
+	}
+	return nil
+}
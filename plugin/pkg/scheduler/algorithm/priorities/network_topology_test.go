@@ -0,0 +1,101 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package priorities
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kubernetes/pkg/api/v1"
+	schedulerapi "k8s.io/kubernetes/plugin/pkg/scheduler/api"
+	"k8s.io/kubernetes/plugin/pkg/scheduler/schedulercache"
+	schedulertesting "k8s.io/kubernetes/plugin/pkg/scheduler/testing"
+	"k8s.io/kubernetes/plugin/pkg/scheduler/tools"
+)
+
+func groupPod(name, nodeName string, miniGroup schedulerapi.MiniGroup) *v1.Pod {
+	data, _ := json.Marshal(miniGroup)
+	return &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   "default",
+			Annotations: map[string]string{tools.SchedulingGroup: string(data)},
+		},
+		Spec: v1.PodSpec{NodeName: nodeName},
+	}
+}
+
+func TestNetworkTopologyPriority(t *testing.T) {
+	nodes := []*v1.Node{
+		{ObjectMeta: metav1.ObjectMeta{Name: "machine1", Labels: map[string]string{"zone": "z1", "rack": "r1"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "machine2", Labels: map[string]string{"zone": "z1", "rack": "r1"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "machine3", Labels: map[string]string{"zone": "z1", "rack": "r2"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "machine4", Labels: map[string]string{"zone": "z2", "rack": "r1"}}},
+	}
+	levels := []schedulerapi.NetworkTopologyLevel{{Label: "zone", Weight: 1}, {Label: "rack", Weight: 2}}
+	worker := schedulerapi.MiniGroup{Group: "default/job", Role: "worker"}
+	otherGroup := schedulerapi.MiniGroup{Group: "default/other", Role: "worker"}
+
+	tests := []struct {
+		pod          *v1.Pod
+		pods         []*v1.Pod
+		expectedList schedulerapi.HostPriorityList
+		test         string
+	}{
+		{
+			pod:          &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod", Namespace: "default"}},
+			pods:         []*v1.Pod{groupPod("worker-0", "machine1", worker)},
+			expectedList: []schedulerapi.HostPriority{{Host: "machine1", Score: 0}, {Host: "machine2", Score: 0}, {Host: "machine3", Score: 0}, {Host: "machine4", Score: 0}},
+			test:         "pod not in a group",
+		},
+		{
+			pod:          groupPod("worker-1", "", worker),
+			pods:         []*v1.Pod{groupPod("worker-0", "machine1", otherGroup)},
+			expectedList: []schedulerapi.HostPriority{{Host: "machine1", Score: 0}, {Host: "machine2", Score: 0}, {Host: "machine3", Score: 0}, {Host: "machine4", Score: 0}},
+			test:         "members of other groups are ignored",
+		},
+		{
+			pod:          groupPod("worker-1", "", worker),
+			pods:         []*v1.Pod{groupPod("worker-0", "machine1", worker)},
+			expectedList: []schedulerapi.HostPriority{{Host: "machine1", Score: 10}, {Host: "machine2", Score: 10}, {Host: "machine3", Score: 3}, {Host: "machine4", Score: 0}},
+			test:         "outer levels must match for inner levels to count",
+		},
+		{
+			pod:          groupPod("worker-2", "", worker),
+			pods:         []*v1.Pod{groupPod("worker-0", "machine3", worker), groupPod("worker-1", "machine4", worker)},
+			expectedList: []schedulerapi.HostPriority{{Host: "machine1", Score: 3}, {Host: "machine2", Score: 3}, {Host: "machine3", Score: 10}, {Host: "machine4", Score: 10}},
+			test:         "closeness to all members is summed",
+		},
+	}
+
+	for _, test := range tests {
+		nodeNameToInfo := schedulercache.CreateNodeNameToInfoMap(test.pods, nodes)
+		mapFn, reduceFn := NewNetworkTopologyPriority(schedulertesting.FakePodLister(test.pods), schedulertesting.FakeNodeLister(nodes), levels)
+		list, err := priorityFunction(mapFn, reduceFn)(test.pod, nodeNameToInfo, nodes)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.test, err)
+		}
+		sort.Sort(test.expectedList)
+		sort.Sort(list)
+		if !reflect.DeepEqual(test.expectedList, list) {
+			t.Errorf("%s: expected %#v, got %#v", test.test, test.expectedList, list)
+		}
+	}
+}
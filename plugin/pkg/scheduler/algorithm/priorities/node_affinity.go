@@ -27,11 +27,16 @@ import (
 	"k8s.io/kubernetes/plugin/pkg/scheduler/schedulercache"
 )
 
+// dependencyHintWeight is added to the score of the nodes the roles a pod depends on are
+// placed on, as if the pod preferred them through a node affinity term of that weight.
+const dependencyHintWeight = 100
+
 // CalculateNodeAffinityPriority prioritizes nodes according to node affinity scheduling preferences
 // indicated in PreferredDuringSchedulingIgnoredDuringExecution. Each time a node match a preferredSchedulingTerm,
 // it will a get an add of preferredSchedulingTerm.Weight. Thus, the more preferredSchedulingTerms
 // the node satisfies and the more the preferredSchedulingTerm that is satisfied weights, the higher
-// score the node gets.
+// score the node gets. A member of a scheduling group gets dependencyHintWeight on the nodes the
+// roles it depends on are placed on.
 func CalculateNodeAffinityPriorityMap(pod *v1.Pod, meta interface{}, nodeInfo *schedulercache.NodeInfo) (schedulerapi.HostPriority, error) {
 	node := nodeInfo.Node()
 	if node == nil {
@@ -39,7 +44,8 @@ func CalculateNodeAffinityPriorityMap(pod *v1.Pod, meta interface{}, nodeInfo *s
 	}
 
 	var affinity *v1.Affinity
-	if priorityMeta, ok := meta.(*priorityMetadata); ok {
+	priorityMeta, ok := meta.(*priorityMetadata)
+	if ok {
 		affinity = priorityMeta.affinity
 	} else {
 		// We couldn't parse metadata - fallback to the podspec.
@@ -68,6 +74,9 @@ func CalculateNodeAffinityPriorityMap(pod *v1.Pod, meta interface{}, nodeInfo *s
 			}
 		}
 	}
+	if ok && priorityMeta.dependencyNodes.Has(node.Name) {
+		count += dependencyHintWeight
+	}
 
 	return schedulerapi.HostPriority{
 		Host:  node.Name,
@@ -23,6 +23,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	utilfeature "k8s.io/apiserver/pkg/util/feature"
 	"k8s.io/kubernetes/pkg/api/v1"
+	kubeletapis "k8s.io/kubernetes/pkg/kubelet/apis"
 	schedulerapi "k8s.io/kubernetes/plugin/pkg/scheduler/api"
 	"k8s.io/kubernetes/plugin/pkg/scheduler/schedulercache"
 )
@@ -179,6 +180,68 @@ func TestNodeAffinityPriority(t *testing.T) {
 	}
 }
 
+// TestNodeAffinityPriorityDependencyHint tests that the nodes the roles a member depends on are
+// placed on are preferred by their name, whatever their hostname label is.
+func TestNodeAffinityPriorityDependencyHint(t *testing.T) {
+	nodes := []*v1.Node{
+		{ObjectMeta: metav1.ObjectMeta{Name: "machine1", Labels: map[string]string{kubeletapis.LabelHostname: "host-a"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "machine2", Labels: map[string]string{kubeletapis.LabelHostname: "host-b"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "machine3", Labels: map[string]string{kubeletapis.LabelHostname: "host-c"}}},
+	}
+	ps := schedulerapi.MiniGroup{Group: "default/job", Role: "ps"}
+	worker := schedulerapi.MiniGroup{
+		Group:     "default/job",
+		Role:      "worker",
+		DependsOn: []schedulerapi.RoleDependency{{Role: "ps", Type: schedulerapi.DependencyScheduled}},
+	}
+	evaluator := schedulerapi.MiniGroup{Group: "default/job", Role: "evaluator"}
+
+	tests := []struct {
+		pod          *v1.Pod
+		pods         []*v1.Pod
+		expectedList schedulerapi.HostPriorityList
+		test         string
+	}{
+		{
+			pod:          groupPod("worker-0", "", worker),
+			pods:         []*v1.Pod{groupPod("ps-0", "machine2", ps), groupPod("evaluator-0", "machine3", evaluator)},
+			expectedList: []schedulerapi.HostPriority{{Host: "machine1", Score: 0}, {Host: "machine2", Score: 10}, {Host: "machine3", Score: 0}},
+			test:         "node of the role depended on preferred",
+		},
+		{
+			pod:          groupPod("worker-0", "", worker),
+			pods:         []*v1.Pod{groupPod("evaluator-0", "machine3", evaluator)},
+			expectedList: []schedulerapi.HostPriority{{Host: "machine1", Score: 0}, {Host: "machine2", Score: 0}, {Host: "machine3", Score: 0}},
+			test:         "role depended on not placed yet",
+		},
+		{
+			pod:          groupPod("evaluator-1", "", evaluator),
+			pods:         []*v1.Pod{groupPod("ps-0", "machine2", ps)},
+			expectedList: []schedulerapi.HostPriority{{Host: "machine1", Score: 0}, {Host: "machine2", Score: 0}, {Host: "machine3", Score: 0}},
+			test:         "role without dependencies",
+		},
+	}
+
+	for _, test := range tests {
+		nodeNameToInfo := schedulercache.CreateNodeNameToInfoMap(test.pods, nodes)
+		meta := PriorityMetadata(test.pod, nodeNameToInfo)
+		list := make(schedulerapi.HostPriorityList, 0, len(nodes))
+		for _, node := range nodes {
+			hostPriority, err := CalculateNodeAffinityPriorityMap(test.pod, meta, nodeNameToInfo[node.Name])
+			if err != nil {
+				t.Fatalf("%s: unexpected error: %v", test.test, err)
+			}
+			list = append(list, hostPriority)
+		}
+		if err := CalculateNodeAffinityPriorityReduce(test.pod, meta, nodeNameToInfo, list); err != nil {
+			t.Fatalf("%s: unexpected error: %v", test.test, err)
+		}
+		if !reflect.DeepEqual(test.expectedList, list) {
+			t.Errorf("%s: expected %#v, got %#v", test.test, test.expectedList, list)
+		}
+	}
+}
+
 // TODO: remove when alpha support for affinity is removed
 func TestNodeAffinityAnnotationsPriority(t *testing.T) {
 	utilfeature.DefaultFeatureGate.Set("AffinityInAnnotations=true")
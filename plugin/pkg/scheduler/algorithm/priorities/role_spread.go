@@ -0,0 +1,108 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package priorities
+
+import (
+	"fmt"
+
+	"github.com/golang/glog"
+	"k8s.io/kubernetes/pkg/api/v1"
+	"k8s.io/kubernetes/plugin/pkg/scheduler/algorithm"
+	schedulerapi "k8s.io/kubernetes/plugin/pkg/scheduler/api"
+	"k8s.io/kubernetes/plugin/pkg/scheduler/schedulercache"
+	"k8s.io/kubernetes/plugin/pkg/scheduler/tools"
+)
+
+type RoleSpread struct {
+	podLister  algorithm.PodLister
+	nodeLister algorithm.NodeLister
+}
+
+// NewRoleSpreadPriority creates a priority which prefers the topology domains with the
+// fewest members of the pod's role, for roles declaring MiniGroup.Spread.
+func NewRoleSpreadPriority(podLister algorithm.PodLister, nodeLister algorithm.NodeLister) (algorithm.PriorityMapFunction, algorithm.PriorityReduceFunction) {
+	roleSpread := &RoleSpread{
+		podLister:  podLister,
+		nodeLister: nodeLister,
+	}
+	return roleSpread.CalculateRoleSpreadPriorityMap, roleSpread.CalculateRoleSpreadPriorityReduce
+}
+
+// CalculateRoleSpreadPriorityMap counts the members of the pod's role, assumed or running,
+// in the domain of the node.
+func (r *RoleSpread) CalculateRoleSpreadPriorityMap(pod *v1.Pod, meta interface{}, nodeInfo *schedulercache.NodeInfo) (schedulerapi.HostPriority, error) {
+	node := nodeInfo.Node()
+	if node == nil {
+		return schedulerapi.HostPriority{}, fmt.Errorf("node not found")
+	}
+
+	podGroup, members, err := getGroupMembers(pod, meta, r.podLister, r.nodeLister)
+	if err != nil {
+		return schedulerapi.HostPriority{}, err
+	}
+
+	count := 0
+	if podGroup != nil && podGroup.Spread != nil {
+		if domain, ok := node.Labels[podGroup.Spread.TopologyKey]; ok {
+			count = tools.CountRoleMembers(pod, members, podGroup.Role, podGroup.Spread.TopologyKey, nil)[domain]
+		}
+	}
+	return schedulerapi.HostPriority{
+		Host:  node.Name,
+		Score: count,
+	}, nil
+}
+
+// CalculateRoleSpreadPriorityReduce gives MaxPriority to the nodes in the domains with the
+// fewest members and less to the others in proportion. Nodes without the topology key get 0.
+func (r *RoleSpread) CalculateRoleSpreadPriorityReduce(pod *v1.Pod, meta interface{}, nodeNameToInfo map[string]*schedulercache.NodeInfo, result schedulerapi.HostPriorityList) error {
+	var podGroup *schedulerapi.MiniGroup
+	if priorityMeta, ok := meta.(*priorityMetadata); ok {
+		podGroup = priorityMeta.podGroup
+	} else if _, ok := pod.Annotations[tools.SchedulingGroup]; ok {
+		podGroup = tools.GetSchedulingGroup(pod)
+	}
+	if podGroup == nil || podGroup.Spread == nil {
+		return nil
+	}
+	key := podGroup.Spread.TopologyKey
+
+	var maxCount int
+	for i := range result {
+		if result[i].Score > maxCount {
+			maxCount = result[i].Score
+		}
+	}
+	maxCountFloat := float64(maxCount)
+
+	for i := range result {
+		fScore := float64(schedulerapi.MaxPriority)
+		if maxCount > 0 {
+			fScore = float64(schedulerapi.MaxPriority) * ((maxCountFloat - float64(result[i].Score)) / maxCountFloat)
+		}
+		if info, ok := nodeNameToInfo[result[i].Host]; !ok || info.Node() == nil {
+			fScore = 0
+		} else if _, ok := info.Node().Labels[key]; !ok {
+			fScore = 0
+		}
+		if glog.V(10) {
+			glog.Infof("%v -> %v: RoleSpreadPriority, Score: (%d)", pod.Name, result[i].Host, int(fScore))
+		}
+		result[i].Score = int(fScore)
+	}
+	return nil
+}
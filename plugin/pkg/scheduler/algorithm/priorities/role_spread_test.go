@@ -0,0 +1,85 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package priorities
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kubernetes/pkg/api/v1"
+	schedulerapi "k8s.io/kubernetes/plugin/pkg/scheduler/api"
+	"k8s.io/kubernetes/plugin/pkg/scheduler/schedulercache"
+	schedulertesting "k8s.io/kubernetes/plugin/pkg/scheduler/testing"
+)
+
+func TestRoleSpreadPriority(t *testing.T) {
+	nodes := []*v1.Node{
+		{ObjectMeta: metav1.ObjectMeta{Name: "machine1", Labels: map[string]string{"zone": "z1"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "machine2", Labels: map[string]string{"zone": "z1"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "machine3", Labels: map[string]string{"zone": "z2"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "machine4"}},
+	}
+	shard := schedulerapi.MiniGroup{Group: "default/db", Role: "shard", Spread: &schedulerapi.RoleSpread{TopologyKey: "zone", MaxSkew: 2}}
+	router := schedulerapi.MiniGroup{Group: "default/db", Role: "router"}
+
+	tests := []struct {
+		pod          *v1.Pod
+		pods         []*v1.Pod
+		expectedList schedulerapi.HostPriorityList
+		test         string
+	}{
+		{
+			pod:          groupPod("router-1", "", router),
+			pods:         []*v1.Pod{groupPod("router-0", "machine1", router)},
+			expectedList: []schedulerapi.HostPriority{{Host: "machine1", Score: 0}, {Host: "machine2", Score: 0}, {Host: "machine3", Score: 0}, {Host: "machine4", Score: 0}},
+			test:         "role without spread",
+		},
+		{
+			pod:          groupPod("shard-0", "", shard),
+			expectedList: []schedulerapi.HostPriority{{Host: "machine1", Score: 10}, {Host: "machine2", Score: 10}, {Host: "machine3", Score: 10}, {Host: "machine4", Score: 0}},
+			test:         "no members placed",
+		},
+		{
+			pod:          groupPod("shard-2", "", shard),
+			pods:         []*v1.Pod{groupPod("shard-0", "machine1", shard), groupPod("shard-1", "machine3", shard), groupPod("router-0", "machine3", router)},
+			expectedList: []schedulerapi.HostPriority{{Host: "machine1", Score: 0}, {Host: "machine2", Score: 0}, {Host: "machine3", Score: 0}, {Host: "machine4", Score: 0}},
+			test:         "domains evenly used",
+		},
+		{
+			pod:          groupPod("shard-3", "", shard),
+			pods:         []*v1.Pod{groupPod("shard-0", "machine1", shard), groupPod("shard-1", "machine2", shard), groupPod("shard-2", "machine3", shard)},
+			expectedList: []schedulerapi.HostPriority{{Host: "machine1", Score: 0}, {Host: "machine2", Score: 0}, {Host: "machine3", Score: 5}, {Host: "machine4", Score: 0}},
+			test:         "least used domain preferred",
+		},
+	}
+
+	for _, test := range tests {
+		nodeNameToInfo := schedulercache.CreateNodeNameToInfoMap(test.pods, nodes)
+		mapFn, reduceFn := NewRoleSpreadPriority(schedulertesting.FakePodLister(test.pods), schedulertesting.FakeNodeLister(nodes))
+		list, err := priorityFunction(mapFn, reduceFn)(test.pod, nodeNameToInfo, nodes)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.test, err)
+		}
+		sort.Sort(test.expectedList)
+		sort.Sort(list)
+		if !reflect.DeepEqual(test.expectedList, list) {
+			t.Errorf("%s: expected %#v, got %#v", test.test, test.expectedList, list)
+		}
+	}
+}
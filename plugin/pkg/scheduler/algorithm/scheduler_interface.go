@@ -41,6 +41,34 @@ type SchedulerExtender interface {
 
 	// IsBinder returns whether this extender is configured for the Bind method.
 	IsBinder() bool
+
+	// IsInterested returns whether this extender is to be called for the pod. The
+	// scheduling of the pods it is not interested in is left to Kubernetes.
+	IsInterested(pod *v1.Pod) bool
+}
+
+// GroupExtender is an interface for external processes to influence the placement of whole
+// scheduling groups. This is typically needed for resources allocated to a gang as a whole,
+// like network fabric or licenses.
+type GroupExtender interface {
+	// GroupPrioritize scores the nodes for the group as a whole. The returned scores & weight
+	// are used to make the members of the group prefer the nodes scored higher.
+	GroupPrioritize(group *schedulerapi.SchedulingGroup, nodes []*v1.Node) (hostPriorities *schedulerapi.HostPriorityList, weight int, err error)
+
+	// GroupFilter is called with the nodes proposed for the members of the group before they
+	// are bound. It returns the assignments overriding the proposed ones, if any, or an error
+	// if the placement is rejected.
+	GroupFilter(group *schedulerapi.SchedulingGroup, assignments []schedulerapi.ExtenderPodAssignment) ([]schedulerapi.ExtenderPodAssignment, error)
+
+	// GroupBind delegates the action of binding all members of the group to the extender.
+	GroupBind(group *schedulerapi.SchedulingGroup, assignments []schedulerapi.ExtenderPodAssignment) error
+
+	// IsGroupBinder returns whether this extender is configured for the GroupBind method.
+	IsGroupBinder() bool
+
+	// IsInterested returns whether this extender is to be called for the pod. A group is
+	// only sent to the extender if it is interested in at least one of its members.
+	IsInterested(pod *v1.Pod) bool
 }
 
 // ScheduleAlgorithm is an interface implemented by things that know how to schedule pods
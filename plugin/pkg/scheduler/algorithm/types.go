@@ -49,6 +49,8 @@ type MetadataProducer func(pod *v1.Pod, nodeNameToInfo map[string]*schedulercach
 type PriorityFunction func(pod *v1.Pod, nodeNameToInfo map[string]*schedulercache.NodeInfo, nodes []*v1.Node) (schedulerapi.HostPriorityList, error)
 
 type PriorityConfig struct {
+	// Name is the name the priority function is registered with.
+	Name   string
 	Map    PriorityMapFunction
 	Reduce PriorityReduceFunction
 	// TODO: Remove it after migrating all functions to
@@ -57,6 +59,12 @@ type PriorityConfig struct {
 	Weight   int
 }
 
+// CandidateNodesMetadata is implemented by predicate metadata which depends on the nodes a
+// pod is scheduled among, rather than on all nodes. The nodes are passed once listed.
+type CandidateNodesMetadata interface {
+	SetCandidateNodes(nodes []*v1.Node)
+}
+
 // EmptyMetadataProducer returns a no-op MetadataProducer type.
 func EmptyMetadataProducer(pod *v1.Pod, nodeNameToInfo map[string]*schedulercache.NodeInfo) interface{} {
 	return nil
@@ -144,3 +152,20 @@ type EmptyStatefulSetLister struct{}
 func (f EmptyStatefulSetLister) GetPodStatefulSets(pod *v1.Pod) (sss []*apps.StatefulSet, err error) {
 	return nil, nil
 }
+
+// GPUDemandLister interface represents anything that can report the GPU requests of the scheduling groups
+// waiting to be scheduled; it is consumed by a scheduler.
+type GPUDemandLister interface {
+	// Gets the largest GPU request of a single pending member of a scheduling group, 0 if there is none.
+	MaxPendingGPURequest() int64
+}
+
+var _ GPUDemandLister = &EmptyGPUDemandLister{}
+
+// EmptyGPUDemandLister implements GPUDemandLister reporting no demand.
+type EmptyGPUDemandLister struct{}
+
+// MaxPendingGPURequest of EmptyGPUDemandLister returns 0.
+func (f EmptyGPUDemandLister) MaxPendingGPURequest() int64 {
+	return 0
+}
@@ -27,8 +27,10 @@ import (
 	"k8s.io/kubernetes/plugin/pkg/scheduler/algorithm"
 	"k8s.io/kubernetes/plugin/pkg/scheduler/algorithm/predicates"
 	"k8s.io/kubernetes/plugin/pkg/scheduler/algorithm/priorities"
+	schedulerapi "k8s.io/kubernetes/plugin/pkg/scheduler/api"
 	"k8s.io/kubernetes/plugin/pkg/scheduler/core"
 	"k8s.io/kubernetes/plugin/pkg/scheduler/factory"
+	"k8s.io/kubernetes/plugin/pkg/scheduler/tools"
 
 	"github.com/golang/glog"
 )
@@ -115,6 +117,20 @@ func init() {
 	factory.RegisterPriorityFunction2("ImageLocalityPriority", priorities.ImageLocalityPriorityMap, nil, 1)
 	// Optional, cluster-autoscaler friendly priority function - give used nodes higher priority.
 	factory.RegisterPriorityFunction2("MostRequestedPriority", priorities.MostRequestedPriorityMap, nil, 1)
+	// Optional, gang friendly priority function - give higher priority to the nodes the gang leaves
+	// the least fragmented, keeping whole nodes free for later gangs.
+	// GroupNodeScorePriority prefers the nodes the group extenders scored for the group of the pod.
+	// It has to be enabled for the scores of the extenders prioritizing groups to be used.
+	factory.RegisterPriorityFunction2(schedulerapi.GroupNodeScorePriority, priorities.GroupNodeScorePriorityMap, priorities.GroupNodeScorePriorityReduce, 1)
+	factory.RegisterPriorityConfigFactory(
+		"GangFragmentationPriority",
+		factory.PriorityConfigFactory{
+			MapReduceFunction: func(args factory.PluginFactoryArgs) (algorithm.PriorityMapFunction, algorithm.PriorityReduceFunction) {
+				return priorities.NewGangFragmentationPriority(args.PodLister, args.NodeLister)
+			},
+			Weight: 1,
+		},
+	)
 }
 
 func defaultPredicates() sets.String {
@@ -161,6 +177,30 @@ func defaultPredicates() sets.String {
 			},
 		),
 
+		// Fit is determined by the placement constraints of the pod's gang role.
+		factory.RegisterFitPredicateFactory(
+			"MatchRoleConstraints",
+			func(args factory.PluginFactoryArgs) algorithm.FitPredicate {
+				return predicates.NewRoleConstraintsPredicate(args.NodeInfo, args.PodLister)
+			},
+		),
+
+		// Fit is determined by the spread of the pod's gang role over topology domains.
+		factory.RegisterFitPredicateFactory(
+			"MatchRoleSpread",
+			func(args factory.PluginFactoryArgs) algorithm.FitPredicate {
+				return predicates.NewRoleSpreadPredicate(args.NodeInfo, args.PodLister, args.NodeLister)
+			},
+		),
+
+		// Fit is determined by the accelerator type of the node and of the pod's gang role.
+		factory.RegisterFitPredicateFactory(
+			"MatchAcceleratorType",
+			func(args factory.PluginFactoryArgs) algorithm.FitPredicate {
+				return predicates.NewAcceleratorTypePredicate(args.NodeInfo, args.PodLister)
+			},
+		),
+
 		// Fit is determined by non-conflicting disk volumes.
 		factory.RegisterFitPredicate("NoDiskConflict", predicates.NoDiskConflict),
 
@@ -211,6 +251,32 @@ func defaultPriorities() sets.String {
 			},
 		),
 
+		// spreads the members of a gang role over the topology domains declared by the role.
+		factory.RegisterPriorityConfigFactory(
+			"RoleSpreadPriority",
+			factory.PriorityConfigFactory{
+				MapReduceFunction: func(args factory.PluginFactoryArgs) (algorithm.PriorityMapFunction, algorithm.PriorityReduceFunction) {
+					return priorities.NewRoleSpreadPriority(args.PodLister, args.NodeLister)
+				},
+				Weight: 1,
+			},
+		),
+
+		// packs GPU pods onto the nodes they fill up, keeping whole GPU nodes for queued multi-GPU gangs.
+		factory.RegisterPriorityConfigFactory(
+			"GPUPackingPriority",
+			factory.PriorityConfigFactory{
+				MapReduceFunction: func(args factory.PluginFactoryArgs) (algorithm.PriorityMapFunction, algorithm.PriorityReduceFunction) {
+					demand := args.GPUDemandLister
+					if demand == nil {
+						demand = algorithm.EmptyGPUDemandLister{}
+					}
+					return priorities.NewGPUPackingPriority(demand)
+				},
+				Weight: 1,
+			},
+		),
+
 		// Prioritize nodes by least requested utilization.
 		factory.RegisterPriorityFunction2("LeastRequestedPriority", priorities.LeastRequestedPriorityMap, nil, 1),
 
@@ -255,6 +321,16 @@ func copyAndReplace(set sets.String, replaceWhat, replaceWith string) sets.Strin
 
 // GetEquivalencePod returns a EquivalencePod which contains a group of pod attributes which can be reused.
 func GetEquivalencePod(pod *v1.Pod) interface{} {
+	// The members of a gang role are identical apart from the resources they consume, so
+	// they are equivalent to each other, whatever their controller.
+	if _, ok := pod.Annotations[tools.SchedulingGroup]; ok {
+		if miniGroup := tools.GetSchedulingGroup(pod); miniGroup != nil {
+			return &EquivalencePod{
+				Group: miniGroup.Group,
+				Role:  miniGroup.Role,
+			}
+		}
+	}
 	// For now we only consider pods:
 	// 1. OwnerReferences is Controller
 	// 2. with same OwnerReferences
@@ -275,4 +351,7 @@ func GetEquivalencePod(pod *v1.Pod) interface{} {
 // EquivalencePod is a group of pod attributes which can be reused as equivalence to schedule other pods.
 type EquivalencePod struct {
 	ControllerRef metav1.OwnerReference
+	// Group and Role identify the gang role of the pod.
+	Group string
+	Role  string
 }
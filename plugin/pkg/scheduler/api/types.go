@@ -33,6 +33,18 @@ const (
 	MaxWeight        = MaxInt / MaxPriority
 )
 
+const (
+	// HTTPExtenderTransport sends the calls to an extender as JSON over HTTP.
+	HTTPExtenderTransport = "http"
+	// GRPCExtenderTransport sends the calls to an extender as protobuf messages over gRPC.
+	GRPCExtenderTransport = "grpc"
+)
+
+// GroupNodeScorePriority is the name of the priority preferring the nodes the group extenders
+// scored for the group of a pod. The scores of the group prioritize verb of an extender are
+// only used if the priority is enabled.
+const GroupNodeScorePriority = "GroupNodeScorePriority"
+
 type Policy struct {
 	metav1.TypeMeta
 	// Holds the information to configure the fit predicate functions
@@ -45,6 +57,53 @@ type Policy struct {
 	// corresponding to every RequiredDuringScheduling affinity rule.
 	// HardPodAffinitySymmetricWeight represents the weight of implicit PreferredDuringScheduling affinity rule, in the range 1-100.
 	HardPodAffinitySymmetricWeight int
+	// Parallelism is the number of nodes the predicates and priorities are evaluated on at once.
+	// 16 nodes are evaluated at once if not set.
+	Parallelism int
+	// NumFeasibleNodesToFind is the number of nodes fitting a pod to find before prioritizing them.
+	// All nodes are evaluated if neither this nor PercentageOfNodesToFind is set.
+	NumFeasibleNodesToFind int
+	// PercentageOfNodesToFind is the number of nodes fitting a pod to find before prioritizing them,
+	// as a percentage of all nodes, but at least 100 nodes. If both are set, the larger number is found.
+	PercentageOfNodesToFind int
+	// PredicateOrdering is the order the predicates are evaluated in on a node. The predicates
+	// not listed are evaluated after these, cheap checks of the node first.
+	PredicateOrdering []string
+	// ShortCircuitPredicates stops the evaluation of the predicates on a node at the first one
+	// failing. Only the reason of that predicate is reported then.
+	ShortCircuitPredicates bool
+	// GroupWorkers is the number of groups scheduled at once. Each worker places a group on
+	// its own snapshot of the cluster and places it again if another worker took one of its
+	// nodes first. One group is scheduled at a time if not set. A reloaded
+	// policy changing it is rejected.
+	GroupWorkers int
+	// BindingWorkers is the number of members of a group bound at once.
+	// 16 members are bound at once if not set.
+	BindingWorkers int
+	// BindingTimeoutSeconds is the time all members of a group have to be bound in. Bindings
+	// failing with a retriable error are retried until then. 30 seconds if not set.
+	BindingTimeoutSeconds int
+	// StatusUpdateQPS is the rate events, pod conditions and group status are written to the
+	// API server at. 20 writes per second if not set.
+	StatusUpdateQPS float32
+	// StatusUpdateBurst is the number of status writes allowed at once. 50 if not set.
+	StatusUpdateBurst int
+	// Profiles are named sets of predicates, priorities and extenders a group can select to
+	// be scheduled with instead of the ones above.
+	Profiles []ProfilePolicy
+	// NamespaceProfiles maps a namespace to the profile of its groups not selecting one.
+	NamespaceProfiles map[string]string
+}
+
+type ProfilePolicy struct {
+	// Name of the profile, selected by the profile of a group
+	Name string
+	// Holds the information to configure the fit predicate functions of the profile
+	Predicates []PredicatePolicy
+	// Holds the information to configure the priority functions of the profile
+	Priorities []PriorityPolicy
+	// Holds the information to communicate with the extender(s) of the profile
+	ExtenderConfigs []ExtenderConfig
 }
 
 type PredicatePolicy struct {
@@ -88,6 +147,9 @@ type PriorityArgument struct {
 	// The priority function that checks whether a particular node has a certain label
 	// defined or not, regardless of value
 	LabelPreference *LabelPreference
+	// The priority function that places the members of a scheduling group close to each
+	// other in the network hierarchy described by node labels
+	NetworkTopology *NetworkTopology
 }
 
 // Holds the parameters that are used to configure the corresponding predicate
@@ -122,11 +184,31 @@ type LabelPreference struct {
 	Presence bool
 }
 
+// Holds the parameters that are used to configure the corresponding priority function
+type NetworkTopology struct {
+	// The levels of the hierarchy, from the outermost (e.g. zone) to the innermost (e.g. rack)
+	Levels []NetworkTopologyLevel
+}
+
+// Holds one level of the network hierarchy
+type NetworkTopologyLevel struct {
+	// Node label whose value identifies the domain of the node at this level
+	Label string
+	// The score added for a member of the group in the same domain at this level
+	// The weight should be a positive integer
+	Weight int
+}
+
 // Holds the parameters used to communicate with the extender. If a verb is unspecified/empty,
 // it is assumed that the extender chose not to provide that extension.
 type ExtenderConfig struct {
 	// URLPrefix at which the extender is available
 	URLPrefix string
+	// Transport is the transport of the calls to the extender, "http" if not set. With "grpc", URLPrefix is
+	// the address of the gRPC server of the extender, the verbs only enable the calls, the timeout of calls is
+	// HTTPTimeout, and the changes of the nodes are streamed to extenders caching nodes. Group verbs are not
+	// supported over gRPC.
+	Transport string
 	// Verb for the filter call, empty if not supported. This verb is appended to the URLPrefix when issuing the filter call to extender.
 	FilterVerb string
 	// Verb for the prioritize call, empty if not supported. This verb is appended to the URLPrefix when issuing the prioritize call to extender.
@@ -138,6 +220,18 @@ type ExtenderConfig struct {
 	// If this method is implemented by the extender, it is the extender's responsibility to bind the pod to apiserver. Only one extender
 	// can implement this function.
 	BindVerb string
+	// Verb for the group filter call, empty if not supported. This verb is appended to the URLPrefix when issuing the group
+	// filter call to extender. The extender is sent the roles of a scheduling group and the nodes proposed for all its
+	// members before they are bound, and may reject the placement or move members to other nodes.
+	GroupFilterVerb string
+	// Verb for the group prioritize call, empty if not supported. This verb is appended to the URLPrefix when issuing the
+	// group prioritize call to extender. The nodes are scored for a scheduling group as a whole before its members are placed,
+	// and the members prefer the nodes scored higher through GroupNodeScorePriority, which the policy has to enable.
+	GroupPrioritizeVerb string
+	// Verb for the group bind call, empty if not supported. This verb is appended to the URLPrefix when issuing the group
+	// bind call to extender. If this method is implemented by the extender, it is the extender's responsibility to bind all
+	// members of a scheduling group to apiserver at once. Only one extender can implement this function.
+	GroupBindVerb string
 	// EnableHttps specifies whether https should be used to communicate with the extender
 	EnableHttps bool
 	// TLSConfig specifies the transport layer security config
@@ -149,6 +243,26 @@ type ExtenderConfig struct {
 	// so the scheduler should only send minimal information about the eligible nodes
 	// assuming that the extender already cached full details of all nodes in the cluster
 	NodeCacheCapable bool
+	// Retries is the number of times a call to the extender is retried when it fails to get a response,
+	// with a backoff doubling from RetryBackoff. Calls are not retried if not set.
+	Retries int
+	// RetryBackoff is the time waited before the first retry of a call, 100 milliseconds if not set.
+	RetryBackoff time.Duration
+	// FailureThreshold is the number of calls in a row that have to fail for the extender to be skipped
+	// for CircuitOpenDuration. The extender is never skipped if not set.
+	FailureThreshold int
+	// CircuitOpenDuration is the time the extender is skipped for once FailureThreshold calls in a row failed.
+	// One call is let through afterwards, the extender is skipped again if it fails. 30 seconds if not set.
+	CircuitOpenDuration time.Duration
+	// Ignorable specifies that the scheduling doesn't fail when the extender fails or is skipped. Its filter
+	// lets all nodes pass, and its placement of groups is accepted. Binding through the extender still fails.
+	Ignorable bool
+	// ManagedResources lists the names of the resources managed by the extender. If set, the extender is
+	// only called for the pods requesting or limiting at least one of them, or matched by LabelSelector.
+	ManagedResources []string
+	// LabelSelector selects the pods the extender is called for, in addition to the pods using its
+	// ManagedResources. The extender is called for all pods if neither is set.
+	LabelSelector *metav1.LabelSelector
 }
 
 // ExtenderArgs represents the arguments needed by the extender to filter/prioritize
@@ -199,6 +313,63 @@ type ExtenderBindingResult struct {
 	Error string
 }
 
+// ExtenderGroupRole is a role of a scheduling group, as sent to an extender.
+type ExtenderGroupRole struct {
+	// Role is the name of the role
+	Role string
+	// Min is the number of members of the role which have to be placed together
+	Min int
+	// Max is the number of members of the role
+	Max int
+	// Pods are the pending members of the role
+	Pods []v1.Pod
+}
+
+// ExtenderPodAssignment is the node proposed for a member of a scheduling group.
+type ExtenderPodAssignment struct {
+	// PodName is the name of the member
+	PodName string
+	// PodNamespace is the namespace of the member
+	PodNamespace string
+	// PodUID is the UID of the member
+	PodUID types.UID
+	// Role is the role of the member
+	Role string
+	// Node proposed by the scheduler
+	Node string
+}
+
+// ExtenderGroupArgs represents the arguments to an extender for a scheduling group: its roles,
+// and either the placement proposed for its members or the candidate nodes.
+type ExtenderGroupArgs struct {
+	// Group is the namespace/name of the scheduling group
+	Group string
+	// Priority of the group, the highest priority of its pending members; for instance to
+	// only consider members of groups of a lower priority as victims. The priority of a pod
+	// is read from its ecp-pod-priority annotation, PriorityClasses are not resolved.
+	Priority int32
+	// Roles of the group
+	Roles []ExtenderGroupRole
+	// Assignments proposed for the members of the group; populated for the group filter
+	// and group bind calls
+	Assignments []ExtenderPodAssignment
+	// List of candidate nodes for the group; populated for the group prioritize call
+	// only if ExtenderConfig.NodeCacheCapable == false
+	Nodes *v1.NodeList
+	// List of candidate node names for the group; populated for the group prioritize
+	// call only if ExtenderConfig.NodeCacheCapable == true
+	NodeNames *[]string
+}
+
+// ExtenderGroupFilterResult represents the result of a group filter call to an extender.
+type ExtenderGroupFilterResult struct {
+	// Assignments overriding the node proposed for some members of the group; the proposed
+	// placement is accepted as is if empty
+	Assignments []ExtenderPodAssignment
+	// Error message indicating the placement is rejected
+	Error string
+}
+
 // HostPriority represents the priority of scheduling to a particular host, higher priority is better.
 type HostPriority struct {
 	// Name of the host
@@ -232,21 +403,116 @@ const (
 	Failed  State = "Failed"
 )
 
+// DependencyType describes when a role dependency is considered satisfied.
+type DependencyType string
+
+const (
+	// DependencyScheduled is satisfied once the dependency role has been placed,
+	// either earlier in the same scheduling attempt or in a previous one.
+	DependencyScheduled DependencyType = "Scheduled"
+	// DependencyStarted is satisfied once at least Min pods of the dependency
+	// role are Running. The dependent role is deferred until then.
+	DependencyStarted DependencyType = "Started"
+)
+
+// RoleDependency declares that a role must be placed after another role of the same group.
+type RoleDependency struct {
+	// Role is the name of the role this role depends on.
+	Role string `json:"role"`
+	// Type is the kind of dependency, DependencyScheduled if empty.
+	Type DependencyType `json:"type,omitempty"`
+}
+
+// RoleConstraints are placement rules for the members of a role. They are checked against
+// the members of the group already placed, including the ones assumed in the current attempt.
+type RoleConstraints struct {
+	// MaxPerNode caps the number of members of the role on a single node, 0 means no cap.
+	MaxPerNode int `json:"maxPerNode,omitempty"`
+	// MaxPerDomain caps the number of members of the role in a single topology domain
+	// identified by TopologyKey, 0 means no cap.
+	MaxPerDomain int `json:"maxPerDomain,omitempty"`
+	// TopologyKey is the node label used by MaxPerDomain.
+	TopologyKey string `json:"topologyKey,omitempty"`
+	// CoLocate requires a member of each referenced role to be in the same topology domain.
+	CoLocate []RoleTopologyTerm `json:"coLocate,omitempty"`
+	// Avoid forbids members of each referenced role in the same topology domain.
+	Avoid []RoleTopologyTerm `json:"avoid,omitempty"`
+}
+
+// RoleTopologyTerm references the members of another role of the same group.
+type RoleTopologyTerm struct {
+	// Role is the referenced role.
+	Role string `json:"role"`
+	// Member optionally narrows the term down to the member pod with this name.
+	Member string `json:"member,omitempty"`
+	// TopologyKey is the node label defining the domain, the node itself if empty.
+	TopologyKey string `json:"topologyKey,omitempty"`
+}
+
+// TopologyMode tells whether a group topology is a hard or a soft constraint.
+type TopologyMode string
+
+const (
+	// TopologyHard requires all members of the group to be in a single domain.
+	TopologyHard TopologyMode = "Hard"
+	// TopologySoft packs the members into as few domains as possible.
+	TopologySoft TopologyMode = "Soft"
+)
+
+// RoleSpread distributes the members of a role evenly over the topology domains, e.g. zones
+// or racks, identified by a node label.
+type RoleSpread struct {
+	// TopologyKey is the node label identifying the domains.
+	TopologyKey string `json:"topologyKey"`
+	// MaxSkew is the largest allowed difference between the number of members of the role
+	// in any two domains, 1 if not set.
+	MaxSkew int `json:"maxSkew,omitempty"`
+}
+
+// AcceleratorPreference selects the accelerator type of the nodes of a role. All members of
+// the role are placed on nodes of the same type.
+type AcceleratorPreference struct {
+	// Label is the node label holding the accelerator type.
+	Label string `json:"label"`
+	// Types are the accepted accelerator types, the most preferred first.
+	Types []string `json:"types"`
+}
+
+// GroupTopology keeps the members of a group within the topology domains, e.g. zones or
+// racks, identified by a node label.
+type GroupTopology struct {
+	// Key is the node label identifying the domains.
+	Key string `json:"key"`
+	// Mode is TopologyHard if empty.
+	Mode TopologyMode `json:"mode,omitempty"`
+}
+
 type MiniGroup struct {
-	Group       string `json:"group"`
-	Role        string `json:"role"`
-	RoleCount   int    `json:"roleCount"`
-	MinReplicas int    `json:"minReplica"`
-	MaxReplicas int    `json:"maxReplica"`
-	Priority    int    `json:"priority"`
+	Group       string                 `json:"group"`
+	Role        string                 `json:"role"`
+	RoleCount   int                    `json:"roleCount"`
+	MinReplicas int                    `json:"minReplica"`
+	MaxReplicas int                    `json:"maxReplica"`
+	Priority    int                    `json:"priority"`
+	DependsOn   []RoleDependency       `json:"dependsOn,omitempty"`
+	Constraints *RoleConstraints       `json:"constraints,omitempty"`
+	Topology    *GroupTopology         `json:"topology,omitempty"`
+	Spread      *RoleSpread            `json:"spread,omitempty"`
+	Accelerator *AcceleratorPreference `json:"accelerator,omitempty"`
+	// Profile is the name of the scheduling profile of the group. The members of a group
+	// are expected to select the same profile.
+	Profile string `json:"profile,omitempty"`
 }
 
 type SchedulingGroup struct {
 	Group         string
 	ResourceCount int
 	SchedulerName string
+	Topology      *GroupTopology
 	Resources     []*ResourceObject
 	Status        *SchedulerGroupState
+	// Profile is the scheduling profile selected by the first member of the group seen.
+	Profile string
 }
 
 type ResourceObject struct {
@@ -256,9 +522,23 @@ type ResourceObject struct {
 	Priority        int
 	Min             int
 	Max             int
+	DependsOn       []RoleDependency
+	Accelerator     *AcceleratorPreference
+	// Bound is set once the role has been placed and bound in a previous
+	// attempt, while other roles of the group are still deferred.
+	Bound bool
+	// BoundPodCount is the number of members bound in previous attempts of a role
+	// whose binding failed for some of its members.
+	BoundPodCount int
 }
 
 type SchedulerGroupState struct {
 	State
 	PodsToBind map[string]*v1.Pod
+	// BindingFailures holds the reason the binding of a member failed in the last
+	// attempt, by pod name.
+	BindingFailures map[string]string
+	// NodeScores holds the weighted scores the group extenders gave the nodes for the
+	// group in the current attempt, by node name.
+	NodeScores map[string]int
 }
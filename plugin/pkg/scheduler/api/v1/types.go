@@ -37,6 +37,53 @@ type Policy struct {
 	// corresponding to every RequiredDuringScheduling affinity rule.
 	// HardPodAffinitySymmetricWeight represents the weight of implicit PreferredDuringScheduling affinity rule, in the range 1-100.
 	HardPodAffinitySymmetricWeight int `json:"hardPodAffinitySymmetricWeight"`
+	// Parallelism is the number of nodes the predicates and priorities are evaluated on at once.
+	// 16 nodes are evaluated at once if not set.
+	Parallelism int `json:"parallelism,omitempty"`
+	// NumFeasibleNodesToFind is the number of nodes fitting a pod to find before prioritizing them.
+	// All nodes are evaluated if neither this nor PercentageOfNodesToFind is set.
+	NumFeasibleNodesToFind int `json:"numFeasibleNodesToFind,omitempty"`
+	// PercentageOfNodesToFind is the number of nodes fitting a pod to find before prioritizing them,
+	// as a percentage of all nodes, but at least 100 nodes. If both are set, the larger number is found.
+	PercentageOfNodesToFind int `json:"percentageOfNodesToFind,omitempty"`
+	// PredicateOrdering is the order the predicates are evaluated in on a node. The predicates
+	// not listed are evaluated after these, cheap checks of the node first.
+	PredicateOrdering []string `json:"predicateOrdering,omitempty"`
+	// ShortCircuitPredicates stops the evaluation of the predicates on a node at the first one
+	// failing. Only the reason of that predicate is reported then.
+	ShortCircuitPredicates bool `json:"shortCircuitPredicates,omitempty"`
+	// GroupWorkers is the number of groups scheduled at once. Each worker places a group on
+	// its own snapshot of the cluster and places it again if another worker took one of its
+	// nodes first. One group is scheduled at a time if not set. A reloaded
+	// policy changing it is rejected.
+	GroupWorkers int `json:"groupWorkers,omitempty"`
+	// BindingWorkers is the number of members of a group bound at once.
+	// 16 members are bound at once if not set.
+	BindingWorkers int `json:"bindingWorkers,omitempty"`
+	// BindingTimeoutSeconds is the time all members of a group have to be bound in. Bindings
+	// failing with a retriable error are retried until then. 30 seconds if not set.
+	BindingTimeoutSeconds int `json:"bindingTimeoutSeconds,omitempty"`
+	// StatusUpdateQPS is the rate events, pod conditions and group status are written to the
+	// API server at. 20 writes per second if not set.
+	StatusUpdateQPS float32 `json:"statusUpdateQPS,omitempty"`
+	// StatusUpdateBurst is the number of status writes allowed at once. 50 if not set.
+	StatusUpdateBurst int `json:"statusUpdateBurst,omitempty"`
+	// Profiles are named sets of predicates, priorities and extenders a group can select to
+	// be scheduled with instead of the ones above.
+	Profiles []ProfilePolicy `json:"profiles,omitempty"`
+	// NamespaceProfiles maps a namespace to the profile of its groups not selecting one.
+	NamespaceProfiles map[string]string `json:"namespaceProfiles,omitempty"`
+}
+
+type ProfilePolicy struct {
+	// Name of the profile, selected by the profile of a group
+	Name string `json:"name"`
+	// Holds the information to configure the fit predicate functions of the profile
+	Predicates []PredicatePolicy `json:"predicates"`
+	// Holds the information to configure the priority functions of the profile
+	Priorities []PriorityPolicy `json:"priorities"`
+	// Holds the information to communicate with the extender(s) of the profile
+	ExtenderConfigs []ExtenderConfig `json:"extenders"`
 }
 
 type PredicatePolicy struct {
@@ -80,6 +127,9 @@ type PriorityArgument struct {
 	// The priority function that checks whether a particular node has a certain label
 	// defined or not, regardless of value
 	LabelPreference *LabelPreference `json:"labelPreference"`
+	// The priority function that places the members of a scheduling group close to each
+	// other in the network hierarchy described by node labels
+	NetworkTopology *NetworkTopology `json:"networkTopology"`
 }
 
 // Holds the parameters that are used to configure the corresponding predicate
@@ -114,11 +164,31 @@ type LabelPreference struct {
 	Presence bool `json:"presence"`
 }
 
+// Holds the parameters that are used to configure the corresponding priority function
+type NetworkTopology struct {
+	// The levels of the hierarchy, from the outermost (e.g. zone) to the innermost (e.g. rack)
+	Levels []NetworkTopologyLevel `json:"levels"`
+}
+
+// Holds one level of the network hierarchy
+type NetworkTopologyLevel struct {
+	// Node label whose value identifies the domain of the node at this level
+	Label string `json:"label"`
+	// The score added for a member of the group in the same domain at this level
+	// The weight should be a positive integer
+	Weight int `json:"weight"`
+}
+
 // Holds the parameters used to communicate with the extender. If a verb is unspecified/empty,
 // it is assumed that the extender chose not to provide that extension.
 type ExtenderConfig struct {
 	// URLPrefix at which the extender is available
 	URLPrefix string `json:"urlPrefix"`
+	// Transport is the transport of the calls to the extender, "http" if not set. With "grpc", URLPrefix is
+	// the address of the gRPC server of the extender, the verbs only enable the calls, the timeout of calls is
+	// HTTPTimeout, and the changes of the nodes are streamed to extenders caching nodes. Group verbs are not
+	// supported over gRPC.
+	Transport string `json:"transport,omitempty"`
 	// Verb for the filter call, empty if not supported. This verb is appended to the URLPrefix when issuing the filter call to extender.
 	FilterVerb string `json:"filterVerb,omitempty"`
 	// Verb for the prioritize call, empty if not supported. This verb is appended to the URLPrefix when issuing the prioritize call to extender.
@@ -130,6 +200,18 @@ type ExtenderConfig struct {
 	// If this method is implemented by the extender, it is the extender's responsibility to bind the pod to apiserver. Only one extender
 	// can implement this function.
 	BindVerb string
+	// Verb for the group filter call, empty if not supported. This verb is appended to the URLPrefix when issuing the group
+	// filter call to extender. The extender is sent the roles of a scheduling group and the nodes proposed for all its
+	// members before they are bound, and may reject the placement or move members to other nodes.
+	GroupFilterVerb string `json:"groupFilterVerb,omitempty"`
+	// Verb for the group prioritize call, empty if not supported. This verb is appended to the URLPrefix when issuing the
+	// group prioritize call to extender. The nodes are scored for a scheduling group as a whole before its members are placed,
+	// and the members prefer the nodes scored higher through GroupNodeScorePriority, which the policy has to enable.
+	GroupPrioritizeVerb string `json:"groupPrioritizeVerb,omitempty"`
+	// Verb for the group bind call, empty if not supported. This verb is appended to the URLPrefix when issuing the group
+	// bind call to extender. If this method is implemented by the extender, it is the extender's responsibility to bind all
+	// members of a scheduling group to apiserver at once. Only one extender can implement this function.
+	GroupBindVerb string `json:"groupBindVerb,omitempty"`
 	// EnableHttps specifies whether https should be used to communicate with the extender
 	EnableHttps bool `json:"enableHttps,omitempty"`
 	// TLSConfig specifies the transport layer security config
@@ -141,6 +223,26 @@ type ExtenderConfig struct {
 	// so the scheduler should only send minimal information about the eligible nodes
 	// assuming that the extender already cached full details of all nodes in the cluster
 	NodeCacheCapable bool `json:"nodeCacheCapable,omitempty"`
+	// Retries is the number of times a call to the extender is retried when it fails to get a response,
+	// with a backoff doubling from RetryBackoff. Calls are not retried if not set.
+	Retries int `json:"retries,omitempty"`
+	// RetryBackoff is the time waited before the first retry of a call, 100 milliseconds if not set.
+	RetryBackoff time.Duration `json:"retryBackoff,omitempty"`
+	// FailureThreshold is the number of calls in a row that have to fail for the extender to be skipped
+	// for CircuitOpenDuration. The extender is never skipped if not set.
+	FailureThreshold int `json:"failureThreshold,omitempty"`
+	// CircuitOpenDuration is the time the extender is skipped for once FailureThreshold calls in a row failed.
+	// One call is let through afterwards, the extender is skipped again if it fails. 30 seconds if not set.
+	CircuitOpenDuration time.Duration `json:"circuitOpenDuration,omitempty"`
+	// Ignorable specifies that the scheduling doesn't fail when the extender fails or is skipped. Its filter
+	// lets all nodes pass, and its placement of groups is accepted. Binding through the extender still fails.
+	Ignorable bool `json:"ignorable,omitempty"`
+	// ManagedResources lists the names of the resources managed by the extender. If set, the extender is
+	// only called for the pods requesting or limiting at least one of them, or matched by LabelSelector.
+	ManagedResources []string `json:"managedResources,omitempty"`
+	// LabelSelector selects the pods the extender is called for, in addition to the pods using its
+	// ManagedResources. The extender is called for all pods if neither is set.
+	LabelSelector *metav1.LabelSelector `json:"labelSelector,omitempty"`
 }
 
 // ExtenderArgs represents the arguments needed by the extender to filter/prioritize
@@ -191,6 +293,63 @@ type ExtenderBindingResult struct {
 	Error string
 }
 
+// ExtenderGroupRole is a role of a scheduling group, as sent to an extender.
+type ExtenderGroupRole struct {
+	// Role is the name of the role
+	Role string `json:"role"`
+	// Min is the number of members of the role which have to be placed together
+	Min int `json:"min"`
+	// Max is the number of members of the role
+	Max int `json:"max"`
+	// Pods are the pending members of the role
+	Pods []apiv1.Pod `json:"pods,omitempty"`
+}
+
+// ExtenderPodAssignment is the node proposed for a member of a scheduling group.
+type ExtenderPodAssignment struct {
+	// PodName is the name of the member
+	PodName string `json:"podName"`
+	// PodNamespace is the namespace of the member
+	PodNamespace string `json:"podNamespace"`
+	// PodUID is the UID of the member
+	PodUID types.UID `json:"podUID"`
+	// Role is the role of the member
+	Role string `json:"role"`
+	// Node proposed by the scheduler
+	Node string `json:"node"`
+}
+
+// ExtenderGroupArgs represents the arguments to an extender for a scheduling group: its roles,
+// and either the placement proposed for its members or the candidate nodes.
+type ExtenderGroupArgs struct {
+	// Group is the namespace/name of the scheduling group
+	Group string `json:"group"`
+	// Priority of the group, the highest priority of its pending members; for instance to
+	// only consider members of groups of a lower priority as victims. The priority of a pod
+	// is read from its ecp-pod-priority annotation, PriorityClasses are not resolved.
+	Priority int32 `json:"priority,omitempty"`
+	// Roles of the group
+	Roles []ExtenderGroupRole `json:"roles"`
+	// Assignments proposed for the members of the group; populated for the group filter
+	// and group bind calls
+	Assignments []ExtenderPodAssignment `json:"assignments,omitempty"`
+	// List of candidate nodes for the group; populated for the group prioritize call
+	// only if ExtenderConfig.NodeCacheCapable == false
+	Nodes *apiv1.NodeList `json:"nodes,omitempty"`
+	// List of candidate node names for the group; populated for the group prioritize
+	// call only if ExtenderConfig.NodeCacheCapable == true
+	NodeNames *[]string `json:"nodenames,omitempty"`
+}
+
+// ExtenderGroupFilterResult represents the result of a group filter call to an extender.
+type ExtenderGroupFilterResult struct {
+	// Assignments overriding the node proposed for some members of the group; the proposed
+	// placement is accepted as is if empty
+	Assignments []ExtenderPodAssignment `json:"assignments,omitempty"`
+	// Error message indicating the placement is rejected
+	Error string `json:"error,omitempty"`
+}
+
 // HostPriority represents the priority of scheduling to a particular host, higher priority is better.
 type HostPriority struct {
 	// Name of the host
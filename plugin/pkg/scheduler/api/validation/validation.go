@@ -18,7 +18,9 @@ package validation
 
 import (
 	"fmt"
+	"reflect"
 
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	schedulerapi "k8s.io/kubernetes/plugin/pkg/scheduler/api"
 )
@@ -28,23 +30,182 @@ import (
 func ValidatePolicy(policy schedulerapi.Policy) error {
 	var validationErrors []error
 
-	for _, priority := range policy.Priorities {
+	validationErrors = append(validationErrors, validatePriorities(policy.Priorities)...)
+	validationErrors = append(validationErrors, validateExtenders(policy.ExtenderConfigs)...)
+	validationErrors = append(validationErrors, validateGroupPrioritize(policy.Priorities, policy.ExtenderConfigs)...)
+	validationErrors = append(validationErrors, validateProfiles(policy)...)
+
+	if policy.Parallelism < 0 {
+		validationErrors = append(validationErrors, fmt.Errorf("Parallelism should not be negative, got %v", policy.Parallelism))
+	}
+	if policy.NumFeasibleNodesToFind < 0 {
+		validationErrors = append(validationErrors, fmt.Errorf("Number of feasible nodes to find should not be negative, got %v", policy.NumFeasibleNodesToFind))
+	}
+	if policy.PercentageOfNodesToFind < 0 || policy.PercentageOfNodesToFind > 100 {
+		validationErrors = append(validationErrors, fmt.Errorf("Percentage of nodes to find should be in the range 0-100, got %v", policy.PercentageOfNodesToFind))
+	}
+	if policy.GroupWorkers < 0 {
+		validationErrors = append(validationErrors, fmt.Errorf("Group workers should not be negative, got %v", policy.GroupWorkers))
+	}
+	if policy.BindingWorkers < 0 {
+		validationErrors = append(validationErrors, fmt.Errorf("Binding workers should not be negative, got %v", policy.BindingWorkers))
+	}
+	if policy.BindingTimeoutSeconds < 0 {
+		validationErrors = append(validationErrors, fmt.Errorf("Binding timeout should not be negative, got %v", policy.BindingTimeoutSeconds))
+	}
+	if policy.StatusUpdateQPS < 0 {
+		validationErrors = append(validationErrors, fmt.Errorf("Status update QPS should not be negative, got %v", policy.StatusUpdateQPS))
+	}
+	if policy.StatusUpdateBurst < 0 {
+		validationErrors = append(validationErrors, fmt.Errorf("Status update burst should not be negative, got %v", policy.StatusUpdateBurst))
+	}
+
+	ordered := map[string]bool{}
+	for _, predicate := range policy.PredicateOrdering {
+		if ordered[predicate] {
+			validationErrors = append(validationErrors, fmt.Errorf("Predicate %s is repeated in the predicate ordering", predicate))
+		}
+		ordered[predicate] = true
+	}
+	return utilerrors.NewAggregate(validationErrors)
+}
+
+func validatePriorities(priorities []schedulerapi.PriorityPolicy) []error {
+	var validationErrors []error
+	for _, priority := range priorities {
 		if priority.Weight <= 0 || priority.Weight >= schedulerapi.MaxWeight {
 			validationErrors = append(validationErrors, fmt.Errorf("Priority %s should have a positive weight applied to it or it has overflown", priority.Name))
 		}
+		if priority.Argument != nil && priority.Argument.NetworkTopology != nil {
+			validationErrors = append(validationErrors, validateNetworkTopology(priority.Name, priority.Argument.NetworkTopology)...)
+		}
 	}
+	return validationErrors
+}
 
-	binders := 0
-	for _, extender := range policy.ExtenderConfigs {
+func validateExtenders(extenders []schedulerapi.ExtenderConfig) []error {
+	var validationErrors []error
+	binders, groupBinders := 0, 0
+	for _, extender := range extenders {
 		if extender.Weight <= 0 {
 			validationErrors = append(validationErrors, fmt.Errorf("Priority for extender %s should have a positive weight applied to it", extender.URLPrefix))
 		}
 		if extender.BindVerb != "" {
 			binders++
 		}
+		if extender.GroupBindVerb != "" {
+			groupBinders++
+		}
+		switch extender.Transport {
+		case "", schedulerapi.HTTPExtenderTransport:
+		case schedulerapi.GRPCExtenderTransport:
+			if extender.GroupFilterVerb != "" || extender.GroupPrioritizeVerb != "" || extender.GroupBindVerb != "" {
+				validationErrors = append(validationErrors, fmt.Errorf("Extender %s doesn't support group verbs over gRPC", extender.URLPrefix))
+			}
+		default:
+			validationErrors = append(validationErrors, fmt.Errorf("Transport %s of extender %s is not supported", extender.Transport, extender.URLPrefix))
+		}
+		if extender.Retries < 0 || extender.RetryBackoff < 0 {
+			validationErrors = append(validationErrors, fmt.Errorf("Retries of extender %s should not be negative", extender.URLPrefix))
+		}
+		if extender.FailureThreshold < 0 || extender.CircuitOpenDuration < 0 {
+			validationErrors = append(validationErrors, fmt.Errorf("Circuit breaker of extender %s should not be negative", extender.URLPrefix))
+		}
+		for _, resource := range extender.ManagedResources {
+			if resource == "" {
+				validationErrors = append(validationErrors, fmt.Errorf("Managed resource of extender %s should have a name", extender.URLPrefix))
+			}
+		}
+		if extender.LabelSelector != nil {
+			if _, err := metav1.LabelSelectorAsSelector(extender.LabelSelector); err != nil {
+				validationErrors = append(validationErrors, fmt.Errorf("Label selector of extender %s is invalid: %v", extender.URLPrefix, err))
+			}
+		}
 	}
 	if binders > 1 {
 		validationErrors = append(validationErrors, fmt.Errorf("Only one extender can implement bind, found %v", binders))
 	}
-	return utilerrors.NewAggregate(validationErrors)
+	if groupBinders > 1 {
+		validationErrors = append(validationErrors, fmt.Errorf("Only one extender can implement group bind, found %v", groupBinders))
+	}
+	return validationErrors
+}
+
+// validateProfiles checks the profiles like the policy itself. The results of the predicates
+// are cached by name for all profiles, so a predicate has the same argument in all of them.
+// validateGroupPrioritize checks that the scores of the extenders prioritizing groups are used,
+// which GroupNodeScorePriority does.
+func validateGroupPrioritize(priorities []schedulerapi.PriorityPolicy, extenders []schedulerapi.ExtenderConfig) []error {
+	for _, priority := range priorities {
+		if priority.Name == schedulerapi.GroupNodeScorePriority {
+			return nil
+		}
+	}
+	var validationErrors []error
+	for _, extender := range extenders {
+		if extender.GroupPrioritizeVerb != "" {
+			validationErrors = append(validationErrors, fmt.Errorf("Extender %s prioritizes groups, but priority %s is not enabled", extender.URLPrefix, schedulerapi.GroupNodeScorePriority))
+		}
+	}
+	return validationErrors
+}
+
+func validateProfiles(policy schedulerapi.Policy) []error {
+	var validationErrors []error
+	arguments := map[string]*schedulerapi.PredicateArgument{}
+	checkPredicates := func(predicates []schedulerapi.PredicatePolicy) {
+		for _, predicate := range predicates {
+			if argument, ok := arguments[predicate.Name]; ok && !reflect.DeepEqual(argument, predicate.Argument) {
+				validationErrors = append(validationErrors, fmt.Errorf("Predicate %s is configured with different arguments", predicate.Name))
+			}
+			arguments[predicate.Name] = predicate.Argument
+		}
+	}
+	checkPredicates(policy.Predicates)
+
+	names := map[string]bool{}
+	for _, profile := range policy.Profiles {
+		if len(profile.Name) == 0 {
+			validationErrors = append(validationErrors, fmt.Errorf("Profile should have a name"))
+		} else if names[profile.Name] {
+			validationErrors = append(validationErrors, fmt.Errorf("Profile %s is repeated", profile.Name))
+		}
+		names[profile.Name] = true
+		checkPredicates(profile.Predicates)
+		for _, err := range validatePriorities(profile.Priorities) {
+			validationErrors = append(validationErrors, fmt.Errorf("Profile %s: %v", profile.Name, err))
+		}
+		for _, err := range validateExtenders(profile.ExtenderConfigs) {
+			validationErrors = append(validationErrors, fmt.Errorf("Profile %s: %v", profile.Name, err))
+		}
+		for _, err := range validateGroupPrioritize(profile.Priorities, profile.ExtenderConfigs) {
+			validationErrors = append(validationErrors, fmt.Errorf("Profile %s: %v", profile.Name, err))
+		}
+	}
+	for namespace, profile := range policy.NamespaceProfiles {
+		if !names[profile] {
+			validationErrors = append(validationErrors, fmt.Errorf("Profile %s of namespace %s is not defined", profile, namespace))
+		}
+	}
+	return validationErrors
+}
+
+func validateNetworkTopology(name string, topology *schedulerapi.NetworkTopology) []error {
+	var validationErrors []error
+	if len(topology.Levels) == 0 {
+		validationErrors = append(validationErrors, fmt.Errorf("Priority %s should name at least one network topology level", name))
+	}
+	labels := map[string]bool{}
+	for _, level := range topology.Levels {
+		if len(level.Label) == 0 {
+			validationErrors = append(validationErrors, fmt.Errorf("Network topology level of priority %s should have a label", name))
+		} else if labels[level.Label] {
+			validationErrors = append(validationErrors, fmt.Errorf("Network topology level %s of priority %s is repeated", level.Label, name))
+		}
+		labels[level.Label] = true
+		if level.Weight <= 0 {
+			validationErrors = append(validationErrors, fmt.Errorf("Network topology level %s of priority %s should have a positive weight", level.Label, name))
+		}
+	}
+	return validationErrors
 }
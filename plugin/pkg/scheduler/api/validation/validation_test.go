@@ -18,7 +18,9 @@ package validation
 
 import (
 	"testing"
+	"time"
 
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/kubernetes/plugin/pkg/scheduler/api"
 )
 
@@ -73,6 +75,75 @@ func TestValidateExtenderWithNegativeWeight(t *testing.T) {
 	}
 }
 
+func TestValidateExtenderResilience(t *testing.T) {
+	extenderPolicy := api.Policy{ExtenderConfigs: []api.ExtenderConfig{{URLPrefix: "http://127.0.0.1:8081/extender", FilterVerb: "filter", Weight: 1,
+		Retries: 2, RetryBackoff: time.Second, FailureThreshold: 5, CircuitOpenDuration: time.Minute, Ignorable: true}}}
+	if errs := ValidatePolicy(extenderPolicy); errs != nil {
+		t.Errorf("Unexpected errors %v", errs)
+	}
+
+	for _, invalid := range []api.ExtenderConfig{{Retries: -1}, {RetryBackoff: -time.Second}, {FailureThreshold: -1}, {CircuitOpenDuration: -time.Second}} {
+		invalid.URLPrefix, invalid.Weight = "http://127.0.0.1:8081/extender", 1
+		if ValidatePolicy(api.Policy{ExtenderConfigs: []api.ExtenderConfig{invalid}}) == nil {
+			t.Errorf("Expected error about resilience of extender %+v", invalid)
+		}
+	}
+}
+
+func TestValidateExtenderInterest(t *testing.T) {
+	extenderPolicy := api.Policy{ExtenderConfigs: []api.ExtenderConfig{{URLPrefix: "http://127.0.0.1:8081/extender", FilterVerb: "filter", Weight: 1,
+		ManagedResources: []string{"example.com/gpu"}, LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"accelerator": "gpu"}}}}}
+	if errs := ValidatePolicy(extenderPolicy); errs != nil {
+		t.Errorf("Unexpected errors %v", errs)
+	}
+
+	for _, invalid := range []api.ExtenderConfig{
+		{ManagedResources: []string{""}},
+		{LabelSelector: &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "accelerator", Operator: "Unknown"}}}},
+	} {
+		invalid.URLPrefix, invalid.Weight = "http://127.0.0.1:8081/extender", 1
+		if ValidatePolicy(api.Policy{ExtenderConfigs: []api.ExtenderConfig{invalid}}) == nil {
+			t.Errorf("Expected error about interest of extender %+v", invalid)
+		}
+	}
+}
+
+func TestValidateExtenderTransport(t *testing.T) {
+	for _, transport := range []string{"", api.HTTPExtenderTransport, api.GRPCExtenderTransport} {
+		extenderPolicy := api.Policy{ExtenderConfigs: []api.ExtenderConfig{{URLPrefix: "127.0.0.1:8081", FilterVerb: "filter", Weight: 1, Transport: transport}}}
+		if errs := ValidatePolicy(extenderPolicy); errs != nil {
+			t.Errorf("Unexpected errors with transport %q: %v", transport, errs)
+		}
+	}
+
+	for _, invalid := range []api.ExtenderConfig{
+		{Transport: "websocket"},
+		{Transport: api.GRPCExtenderTransport, GroupFilterVerb: "groupfilter"},
+	} {
+		invalid.URLPrefix, invalid.Weight = "127.0.0.1:8081", 1
+		if ValidatePolicy(api.Policy{ExtenderConfigs: []api.ExtenderConfig{invalid}}) == nil {
+			t.Errorf("Expected error about transport of extender %+v", invalid)
+		}
+	}
+}
+
+func TestValidateExtenderGroupPrioritize(t *testing.T) {
+	extenders := []api.ExtenderConfig{{URLPrefix: "http://127.0.0.1:8081/extender", GroupPrioritizeVerb: "groupprioritize", Weight: 1}}
+	scored := []api.PriorityPolicy{{Name: api.GroupNodeScorePriority, Weight: 1}}
+	if errs := ValidatePolicy(api.Policy{Priorities: scored, ExtenderConfigs: extenders}); errs != nil {
+		t.Errorf("Unexpected errors %v", errs)
+	}
+
+	for _, invalid := range []api.Policy{
+		{ExtenderConfigs: extenders},
+		{Profiles: []api.ProfilePolicy{{Name: "gpu", ExtenderConfigs: extenders}}, Priorities: scored},
+	} {
+		if ValidatePolicy(invalid) == nil {
+			t.Errorf("Expected error about the unused scores of group prioritize in policy %+v", invalid)
+		}
+	}
+}
+
 func TestValidateMultipleExtendersWithBind(t *testing.T) {
 	extenderPolicy := api.Policy{
 		ExtenderConfigs: []api.ExtenderConfig{
@@ -84,3 +155,136 @@ func TestValidateMultipleExtendersWithBind(t *testing.T) {
 		t.Errorf("Expected failure when multiple extenders with bind")
 	}
 }
+
+func TestValidateMultipleExtendersWithGroupBind(t *testing.T) {
+	extenderPolicy := api.Policy{
+		ExtenderConfigs: []api.ExtenderConfig{
+			{URLPrefix: "http://127.0.0.1:8081/extender", BindVerb: "bind", GroupBindVerb: "groupbind", Weight: 1},
+			{URLPrefix: "http://127.0.0.1:8082/extender", GroupFilterVerb: "groupfilter", Weight: 1},
+		},
+	}
+	if errs := ValidatePolicy(extenderPolicy); errs != nil {
+		t.Errorf("Unexpected errors %v", errs)
+	}
+
+	extenderPolicy.ExtenderConfigs[1].GroupBindVerb = "groupbind"
+	if ValidatePolicy(extenderPolicy) == nil {
+		t.Errorf("Expected failure when multiple extenders with group bind")
+	}
+}
+
+func TestValidateNetworkTopologyPriority(t *testing.T) {
+	validPolicy := api.Policy{Priorities: []api.PriorityPolicy{{Name: "NetworkTopology", Weight: 1, Argument: &api.PriorityArgument{
+		NetworkTopology: &api.NetworkTopology{Levels: []api.NetworkTopologyLevel{{Label: "zone", Weight: 1}, {Label: "rack", Weight: 2}}},
+	}}}}
+	if errs := ValidatePolicy(validPolicy); errs != nil {
+		t.Errorf("Unexpected errors %v", errs)
+	}
+
+	invalidPolicy := api.Policy{Priorities: []api.PriorityPolicy{{Name: "NetworkTopology", Weight: 1, Argument: &api.PriorityArgument{
+		NetworkTopology: &api.NetworkTopology{Levels: []api.NetworkTopologyLevel{{Label: "zone", Weight: 1}, {Label: "zone", Weight: 0}}},
+	}}}}
+	if ValidatePolicy(invalidPolicy) == nil {
+		t.Errorf("Expected error about repeated network topology level and its weight")
+	}
+}
+
+func TestValidatePredicateOrdering(t *testing.T) {
+	validPolicy := api.Policy{PredicateOrdering: []string{"PodFitsResources", "MatchNodeSelector"}}
+	if errs := ValidatePolicy(validPolicy); errs != nil {
+		t.Errorf("Unexpected errors %v", errs)
+	}
+
+	invalidPolicy := api.Policy{PredicateOrdering: []string{"PodFitsResources", "PodFitsResources"}}
+	if ValidatePolicy(invalidPolicy) == nil {
+		t.Errorf("Expected error about repeated predicate in the ordering")
+	}
+}
+
+func TestValidateNodeSampling(t *testing.T) {
+	validPolicy := api.Policy{Parallelism: 32, NumFeasibleNodesToFind: 50, PercentageOfNodesToFind: 10}
+	if errs := ValidatePolicy(validPolicy); errs != nil {
+		t.Errorf("Unexpected errors %v", errs)
+	}
+
+	for _, invalidPolicy := range []api.Policy{{Parallelism: -1}, {NumFeasibleNodesToFind: -1}, {PercentageOfNodesToFind: 101}} {
+		if ValidatePolicy(invalidPolicy) == nil {
+			t.Errorf("Expected error about node sampling of policy %+v", invalidPolicy)
+		}
+	}
+}
+
+func TestValidateBinding(t *testing.T) {
+	validPolicy := api.Policy{BindingWorkers: 8, BindingTimeoutSeconds: 60}
+	if errs := ValidatePolicy(validPolicy); errs != nil {
+		t.Errorf("Unexpected errors %v", errs)
+	}
+
+	for _, invalidPolicy := range []api.Policy{{BindingWorkers: -1}, {BindingTimeoutSeconds: -1}} {
+		if ValidatePolicy(invalidPolicy) == nil {
+			t.Errorf("Expected error about binding of policy %+v", invalidPolicy)
+		}
+	}
+}
+
+func TestValidateGroupWorkers(t *testing.T) {
+	validPolicy := api.Policy{GroupWorkers: 4}
+	if errs := ValidatePolicy(validPolicy); errs != nil {
+		t.Errorf("Unexpected errors %v", errs)
+	}
+
+	invalidPolicy := api.Policy{GroupWorkers: -1}
+	if ValidatePolicy(invalidPolicy) == nil {
+		t.Errorf("Expected error about group workers of policy %+v", invalidPolicy)
+	}
+}
+
+func TestValidateStatusUpdateRate(t *testing.T) {
+	validPolicy := api.Policy{StatusUpdateQPS: 5.5, StatusUpdateBurst: 10}
+	if errs := ValidatePolicy(validPolicy); errs != nil {
+		t.Errorf("Unexpected errors %v", errs)
+	}
+
+	for _, invalidPolicy := range []api.Policy{{StatusUpdateQPS: -1}, {StatusUpdateBurst: -1}} {
+		if ValidatePolicy(invalidPolicy) == nil {
+			t.Errorf("Expected error about status update rate of policy %+v", invalidPolicy)
+		}
+	}
+}
+
+func TestValidateProfiles(t *testing.T) {
+	spread := api.ProfilePolicy{
+		Name:       "spread",
+		Priorities: []api.PriorityPolicy{{Name: "LeastRequestedPriority", Weight: 1}},
+	}
+	binpack := api.ProfilePolicy{
+		Name:            "binpack",
+		Priorities:      []api.PriorityPolicy{{Name: "MostRequestedPriority", Weight: 2}},
+		ExtenderConfigs: []api.ExtenderConfig{{URLPrefix: "http://binpack", Weight: 1}},
+	}
+	validPolicy := api.Policy{
+		Profiles:          []api.ProfilePolicy{spread, binpack},
+		NamespaceProfiles: map[string]string{"training": "binpack"},
+	}
+	if errs := ValidatePolicy(validPolicy); errs != nil {
+		t.Errorf("Unexpected errors %v", errs)
+	}
+
+	labels := &api.PredicateArgument{LabelsPresence: &api.LabelsPresence{Labels: []string{"gpu"}, Presence: true}}
+	noLabels := &api.PredicateArgument{LabelsPresence: &api.LabelsPresence{Labels: []string{"gpu"}}}
+	for _, invalidPolicy := range []api.Policy{
+		{Profiles: []api.ProfilePolicy{{Priorities: spread.Priorities}}},
+		{Profiles: []api.ProfilePolicy{spread, spread}},
+		{Profiles: []api.ProfilePolicy{{Name: "spread", Priorities: []api.PriorityPolicy{{Name: "LeastRequestedPriority"}}}}},
+		{Profiles: []api.ProfilePolicy{{Name: "binpack", ExtenderConfigs: []api.ExtenderConfig{{URLPrefix: "http://binpack"}}}}},
+		{Profiles: []api.ProfilePolicy{spread}, NamespaceProfiles: map[string]string{"training": "binpack"}},
+		{
+			Predicates: []api.PredicatePolicy{{Name: "HasGPU", Argument: labels}},
+			Profiles:   []api.ProfilePolicy{{Name: "spread", Predicates: []api.PredicatePolicy{{Name: "HasGPU", Argument: noLabels}}}},
+		},
+	} {
+		if ValidatePolicy(invalidPolicy) == nil {
+			t.Errorf("Expected error about profiles of policy %+v", invalidPolicy)
+		}
+	}
+}
@@ -0,0 +1,250 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/golang/glog"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/kubernetes/pkg/api/v1"
+	"k8s.io/kubernetes/plugin/pkg/scheduler/algorithm"
+	schedulerapi "k8s.io/kubernetes/plugin/pkg/scheduler/api"
+	"k8s.io/kubernetes/plugin/pkg/scheduler/metrics"
+)
+
+const (
+	// DefaultBindingWorkers is the number of members of a group bound at once if not configured.
+	DefaultBindingWorkers = 16
+	// DefaultBindingTimeout is the time all members of a group have to be bound in if not configured.
+	DefaultBindingTimeout = 30 * time.Second
+
+	initialBindingBackoff = 100 * time.Millisecond
+	maxBindingBackoff     = 5 * time.Second
+)
+
+// BindingPolicy configures how the members of a group are bound.
+type BindingPolicy struct {
+	// Workers is the number of members bound at once.
+	Workers int
+	// Timeout is the time all members of a group have to be bound in. Bindings failing
+	// with a retriable error are retried with an exponential backoff until then.
+	Timeout time.Duration
+}
+
+func (p BindingPolicy) workers() int {
+	if p.Workers <= 0 {
+		return DefaultBindingWorkers
+	}
+	return p.Workers
+}
+
+func (p BindingPolicy) timeout() time.Duration {
+	if p.Timeout <= 0 {
+		return DefaultBindingTimeout
+	}
+	return p.Timeout
+}
+
+// bindingResult is the outcome of the binding of a member of a group.
+type bindingResult struct {
+	pod      *v1.Pod
+	node     string
+	attempts int
+	err      error
+}
+
+// bindGroup binds the pods of the group to the nodes they are assumed on, as many at once as
+// the binding policy allows, and returns the outcome of each binding in the order of pods.
+// If an extender interested in the pods binds groups, all pods are bound by it at once
+// instead. All bindings share the deadline of the group. The pods whose binding failed are
+// forgotten in the cache, and the node name of all pods is reset.
+func (sched *Scheduler) bindGroup(group *schedulerapi.SchedulingGroup, pods []*v1.Pod) []bindingResult {
+	policy := sched.config.Binding
+	deadline := time.Now().Add(policy.timeout())
+	results := make([]bindingResult, len(pods))
+	if binder := sched.groupBinder(pods); binder != nil {
+		sched.bindWithExtender(binder, group, pods, deadline, results)
+	} else {
+		workqueue.Parallelize(policy.workers(), len(pods), func(i int) {
+			results[i] = sched.bind(pods[i], deadline)
+		})
+	}
+	for _, pod := range pods {
+		pod.Spec.NodeName = ""
+	}
+	return results
+}
+
+// bind binds a pod to the node it is assumed on, retrying retriable errors until the deadline.
+// We expect this to run asynchronously, so we handle binding metrics internally.
+func (sched *Scheduler) bind(assumed *v1.Pod, deadline time.Time) bindingResult {
+	result := bindingResult{pod: assumed, node: assumed.Spec.NodeName}
+	b := &v1.Binding{
+		ObjectMeta: metav1.ObjectMeta{Namespace: assumed.Namespace, Name: assumed.Name, UID: assumed.UID},
+		Target: v1.ObjectReference{
+			Kind: "Node",
+			Name: result.node,
+		},
+	}
+	glog.Infof("Binding pod %s/%s to node %s", assumed.Namespace, assumed.Name, result.node)
+
+	bindingStart := time.Now()
+	// If binding succeeded then PodScheduled condition will be updated in apiserver so that
+	// it's atomic with setting host.
+	result.attempts, result.err = sched.retryBinding(deadline, func() error {
+		return sched.binder(assumed).Bind(b)
+	})
+	if result.err != nil {
+		sched.bindingFailed(assumed, result.err)
+		return result
+	}
+
+	// The outcome is recorded as an event for the group by scheduleOne.
+	metrics.BindingLatency.Observe(metrics.SinceInMicroseconds(bindingStart))
+	return result
+}
+
+// binder returns the binder of the pod.
+func (sched *Scheduler) binder(pod *v1.Pod) Binder {
+	if sched.config.GetBinder != nil {
+		return sched.config.GetBinder(pod)
+	}
+	return sched.config.Binder
+}
+
+// bindWithExtender binds all pods of the group at once through the extender, and reports
+// the same outcome for all of them.
+func (sched *Scheduler) bindWithExtender(binder algorithm.GroupExtender, group *schedulerapi.SchedulingGroup, pods []*v1.Pod, deadline time.Time, results []bindingResult) {
+	glog.Infof("Binding %d pods of group %s through extender", len(pods), group.Group)
+	assignments := groupAssignments(pods)
+	bindingStart := time.Now()
+	attempts, err := sched.retryBinding(deadline, func() error {
+		return binder.GroupBind(group, assignments)
+	})
+	for i, pod := range pods {
+		results[i] = bindingResult{pod: pod, node: pod.Spec.NodeName, attempts: attempts, err: err}
+		if err != nil {
+			sched.bindingFailed(pod, err)
+		}
+	}
+	if err == nil {
+		metrics.BindingLatency.Observe(metrics.SinceInMicroseconds(bindingStart))
+	}
+}
+
+// retryBinding calls bind until it succeeds, fails with an error that is not retriable, or
+// the deadline would pass while backing off. It returns the number of attempts and the error
+// of the last one.
+func (sched *Scheduler) retryBinding(deadline time.Time, bind func() error) (int, error) {
+	backoff := initialBindingBackoff
+	for attempts := 1; ; attempts++ {
+		err := bind()
+		if err == nil || !isRetriableBindingError(err) {
+			return attempts, err
+		}
+		if time.Now().Add(backoff).After(deadline) {
+			return attempts, fmt.Errorf("binding deadline of the group exceeded after %d attempts: %v", attempts, err)
+		}
+		glog.V(3).Infof("Retrying binding in %v: %v", backoff, err)
+		metrics.BindingRetries.Inc()
+		select {
+		case <-time.After(backoff):
+		case <-sched.config.StopEverything:
+			return attempts, err
+		}
+		backoff *= 2
+		if backoff > maxBindingBackoff {
+			backoff = maxBindingBackoff
+		}
+	}
+}
+
+// bindingFailed forgets the pod in the cache again and reports why it couldn't be bound.
+func (sched *Scheduler) bindingFailed(assumed *v1.Pod, err error) {
+	glog.V(1).Infof("Failed to bind pod: %v/%v", assumed.Namespace, assumed.Name)
+	if forgetErr := sched.forget(assumed); forgetErr != nil {
+		glog.Errorf("scheduler cache ForgetPod failed: %v", forgetErr)
+	}
+	sched.updatePodCondition(assumed, &v1.PodCondition{
+		Type:    v1.PodScheduled,
+		Status:  v1.ConditionFalse,
+		Reason:  "BindingRejected",
+		Message: err.Error(),
+	})
+}
+
+// isRetriableBindingError returns true if the binding may succeed when it is sent again:
+// on conflicts, timeouts and server errors.
+func isRetriableBindingError(err error) bool {
+	if apierrors.IsConflict(err) || apierrors.IsServerTimeout(err) || apierrors.IsTimeout(err) ||
+		apierrors.IsTooManyRequests(err) || apierrors.IsInternalError(err) || apierrors.IsUnexpectedServerError(err) {
+		return true
+	}
+	if status, ok := err.(apierrors.APIStatus); ok {
+		return status.Status().Code >= 500
+	}
+	if netErr, ok := err.(net.Error); ok {
+		return netErr.Timeout() || netErr.Temporary()
+	}
+	return false
+}
+
+// recordBindings reports the outcome of the bindings to the group. The members bound are no
+// longer pending, and a role is bound once all its placed members are. It returns the number
+// of members whose binding failed, the reasons are kept in the status of the group. The
+// group is a copy the attempt owns, the bindings are recorded in the configurator as well.
+func (sched *Scheduler) recordBindings(group *schedulerapi.SchedulingGroup, roles []*schedulerapi.ResourceObject, results []bindingResult) int {
+	group.Status.BindingFailures = make(map[string]string)
+	failedRoles := make(map[string]bool)
+	bound := make([]*v1.Pod, 0, len(results))
+	for _, result := range results {
+		var role *schedulerapi.ResourceObject
+		for _, rb := range roles {
+			if _, ok := rb.PendingPods[result.pod.Name]; ok {
+				role = rb
+				break
+			}
+		}
+		if result.err != nil {
+			group.Status.BindingFailures[result.pod.Name] = result.err.Error()
+			if role != nil {
+				failedRoles[role.Role] = true
+			}
+			continue
+		}
+		bound = append(bound, result.pod)
+		if role != nil {
+			delete(role.PendingPods, result.pod.Name)
+			role.PendingPodCount--
+			role.BoundPodCount++
+		}
+	}
+	for _, rb := range roles {
+		if !failedRoles[rb.Role] {
+			rb.Bound = true
+		}
+	}
+	if sched.config.RecordSchedulingGroupBindings != nil {
+		sched.config.RecordSchedulingGroupBindings(group, bound)
+	}
+	return len(group.Status.BindingFailures)
+}
@@ -0,0 +1,194 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/kubernetes/pkg/api/v1"
+	schedulerapi "k8s.io/kubernetes/plugin/pkg/scheduler/api"
+	schedulertesting "k8s.io/kubernetes/plugin/pkg/scheduler/testing"
+)
+
+func TestIsRetriableBindingError(t *testing.T) {
+	resource := schema.GroupResource{Resource: "pods"}
+	tests := []struct {
+		err       error
+		retriable bool
+	}{
+		{err: apierrors.NewConflict(resource, "foo", errors.New("conflict")), retriable: true},
+		{err: apierrors.NewServerTimeout(resource, "create", 1), retriable: true},
+		{err: apierrors.NewInternalError(errors.New("internal")), retriable: true},
+		{err: apierrors.NewGenericServerResponse(503, "create", resource, "foo", "unavailable", 1, true), retriable: true},
+		{err: apierrors.NewForbidden(resource, "foo", errors.New("forbidden")), retriable: false},
+		{err: apierrors.NewNotFound(resource, "foo"), retriable: false},
+		{err: errors.New("unknown"), retriable: false},
+	}
+	for _, test := range tests {
+		if retriable := isRetriableBindingError(test.err); retriable != test.retriable {
+			t.Errorf("%v: expected retriable %v, got %v", test.err, test.retriable, retriable)
+		}
+	}
+}
+
+func TestBindGroupRetries(t *testing.T) {
+	resource := schema.GroupResource{Resource: "pods"}
+	tests := []struct {
+		name     string
+		errs     []error
+		timeout  time.Duration
+		attempts int
+		bound    bool
+	}{
+		{
+			name:     "bound at once",
+			attempts: 1,
+			bound:    true,
+		},
+		{
+			name:     "retriable errors are retried",
+			errs:     []error{apierrors.NewConflict(resource, "foo", errors.New("conflict")), apierrors.NewInternalError(errors.New("internal"))},
+			attempts: 3,
+			bound:    true,
+		},
+		{
+			name:     "other errors are not retried",
+			errs:     []error{apierrors.NewForbidden(resource, "foo", errors.New("forbidden"))},
+			attempts: 1,
+		},
+		{
+			name: "retries stop at the deadline of the group",
+			errs: []error{
+				apierrors.NewInternalError(errors.New("internal")),
+				apierrors.NewInternalError(errors.New("internal")),
+				apierrors.NewInternalError(errors.New("internal")),
+			},
+			timeout:  250 * time.Millisecond,
+			attempts: 2,
+		},
+	}
+
+	for _, test := range tests {
+		var lock sync.Mutex
+		errs := test.errs
+		var forgotten []string
+		sched := &Scheduler{
+			config: &Config{
+				SchedulerCache: &schedulertesting.FakeCache{
+					ForgetFunc: func(pod *v1.Pod) { forgotten = append(forgotten, pod.Name) },
+				},
+				Binder: fakeBinder{func(b *v1.Binding) error {
+					lock.Lock()
+					defer lock.Unlock()
+					if len(errs) == 0 {
+						return nil
+					}
+					err := errs[0]
+					errs = errs[1:]
+					return err
+				}},
+				Binding:             BindingPolicy{Timeout: test.timeout},
+				PodConditionUpdater: fakePodConditionUpdater{},
+				Recorder:            &record.FakeRecorder{},
+			},
+		}
+		pod := &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "bar"}, Spec: v1.PodSpec{NodeName: "machine1"}}
+		group := &schedulerapi.SchedulingGroup{Group: "bar/group", Status: &schedulerapi.SchedulerGroupState{}}
+
+		results := sched.bindGroup(group, []*v1.Pod{pod})
+		if len(results) != 1 {
+			t.Fatalf("%s: expected one result, got %v", test.name, results)
+		}
+		result := results[0]
+		if result.attempts != test.attempts {
+			t.Errorf("%s: expected %d attempts, got %d", test.name, test.attempts, result.attempts)
+		}
+		if bound := result.err == nil; bound != test.bound {
+			t.Errorf("%s: expected bound %v, got error %v", test.name, test.bound, result.err)
+		}
+		if result.node != "machine1" || pod.Spec.NodeName != "" {
+			t.Errorf("%s: expected pod bound to machine1 and its node reset, got %q and %q", test.name, result.node, pod.Spec.NodeName)
+		}
+		if forgot := len(forgotten) == 1; forgot == test.bound {
+			t.Errorf("%s: expected pod forgotten only if not bound, got %v", test.name, forgotten)
+		}
+	}
+}
+
+func TestRecordBindings(t *testing.T) {
+	newPod := func(name string) *v1.Pod {
+		return &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "bar"}}
+	}
+	ps, worker0, worker1 := newPod("ps-0"), newPod("worker-0"), newPod("worker-1")
+	psRole := &schedulerapi.ResourceObject{
+		Role:            "ps",
+		PendingPods:     map[string]*v1.Pod{ps.Name: ps},
+		PendingPodCount: 1,
+		Min:             1,
+		Max:             1,
+	}
+	workerRole := &schedulerapi.ResourceObject{
+		Role:            "worker",
+		PendingPods:     map[string]*v1.Pod{worker0.Name: worker0, worker1.Name: worker1},
+		PendingPodCount: 2,
+		Min:             2,
+		Max:             2,
+	}
+	group := &schedulerapi.SchedulingGroup{
+		Group:         "bar/group",
+		ResourceCount: 2,
+		Resources:     []*schedulerapi.ResourceObject{psRole, workerRole},
+		Status:        &schedulerapi.SchedulerGroupState{},
+	}
+	var recorded []*v1.Pod
+	sched := &Scheduler{config: &Config{
+		RecordSchedulingGroupBindings: func(_ *schedulerapi.SchedulingGroup, bound []*v1.Pod) {
+			recorded = bound
+		},
+	}}
+
+	failed := sched.recordBindings(group, group.Resources, []bindingResult{
+		{pod: ps, node: "machine1", attempts: 1},
+		{pod: worker0, node: "machine1", attempts: 1},
+		{pod: worker1, node: "machine2", attempts: 1, err: errors.New("forbidden")},
+	})
+	if failed != 1 || group.Status.BindingFailures[worker1.Name] != "forbidden" {
+		t.Errorf("expected the binding of %s to be reported failed, got %v", worker1.Name, group.Status.BindingFailures)
+	}
+	if !psRole.Bound || psRole.PendingPodCount != 0 || psRole.BoundPodCount != 1 {
+		t.Errorf("expected role ps to be bound, got %+v", psRole)
+	}
+	if workerRole.Bound || workerRole.PendingPodCount != 1 || workerRole.BoundPodCount != 1 {
+		t.Errorf("expected role worker to have one member bound and one pending, got %+v", workerRole)
+	}
+	if _, ok := workerRole.PendingPods[worker1.Name]; !ok {
+		t.Errorf("expected pod %s to be pending again", worker1.Name)
+	}
+	if len(recorded) != 2 {
+		t.Errorf("expected the 2 pods bound to be recorded, got %d", len(recorded))
+	}
+	if !sched.readyToScheduler(group) {
+		t.Errorf("expected group to be ready to schedule the remaining member")
+	}
+}
@@ -24,6 +24,7 @@ import (
 	"k8s.io/kubernetes/pkg/api/v1"
 	hashutil "k8s.io/kubernetes/pkg/util/hash"
 	"k8s.io/kubernetes/plugin/pkg/scheduler/algorithm"
+	"k8s.io/kubernetes/plugin/pkg/scheduler/tools"
 
 	"github.com/golang/glog"
 	"github.com/golang/groupcache/lru"
@@ -32,6 +33,20 @@ import (
 // we use predicate names as cache's key, its count is limited
 const maxCacheEntries = 100
 
+var (
+	// resourcePredicates depend on the pods requesting resources and ports on the node.
+	resourcePredicates = sets.NewString("GeneralPredicates", "PodFitsResources", "PodFitsHostPorts", "PodFitsPorts")
+	// volumePredicates depend on the volumes of the pods on the node.
+	volumePredicates = sets.NewString("NoDiskConflict", "MaxEBSVolumeCount", "MaxGCEPDVolumeCount", "MaxAzureDiskVolumeCount")
+	// interPodAffinityPredicates depend on the pods on all nodes of a topology domain.
+	interPodAffinityPredicates = sets.NewString("MatchInterPodAffinity")
+	// groupPredicates depend on the members of a scheduling group placed so far, on any node.
+	groupPredicates = sets.NewString("MatchRoleConstraints", "MatchRoleSpread", "MatchAcceleratorType")
+	// candidatePredicates depend on the nodes a pod is scheduled among, see
+	// algorithm.CandidateNodesMetadata. Their results are not cached.
+	candidatePredicates = sets.NewString("MatchRoleSpread")
+)
+
 type HostPredicate struct {
 	Fit         bool
 	FailReasons []algorithm.PredicateFailureReason
@@ -56,19 +71,64 @@ type EquivalenceCache struct {
 	sync.RWMutex
 	getEquivalencePod algorithm.GetEquivalencePodFunc
 	algorithmCache    map[string]AlgorithmCache
+	// invalidations counts the invalidations of all nodes, nodeInvalidations the invalidations
+	// of each node on its own.
+	invalidations     uint64
+	nodeInvalidations map[string]uint64
+}
+
+// Generation identifies the cached predicates of the nodes as of a point in time. Results
+// computed on node infos taken after it are stored only if no node they depend on was
+// invalidated since, otherwise they could be stale already.
+type Generation struct {
+	invalidations     uint64
+	nodeInvalidations map[string]uint64
 }
 
 func NewEquivalenceCache(getEquivalencePodFunc algorithm.GetEquivalencePodFunc) *EquivalenceCache {
 	return &EquivalenceCache{
 		getEquivalencePod: getEquivalencePodFunc,
 		algorithmCache:    make(map[string]AlgorithmCache),
+		nodeInvalidations: make(map[string]uint64),
 	}
 }
 
-// UpdateCachedPredicateItem updates pod predicate for equivalence class
-func (ec *EquivalenceCache) UpdateCachedPredicateItem(pod *v1.Pod, nodeName, predicateKey string, fit bool, reasons []algorithm.PredicateFailureReason, equivalenceHash uint64) {
+// NewEmpty returns an empty equivalence cache classing pods like ec, e.g. for the predicates
+// evaluated on the snapshot of a worker. It is nil for a nil cache.
+func (ec *EquivalenceCache) NewEmpty() *EquivalenceCache {
+	if ec == nil {
+		return nil
+	}
+	return NewEquivalenceCache(ec.getEquivalencePod)
+}
+
+// Generation returns the current generation of the cached predicates, to be taken before the
+// node infos the predicates are evaluated on. It is nil for a nil cache.
+func (ec *EquivalenceCache) Generation() *Generation {
+	if ec == nil {
+		return nil
+	}
+	ec.RLock()
+	defer ec.RUnlock()
+	generation := &Generation{
+		invalidations:     ec.invalidations,
+		nodeInvalidations: make(map[string]uint64, len(ec.nodeInvalidations)),
+	}
+	for nodeName, invalidations := range ec.nodeInvalidations {
+		generation.nodeInvalidations[nodeName] = invalidations
+	}
+	return generation
+}
+
+// UpdateCachedPredicateItem updates pod predicate for equivalence class. The result is dropped
+// if the node was invalidated since the generation given, unless the generation is nil.
+func (ec *EquivalenceCache) UpdateCachedPredicateItem(pod *v1.Pod, nodeName, predicateKey string, fit bool, reasons []algorithm.PredicateFailureReason, equivalenceHash uint64, generation *Generation) {
 	ec.Lock()
 	defer ec.Unlock()
+	if generation != nil && (generation.invalidations != ec.invalidations || generation.nodeInvalidations[nodeName] != ec.nodeInvalidations[nodeName]) {
+		glog.V(5).Infof("Dropped stale predicate: %v for pod: %v on node: %s", predicateKey, pod.GetName(), nodeName)
+		return
+	}
 	if _, exist := ec.algorithmCache[nodeName]; !exist {
 		ec.algorithmCache[nodeName] = newAlgorithmCache()
 	}
@@ -125,6 +185,7 @@ func (ec *EquivalenceCache) InvalidateCachedPredicateItem(nodeName string, predi
 	}
 	ec.Lock()
 	defer ec.Unlock()
+	ec.nodeInvalidations[nodeName]++
 	if algorithmCache, exist := ec.algorithmCache[nodeName]; exist {
 		for predicateKey := range predicateKeys {
 			algorithmCache.predicatesCache.Remove(predicateKey)
@@ -140,6 +201,7 @@ func (ec *EquivalenceCache) InvalidateCachedPredicateItemOfAllNodes(predicateKey
 	}
 	ec.Lock()
 	defer ec.Unlock()
+	ec.invalidations++
 	// algorithmCache uses nodeName as key, so we just iterate it and invalid given predicates
 	for _, algorithmCache := range ec.algorithmCache {
 		for predicateKey := range predicateKeys {
@@ -154,10 +216,21 @@ func (ec *EquivalenceCache) InvalidateCachedPredicateItemOfAllNodes(predicateKey
 func (ec *EquivalenceCache) InvalidateAllCachedPredicateItemOfNode(nodeName string) {
 	ec.Lock()
 	defer ec.Unlock()
+	ec.nodeInvalidations[nodeName]++
 	delete(ec.algorithmCache, nodeName)
 	glog.V(5).Infof("Done invalidating all cached predicates on node: %s", nodeName)
 }
 
+// InvalidateAll marks all cached items on all nodes as invalid, e.g. when the snapshot they
+// were evaluated on is updated.
+func (ec *EquivalenceCache) InvalidateAll() {
+	ec.Lock()
+	defer ec.Unlock()
+	ec.invalidations++
+	ec.algorithmCache = make(map[string]AlgorithmCache)
+	glog.V(5).Infof("Done invalidating all cached predicates")
+}
+
 // InvalidateCachedPredicateItemForPod marks item of given predicateKeys, of given pod, on the given node as invalid
 func (ec *EquivalenceCache) InvalidateCachedPredicateItemForPod(nodeName string, predicateKeys sets.String, pod *v1.Pod) {
 	if len(predicateKeys) == 0 {
@@ -170,6 +243,7 @@ func (ec *EquivalenceCache) InvalidateCachedPredicateItemForPod(nodeName string,
 	}
 	ec.Lock()
 	defer ec.Unlock()
+	ec.nodeInvalidations[nodeName]++
 	if algorithmCache, exist := ec.algorithmCache[nodeName]; exist {
 		for predicateKey := range predicateKeys {
 			if cachePredicate, exist := algorithmCache.predicatesCache.Get(predicateKey); exist {
@@ -186,7 +260,8 @@ func (ec *EquivalenceCache) InvalidateCachedPredicateItemForPod(nodeName string,
 func (ec *EquivalenceCache) InvalidateCachedPredicateItemForPodAdd(pod *v1.Pod, nodeName string) {
 	// MatchInterPodAffinity: we assume scheduler can make sure newly binded pod
 	// will not break the existing inter pod affinity. So we does not need to invalidate
-	// MatchInterPodAffinity when pod added.
+	// MatchInterPodAffinity when pod added, unless the pod brings affinity terms of its own,
+	// which the next members of its gang role are checked against.
 	//
 	// But when a pod is deleted, existing inter pod affinity may become invalid.
 	// (e.g. this pod was preferred by some else, or vice versa)
@@ -195,11 +270,39 @@ func (ec *EquivalenceCache) InvalidateCachedPredicateItemForPodAdd(pod *v1.Pod,
 	// RequiredDuringSchedulingRequiredDuringExecution.
 
 	// NoDiskConflict: the newly scheduled pod fits to existing pods on this node,
-	// it will also fits to equivalence class of existing pods
+	// it will also fits to equivalence class of existing pods. This doesn't hold for the
+	// next members of a gang role mounting the same disks, so the volume predicates are
+	// invalidated for pods with volumes.
 
 	// GeneralPredicates: will always be affected by adding a new pod
-	invalidPredicates := sets.NewString("GeneralPredicates")
+	ec.invalidateCachedPredicateItemForPod(pod, nodeName)
+	if pod.Spec.Affinity != nil && (pod.Spec.Affinity.PodAffinity != nil || pod.Spec.Affinity.PodAntiAffinity != nil) {
+		ec.InvalidateCachedPredicateItemOfAllNodes(interPodAffinityPredicates)
+	}
+}
+
+// InvalidateCachedPredicateItemForPodDelete is a wrapper of InvalidateCachedPredicateItem for
+// pod delete case, including pods which were assumed and are forgotten again.
+func (ec *EquivalenceCache) InvalidateCachedPredicateItemForPodDelete(pod *v1.Pod, nodeName string) {
+	ec.invalidateCachedPredicateItemForPod(pod, nodeName)
+	ec.InvalidateCachedPredicateItemOfAllNodes(interPodAffinityPredicates)
+}
+
+// invalidateCachedPredicateItemForPod invalidates the predicates affected by the pod either
+// way: the resource predicates and, for pods with volumes, the volume predicates of its node,
+// and the predicates counting the placed members of its scheduling group on all nodes.
+func (ec *EquivalenceCache) invalidateCachedPredicateItemForPod(pod *v1.Pod, nodeName string) {
+	if len(nodeName) == 0 {
+		return
+	}
+	invalidPredicates := sets.NewString(resourcePredicates.List()...)
+	if len(pod.Spec.Volumes) != 0 {
+		invalidPredicates.Insert(volumePredicates.List()...)
+	}
 	ec.InvalidateCachedPredicateItem(nodeName, invalidPredicates)
+	if _, ok := pod.Annotations[tools.SchedulingGroup]; ok {
+		ec.InvalidateCachedPredicateItemOfAllNodes(groupPredicates)
+	}
 }
 
 // getHashEquivalencePod returns the hash of equivalence pod.
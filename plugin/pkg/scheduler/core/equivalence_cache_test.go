@@ -25,6 +25,7 @@ import (
 	"k8s.io/kubernetes/pkg/api/v1"
 	"k8s.io/kubernetes/plugin/pkg/scheduler/algorithm"
 	"k8s.io/kubernetes/plugin/pkg/scheduler/algorithm/predicates"
+	"k8s.io/kubernetes/plugin/pkg/scheduler/tools"
 )
 
 func TestUpdateCachedPredicateItem(t *testing.T) {
@@ -54,7 +55,7 @@ func TestUpdateCachedPredicateItem(t *testing.T) {
 		// this case does not need to calculate equivalence hash, just pass an empty function
 		fakeGetEquivalencePodFunc := func(pod *v1.Pod) interface{} { return nil }
 		ecache := NewEquivalenceCache(fakeGetEquivalencePodFunc)
-		ecache.UpdateCachedPredicateItem(test.pod, test.nodeName, test.predicateKey, test.fit, test.reasons, test.equivalenceHash)
+		ecache.UpdateCachedPredicateItem(test.pod, test.nodeName, test.predicateKey, test.fit, test.reasons, test.equivalenceHash, nil)
 
 		value, ok := ecache.algorithmCache[test.nodeName].predicatesCache.Get(test.predicateKey)
 		if !ok {
@@ -68,6 +69,75 @@ func TestUpdateCachedPredicateItem(t *testing.T) {
 	}
 }
 
+// TestUpdateCachedPredicateItemAfterInvalidation tests that results computed before the node
+// was invalidated are not cached.
+func TestUpdateCachedPredicateItemAfterInvalidation(t *testing.T) {
+	tests := []struct {
+		name        string
+		invalidate  func(ecache *EquivalenceCache)
+		expectCache bool
+	}{
+		{
+			name:        "nothing invalidated",
+			invalidate:  func(ecache *EquivalenceCache) {},
+			expectCache: true,
+		},
+		{
+			name: "node invalidated",
+			invalidate: func(ecache *EquivalenceCache) {
+				ecache.InvalidateCachedPredicateItem("node1", sets.NewString("GeneralPredicates"))
+			},
+		},
+		{
+			name: "other node invalidated",
+			invalidate: func(ecache *EquivalenceCache) {
+				ecache.InvalidateAllCachedPredicateItemOfNode("node2")
+			},
+			expectCache: true,
+		},
+		{
+			name: "all nodes invalidated",
+			invalidate: func(ecache *EquivalenceCache) {
+				ecache.InvalidateCachedPredicateItemOfAllNodes(sets.NewString("MatchInterPodAffinity"))
+			},
+		},
+		{
+			name: "all predicates invalidated",
+			invalidate: func(ecache *EquivalenceCache) {
+				ecache.InvalidateAll()
+			},
+		},
+	}
+	for _, test := range tests {
+		ecache := NewEquivalenceCache(func(pod *v1.Pod) interface{} { return nil })
+		generation := ecache.Generation()
+		test.invalidate(ecache)
+		ecache.UpdateCachedPredicateItem(&v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "testPod"}}, "node1", "GeneralPredicates", true, nil, 123, generation)
+
+		_, cached := ecache.algorithmCache["node1"]
+		if cached != test.expectCache {
+			t.Errorf("Failed : %s, expected cached %v, got %v", test.name, test.expectCache, cached)
+		}
+	}
+}
+
+func TestInvalidateAll(t *testing.T) {
+	ecache := NewEquivalenceCache(func(pod *v1.Pod) interface{} { return nil })
+	pod := &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "testPod"}}
+	for _, nodeName := range []string{"node1", "node2"} {
+		ecache.UpdateCachedPredicateItem(pod, nodeName, "GeneralPredicates", true, nil, 123, nil)
+	}
+	ecache.InvalidateAll()
+	for _, nodeName := range []string{"node1", "node2"} {
+		if _, _, invalid := ecache.PredicateWithECache(pod, nodeName, "GeneralPredicates", 123); !invalid {
+			t.Errorf("expected the predicate cached on %s to be invalid", nodeName)
+		}
+	}
+	if empty := ecache.NewEmpty(); empty == nil || len(empty.algorithmCache) != 0 {
+		t.Errorf("expected an empty equivalence cache, got %v", empty)
+	}
+}
+
 type predicateItemType struct {
 	fit     bool
 	reasons []algorithm.PredicateFailureReason
@@ -107,7 +177,7 @@ func TestInvalidateCachedPredicateItem(t *testing.T) {
 		fakeGetEquivalencePodFunc := func(pod *v1.Pod) interface{} { return nil }
 		ecache := NewEquivalenceCache(fakeGetEquivalencePodFunc)
 		// set cached item to equivalence cache
-		ecache.UpdateCachedPredicateItem(test.pod, test.nodeName, test.predicateKey, test.cachedItem.fit, test.cachedItem.reasons, test.equivalenceHash)
+		ecache.UpdateCachedPredicateItem(test.pod, test.nodeName, test.predicateKey, test.cachedItem.fit, test.cachedItem.reasons, test.equivalenceHash, nil)
 		// if we want to do invalid, invalid the cached item
 		if test.expectedInvalid {
 			predicateKeys := sets.NewString()
@@ -129,3 +199,71 @@ func TestInvalidateCachedPredicateItem(t *testing.T) {
 		}
 	}
 }
+
+func TestInvalidateCachedPredicateItemForPodAdd(t *testing.T) {
+	groupPod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "worker-0",
+			Namespace:   "default",
+			Annotations: map[string]string{tools.SchedulingGroup: `{"group":"default/job","role":"worker"}`},
+		},
+	}
+	tests := []struct {
+		name          string
+		pod           *v1.Pod
+		nodeName      string
+		expectInvalid map[string]sets.String
+	}{
+		{
+			name:     "pod added invalidates the resource predicates of its node",
+			pod:      &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod", Namespace: "default"}},
+			nodeName: "node1",
+			expectInvalid: map[string]sets.String{
+				"node1": sets.NewString("GeneralPredicates"),
+				"node2": sets.NewString(),
+			},
+		},
+		{
+			name:     "group member added invalidates the group predicates of all nodes",
+			pod:      groupPod,
+			nodeName: "node1",
+			expectInvalid: map[string]sets.String{
+				"node1": sets.NewString("GeneralPredicates", "MatchRoleSpread"),
+				"node2": sets.NewString("MatchRoleSpread"),
+			},
+		},
+		{
+			name:     "pod without node invalidates nothing",
+			pod:      groupPod,
+			nodeName: "",
+			expectInvalid: map[string]sets.String{
+				"node1": sets.NewString(),
+				"node2": sets.NewString(),
+			},
+		},
+	}
+	predicateKeys := []string{"GeneralPredicates", "MatchRoleSpread", "PodToleratesNodeTaints"}
+
+	for _, test := range tests {
+		// this case does not need to calculate equivalence hash, just pass an empty function
+		fakeGetEquivalencePodFunc := func(pod *v1.Pod) interface{} { return nil }
+		ecache := NewEquivalenceCache(fakeGetEquivalencePodFunc)
+		for nodeName := range test.expectInvalid {
+			for _, predicateKey := range predicateKeys {
+				ecache.UpdateCachedPredicateItem(test.pod, nodeName, predicateKey, true, []algorithm.PredicateFailureReason{}, 123, nil)
+			}
+		}
+
+		ecache.InvalidateCachedPredicateItemForPodAdd(test.pod, test.nodeName)
+
+		for nodeName, expectInvalid := range test.expectInvalid {
+			for _, predicateKey := range predicateKeys {
+				_, _, invalid := ecache.PredicateWithECache(test.pod, nodeName, predicateKey, 123)
+				if invalid != expectInvalid.Has(predicateKey) {
+					t.Errorf("Failed : %s, expected %s on %s invalid: %v, but got: %v",
+						test.name, predicateKey, nodeName, expectInvalid.Has(predicateKey), invalid)
+				}
+			}
+		}
+	}
+}
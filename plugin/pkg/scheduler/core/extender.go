@@ -18,17 +18,23 @@ package core
 
 import (
 	"bytes"
+	"crypto/tls"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"net/http"
 	"time"
 
+	"github.com/golang/glog"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	utilnet "k8s.io/apimachinery/pkg/util/net"
+	"k8s.io/apimachinery/pkg/util/sets"
 	restclient "k8s.io/client-go/rest"
 	"k8s.io/kubernetes/pkg/api/v1"
 	"k8s.io/kubernetes/plugin/pkg/scheduler/algorithm"
 	schedulerapi "k8s.io/kubernetes/plugin/pkg/scheduler/api"
+	"k8s.io/kubernetes/plugin/pkg/scheduler/metrics"
 	"k8s.io/kubernetes/plugin/pkg/scheduler/schedulercache"
 )
 
@@ -36,18 +42,22 @@ const (
 	DefaultExtenderTimeout = 5 * time.Second
 )
 
-// HTTPExtender implements the algorithm.SchedulerExtender interface.
+// HTTPExtender implements the algorithm.SchedulerExtender and algorithm.GroupExtender interfaces.
 type HTTPExtender struct {
-	extenderURL      string
-	filterVerb       string
-	prioritizeVerb   string
-	bindVerb         string
-	weight           int
-	client           *http.Client
-	nodeCacheCapable bool
+	*extenderCalls
+	extenderInterest
+	filterVerb          string
+	prioritizeVerb      string
+	bindVerb            string
+	groupFilterVerb     string
+	groupPrioritizeVerb string
+	groupBindVerb       string
+	weight              int
+	client              *http.Client
+	nodeCacheCapable    bool
 }
 
-func makeTransport(config *schedulerapi.ExtenderConfig) (http.RoundTripper, error) {
+func makeTLSConfig(config *schedulerapi.ExtenderConfig) (*tls.Config, error) {
 	var cfg restclient.Config
 	if config.TLSConfig != nil {
 		cfg.TLSClientConfig = *config.TLSConfig
@@ -58,7 +68,11 @@ func makeTransport(config *schedulerapi.ExtenderConfig) (http.RoundTripper, erro
 			cfg.Insecure = true
 		}
 	}
-	tlsConfig, err := restclient.TLSConfigFor(&cfg)
+	return restclient.TLSConfigFor(&cfg)
+}
+
+func makeTransport(config *schedulerapi.ExtenderConfig) (http.RoundTripper, error) {
+	tlsConfig, err := makeTLSConfig(config)
 	if err != nil {
 		return nil, err
 	}
@@ -83,15 +97,25 @@ func NewHTTPExtender(config *schedulerapi.ExtenderConfig) (algorithm.SchedulerEx
 		Transport: transport,
 		Timeout:   config.HTTPTimeout,
 	}
-	return &HTTPExtender{
-		extenderURL:      config.URLPrefix,
-		filterVerb:       config.FilterVerb,
-		prioritizeVerb:   config.PrioritizeVerb,
-		bindVerb:         config.BindVerb,
-		weight:           config.Weight,
-		client:           client,
-		nodeCacheCapable: config.NodeCacheCapable,
-	}, nil
+	interest, err := newExtenderInterest(config)
+	if err != nil {
+		return nil, err
+	}
+	h := &HTTPExtender{
+		extenderCalls:       newExtenderCalls(config),
+		extenderInterest:    interest,
+		filterVerb:          config.FilterVerb,
+		prioritizeVerb:      config.PrioritizeVerb,
+		bindVerb:            config.BindVerb,
+		groupFilterVerb:     config.GroupFilterVerb,
+		groupPrioritizeVerb: config.GroupPrioritizeVerb,
+		groupBindVerb:       config.GroupBindVerb,
+		weight:              config.Weight,
+		client:              client,
+		nodeCacheCapable:    config.NodeCacheCapable,
+	}
+	registerExtender(h.extenderCalls)
+	return h, nil
 }
 
 // Filter based on extender implemented predicate functions. The filtered list is
@@ -129,11 +153,16 @@ func (h *HTTPExtender) Filter(pod *v1.Pod, nodes []*v1.Node, nodeNameToInfo map[
 		NodeNames: nodeNames,
 	}
 
-	if err := h.send(h.filterVerb, args, &result); err != nil {
-		return nil, nil, err
+	err := h.send(h.filterVerb, true, args, &result)
+	if err == nil && result.Error != "" {
+		metrics.ExtenderErrors.WithLabelValues(h.extenderURL, h.filterVerb).Inc()
+		err = fmt.Errorf(result.Error)
 	}
-	if result.Error != "" {
-		return nil, nil, fmt.Errorf(result.Error)
+	if err != nil {
+		if h.ignore(h.filterVerb, err) {
+			return nodes, schedulerapi.FailedNodesMap{}, nil
+		}
+		return nil, nil, err
 	}
 
 	if h.nodeCacheCapable && result.NodeNames != nil {
@@ -189,7 +218,7 @@ func (h *HTTPExtender) Prioritize(pod *v1.Pod, nodes []*v1.Node) (*schedulerapi.
 		NodeNames: nodeNames,
 	}
 
-	if err := h.send(h.prioritizeVerb, args, &result); err != nil {
+	if err := h.send(h.prioritizeVerb, true, args, &result); err != nil {
 		return nil, 0, err
 	}
 	return &result, h.weight, nil
@@ -208,10 +237,11 @@ func (h *HTTPExtender) Bind(binding *v1.Binding) error {
 		PodUID:       binding.UID,
 		Node:         binding.Target.Name,
 	}
-	if err := h.send(h.bindVerb, &req, &result); err != nil {
+	if err := h.send(h.bindVerb, false, &req, &result); err != nil {
 		return err
 	}
 	if result.Error != "" {
+		metrics.ExtenderErrors.WithLabelValues(h.extenderURL, h.bindVerb).Inc()
 		return fmt.Errorf(result.Error)
 	}
 	return nil
@@ -222,8 +252,65 @@ func (h *HTTPExtender) IsBinder() bool {
 	return h.bindVerb != ""
 }
 
-// Helper function to send messages to the extender
-func (h *HTTPExtender) send(action string, args interface{}, result interface{}) error {
+// extenderInterest selects the pods an extender is called for.
+type extenderInterest struct {
+	managedResources sets.String
+	selector         labels.Selector
+}
+
+func newExtenderInterest(config *schedulerapi.ExtenderConfig) (extenderInterest, error) {
+	interest := extenderInterest{managedResources: sets.NewString(config.ManagedResources...)}
+	if config.LabelSelector != nil {
+		selector, err := metav1.LabelSelectorAsSelector(config.LabelSelector)
+		if err != nil {
+			return interest, err
+		}
+		interest.selector = selector
+	}
+	return interest, nil
+}
+
+// IsInterested returns true if the pod requests or limits a resource managed by the extender,
+// or is matched by its label selector. It is interested in all pods if neither is configured.
+func (i extenderInterest) IsInterested(pod *v1.Pod) bool {
+	if i.managedResources.Len() == 0 && i.selector == nil {
+		return true
+	}
+	if i.selector != nil && i.selector.Matches(labels.Set(pod.Labels)) {
+		return true
+	}
+	if i.managedResources.Len() == 0 {
+		return false
+	}
+	return i.hasManagedResources(pod.Spec.Containers) || i.hasManagedResources(pod.Spec.InitContainers)
+}
+
+func (i extenderInterest) hasManagedResources(containers []v1.Container) bool {
+	for j := range containers {
+		for resourceName := range containers[j].Resources.Requests {
+			if i.managedResources.Has(string(resourceName)) {
+				return true
+			}
+		}
+		for resourceName := range containers[j].Resources.Limits {
+			if i.managedResources.Has(string(resourceName)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Helper function to send messages to the extender. If retry is set, calls failing to get a
+// response or getting a server error are retried. The extender is skipped while its circuit
+// is open.
+func (h *HTTPExtender) send(action string, retry bool, args interface{}, result interface{}) error {
+	return h.call(action, retry, func() error {
+		return h.sendOnce(action, args, result)
+	})
+}
+
+func (h *HTTPExtender) sendOnce(action string, args interface{}, result interface{}) error {
 	out, err := json.Marshal(args)
 	if err != nil {
 		return err
@@ -240,14 +327,18 @@ func (h *HTTPExtender) send(action string, args interface{}, result interface{})
 
 	resp, err := h.client.Do(req)
 	if err != nil {
-		return err
+		return transientError{err}
 	}
 
+	defer resp.Body.Close()
 	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("Failed %v with extender at URL %v, code %v", action, h.extenderURL, resp.StatusCode)
+		err := fmt.Errorf("Failed %v with extender at URL %v, code %v", action, h.extenderURL, resp.StatusCode)
+		if resp.StatusCode >= http.StatusInternalServerError {
+			return transientError{err}
+		}
+		return err
 	}
 
-	defer resp.Body.Close()
 	body, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
 		return err
@@ -0,0 +1,257 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+	"k8s.io/kubernetes/plugin/pkg/scheduler/algorithm"
+	schedulerapi "k8s.io/kubernetes/plugin/pkg/scheduler/api"
+	"k8s.io/kubernetes/plugin/pkg/scheduler/metrics"
+)
+
+const (
+	DefaultExtenderRetryBackoff        = 100 * time.Millisecond
+	DefaultExtenderCircuitOpenDuration = 30 * time.Second
+)
+
+// CircuitState is the state of the circuit breaker of an extender.
+type CircuitState string
+
+const (
+	// CircuitClosed lets all calls through to the extender.
+	CircuitClosed CircuitState = "Closed"
+	// CircuitOpen skips the extender after too many calls in a row failed.
+	CircuitOpen CircuitState = "Open"
+	// CircuitHalfOpen lets one call through to find out if the extender recovered.
+	CircuitHalfOpen CircuitState = "HalfOpen"
+)
+
+// ExtenderHealth is the health of an extender, as exposed on the debug endpoint.
+type ExtenderHealth struct {
+	URLPrefix           string       `json:"urlPrefix"`
+	Ignorable           bool         `json:"ignorable"`
+	State               CircuitState `json:"state"`
+	ConsecutiveFailures int          `json:"consecutiveFailures"`
+	LastError           string       `json:"lastError,omitempty"`
+	LastFailure         *time.Time   `json:"lastFailure,omitempty"`
+}
+
+// circuitBreaker skips an extender for a while once a number of calls in a row failed.
+type circuitBreaker struct {
+	lock         sync.Mutex
+	extenderURL  string
+	threshold    int
+	openDuration time.Duration
+	state        CircuitState
+	failures     int
+	openedAt     time.Time
+	lastError    error
+	lastFailure  time.Time
+}
+
+func newCircuitBreaker(extenderURL string, threshold int, openDuration time.Duration) *circuitBreaker {
+	if openDuration == 0 {
+		openDuration = DefaultExtenderCircuitOpenDuration
+	}
+	return &circuitBreaker{
+		extenderURL:  extenderURL,
+		threshold:    threshold,
+		openDuration: openDuration,
+		state:        CircuitClosed,
+	}
+}
+
+// allow returns an error if the extender is to be skipped. Once the circuit was open for its
+// duration, a single call is let through.
+func (c *circuitBreaker) allow(now time.Time) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	switch c.state {
+	case CircuitOpen:
+		if now.Sub(c.openedAt) < c.openDuration {
+			return fmt.Errorf("extender at URL %v is skipped after %d failed calls in a row: %v", c.extenderURL, c.failures, c.lastError)
+		}
+		c.setState(CircuitHalfOpen)
+	case CircuitHalfOpen:
+		return fmt.Errorf("extender at URL %v is skipped while it is probed: %v", c.extenderURL, c.lastError)
+	}
+	return nil
+}
+
+// record records the outcome of a call let through.
+func (c *circuitBreaker) record(err error, now time.Time) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if err == nil {
+		c.failures = 0
+		c.setState(CircuitClosed)
+		return
+	}
+	c.failures++
+	c.lastError = err
+	c.lastFailure = now
+	if c.state == CircuitHalfOpen || (c.threshold > 0 && c.failures >= c.threshold) {
+		c.openedAt = now
+		c.setState(CircuitOpen)
+	}
+}
+
+// Assumes that lock is already acquired.
+func (c *circuitBreaker) setState(state CircuitState) {
+	c.state = state
+	open := 0.0
+	if state != CircuitClosed {
+		open = 1
+	}
+	metrics.ExtenderCircuitOpen.WithLabelValues(c.extenderURL).Set(open)
+}
+
+func (c *circuitBreaker) health() ExtenderHealth {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	health := ExtenderHealth{
+		URLPrefix:           c.extenderURL,
+		State:               c.state,
+		ConsecutiveFailures: c.failures,
+	}
+	if c.lastError != nil {
+		lastFailure := c.lastFailure
+		health.LastError = c.lastError.Error()
+		health.LastFailure = &lastFailure
+	}
+	return health
+}
+
+// transientError is the error of a call which got no response from the extender, or a
+// server error. Only calls failing with it are retried.
+type transientError struct {
+	error
+}
+
+// extenderCalls retries the calls to an extender failing to get a response, and skips the
+// extender while its circuit is open. It is shared by the transports of extenders.
+type extenderCalls struct {
+	extenderURL  string
+	retries      int
+	retryBackoff time.Duration
+	ignorable    bool
+	breaker      *circuitBreaker
+}
+
+func newExtenderCalls(config *schedulerapi.ExtenderConfig) *extenderCalls {
+	retryBackoff := config.RetryBackoff
+	if retryBackoff == 0 {
+		retryBackoff = DefaultExtenderRetryBackoff
+	}
+	return &extenderCalls{
+		extenderURL:  config.URLPrefix,
+		retries:      config.Retries,
+		retryBackoff: retryBackoff,
+		ignorable:    config.Ignorable,
+		breaker:      newCircuitBreaker(config.URLPrefix, config.FailureThreshold, config.CircuitOpenDuration),
+	}
+}
+
+// call calls the extender for the action and records its latency and errors. If retry is
+// set, a call failing with a transientError is retried as configured. Filter and prioritize
+// are retried; bind is not, binding the same pod twice isn't safe, it is retried by the
+// scheduler once the outcome is known.
+func (c *extenderCalls) call(action string, retry bool, call func() error) error {
+	if err := c.breaker.allow(time.Now()); err != nil {
+		metrics.ExtenderSkips.WithLabelValues(c.extenderURL, action).Inc()
+		return err
+	}
+	var err error
+	backoff := c.retryBackoff
+	for attempt := 0; ; attempt++ {
+		start := time.Now()
+		err = call()
+		metrics.ExtenderLatency.WithLabelValues(c.extenderURL, action).Observe(metrics.SinceInMicroseconds(start))
+		if err == nil {
+			break
+		}
+		metrics.ExtenderErrors.WithLabelValues(c.extenderURL, action).Inc()
+		if _, ok := err.(transientError); !ok || !retry || attempt == c.retries {
+			break
+		}
+		glog.V(3).Infof("Retrying %v with extender at URL %v in %v: %v", action, c.extenderURL, backoff, err)
+		metrics.ExtenderRetries.WithLabelValues(c.extenderURL, action).Inc()
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+	c.breaker.record(err, time.Now())
+	return err
+}
+
+// ignore returns true if the failure of the extender is not to fail the scheduling.
+func (c *extenderCalls) ignore(action string, err error) bool {
+	if !c.ignorable {
+		return false
+	}
+	glog.V(2).Infof("Ignoring failed %v of extender at URL %v: %v", action, c.extenderURL, err)
+	return true
+}
+
+// extenders holds the extenders in use to expose their health. Each extender created from a
+// config is held on its own, the extenders of configs loaded one after another may share URLs.
+var extenders = struct {
+	lock sync.Mutex
+	all  map[*extenderCalls]bool
+}{all: map[*extenderCalls]bool{}}
+
+func registerExtender(c *extenderCalls) {
+	extenders.lock.Lock()
+	defer extenders.lock.Unlock()
+	extenders.all[c] = true
+}
+
+// UnregisterExtender stops exposing the health of the extender, once the config it was created
+// from is no longer used.
+func UnregisterExtender(extender algorithm.SchedulerExtender) {
+	registered, ok := extender.(interface {
+		calls() *extenderCalls
+	})
+	if !ok {
+		return
+	}
+	extenders.lock.Lock()
+	defer extenders.lock.Unlock()
+	delete(extenders.all, registered.calls())
+}
+
+func (c *extenderCalls) calls() *extenderCalls {
+	return c
+}
+
+// ExtendersHealth returns the health of the extenders in use, ordered by URL prefix.
+func ExtendersHealth() []ExtenderHealth {
+	extenders.lock.Lock()
+	defer extenders.lock.Unlock()
+	result := make([]ExtenderHealth, 0, len(extenders.all))
+	for c := range extenders.all {
+		health := c.breaker.health()
+		health.Ignorable = c.ignorable
+		result = append(result, health)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].URLPrefix < result[j].URLPrefix })
+	return result
+}
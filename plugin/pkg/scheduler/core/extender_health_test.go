@@ -0,0 +1,247 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kubernetes/pkg/api/v1"
+	schedulerapi "k8s.io/kubernetes/plugin/pkg/scheduler/api"
+	"k8s.io/kubernetes/plugin/pkg/scheduler/schedulercache"
+)
+
+// newFailingExtenderServer returns an extender whose filter fails the first failures calls
+// with the status and lets all nodes pass afterwards.
+func newFailingExtenderServer(status int, failures int32, calls *int32) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(calls, 1) <= failures {
+			w.WriteHeader(status)
+			return
+		}
+		var args schedulerapi.ExtenderArgs
+		json.NewDecoder(r.Body).Decode(&args)
+		json.NewEncoder(w).Encode(schedulerapi.ExtenderFilterResult{NodeNames: args.NodeNames})
+	}))
+}
+
+func TestHTTPExtenderResilience(t *testing.T) {
+	nodes := []*v1.Node{makeNode("machine1", 1000, 1000), makeNode("machine2", 1000, 1000)}
+	nodeNameToInfo := schedulercache.CreateNodeNameToInfoMap(nil, nodes)
+	tests := []struct {
+		name      string
+		config    schedulerapi.ExtenderConfig
+		status    int
+		failures  int32
+		filters   int
+		calls     int32
+		expectErr bool
+		state     CircuitState
+	}{
+		{
+			name:     "retried until the extender responds",
+			config:   schedulerapi.ExtenderConfig{Retries: 2, RetryBackoff: time.Millisecond},
+			failures: 2,
+			filters:  1,
+			calls:    3,
+			state:    CircuitClosed,
+		},
+		{
+			name:      "fails once the retries are used up",
+			config:    schedulerapi.ExtenderConfig{Retries: 1, RetryBackoff: time.Millisecond},
+			failures:  2,
+			filters:   1,
+			calls:     2,
+			expectErr: true,
+			state:     CircuitClosed,
+		},
+		{
+			name:      "client errors not retried",
+			config:    schedulerapi.ExtenderConfig{Retries: 2, RetryBackoff: time.Millisecond},
+			status:    http.StatusBadRequest,
+			failures:  1,
+			filters:   1,
+			calls:     1,
+			expectErr: true,
+			state:     CircuitClosed,
+		},
+		{
+			name:      "skipped after failures in a row",
+			config:    schedulerapi.ExtenderConfig{FailureThreshold: 2, CircuitOpenDuration: time.Hour},
+			failures:  10,
+			filters:   3,
+			calls:     2,
+			expectErr: true,
+			state:     CircuitOpen,
+		},
+		{
+			name:     "ignorable extender lets all nodes pass",
+			config:   schedulerapi.ExtenderConfig{FailureThreshold: 1, CircuitOpenDuration: time.Hour, Ignorable: true},
+			failures: 10,
+			filters:  2,
+			calls:    1,
+			state:    CircuitOpen,
+		},
+	}
+
+	for _, test := range tests {
+		var calls int32
+		status := test.status
+		if status == 0 {
+			status = http.StatusInternalServerError
+		}
+		server := newFailingExtenderServer(status, test.failures, &calls)
+		config := test.config
+		config.URLPrefix = server.URL
+		config.FilterVerb = "filter"
+		config.NodeCacheCapable = true
+		extender, err := NewHTTPExtender(&config)
+		if err != nil {
+			t.Fatalf("%s: failed to create extender: %v", test.name, err)
+		}
+
+		for i := 0; i < test.filters; i++ {
+			filtered, _, err := extender.Filter(&v1.Pod{}, nodes, nodeNameToInfo)
+			if last := i == test.filters-1; last && (err != nil) != test.expectErr {
+				t.Errorf("%s: expected error %v, got %v", test.name, test.expectErr, err)
+			}
+			if err == nil && len(filtered) != len(nodes) {
+				t.Errorf("%s: expected all nodes to pass, got %v", test.name, filtered)
+			}
+		}
+		if calls != test.calls {
+			t.Errorf("%s: expected %d calls to the extender, got %d", test.name, test.calls, calls)
+		}
+
+		found := false
+		for _, health := range ExtendersHealth() {
+			if health.URLPrefix != server.URL {
+				continue
+			}
+			found = true
+			if health.State != test.state || health.Ignorable != test.config.Ignorable {
+				t.Errorf("%s: unexpected health %+v", test.name, health)
+			}
+		}
+		if !found {
+			t.Errorf("%s: expected the health of the extender to be exposed", test.name)
+		}
+		server.Close()
+	}
+}
+
+func TestHTTPExtenderBindNotRetried(t *testing.T) {
+	var calls int32
+	server := newFailingExtenderServer(http.StatusInternalServerError, 1, &calls)
+	defer server.Close()
+	extender, err := NewHTTPExtender(&schedulerapi.ExtenderConfig{
+		URLPrefix:    server.URL,
+		BindVerb:     "bind",
+		Retries:      2,
+		RetryBackoff: time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("failed to create extender: %v", err)
+	}
+	binding := &v1.Binding{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "foo", Name: "bar"},
+		Target:     v1.ObjectReference{Kind: "Node", Name: "machine1"},
+	}
+	if err := extender.Bind(binding); err == nil {
+		t.Errorf("expected the failed binding to be returned")
+	}
+	if calls != 1 {
+		t.Errorf("expected the binding not to be retried, got %d calls", calls)
+	}
+}
+
+// TestUnregisterExtender tests that the extenders of configs sharing a URL are exposed on their
+// own, until the config they were created from is no longer used.
+func TestUnregisterExtender(t *testing.T) {
+	const url = "http://127.0.0.1:8081/unregistered"
+	exposed := func() int {
+		count := 0
+		for _, health := range ExtendersHealth() {
+			if health.URLPrefix == url {
+				count++
+			}
+		}
+		return count
+	}
+
+	previous, err := NewHTTPExtender(&schedulerapi.ExtenderConfig{URLPrefix: url, FilterVerb: "filter"})
+	if err != nil {
+		t.Fatalf("failed to create extender: %v", err)
+	}
+	reloaded, err := NewHTTPExtender(&schedulerapi.ExtenderConfig{URLPrefix: url, FilterVerb: "filter", Ignorable: true})
+	if err != nil {
+		t.Fatalf("failed to create extender: %v", err)
+	}
+	if count := exposed(); count != 2 {
+		t.Errorf("expected the health of both extenders to be exposed, got %d", count)
+	}
+
+	UnregisterExtender(previous)
+	if count := exposed(); count != 1 {
+		t.Errorf("expected the health of the extender in use to be exposed, got %d", count)
+	}
+	UnregisterExtender(reloaded)
+	if count := exposed(); count != 0 {
+		t.Errorf("expected no extender to be exposed, got %d", count)
+	}
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	now := time.Now()
+	breaker := newCircuitBreaker("http://127.0.0.1:8081/extender", 2, time.Minute)
+	failure := errors.New("connection refused")
+
+	breaker.record(failure, now)
+	if err := breaker.allow(now); err != nil {
+		t.Errorf("expected the circuit to be closed after one failure, got %v", err)
+	}
+	breaker.record(failure, now)
+	if err := breaker.allow(now.Add(time.Second)); err == nil {
+		t.Errorf("expected the circuit to be open after two failures in a row")
+	}
+
+	// One probe is let through once the circuit was open for its duration.
+	if err := breaker.allow(now.Add(time.Minute)); err != nil {
+		t.Errorf("expected a probe to be let through, got %v", err)
+	}
+	if err := breaker.allow(now.Add(time.Minute)); err == nil {
+		t.Errorf("expected only one probe to be let through")
+	}
+	breaker.record(failure, now.Add(time.Minute))
+	if breaker.health().State != CircuitOpen {
+		t.Errorf("expected the circuit to open again after the probe failed, got %+v", breaker.health())
+	}
+
+	if err := breaker.allow(now.Add(2 * time.Minute)); err != nil {
+		t.Errorf("expected a probe to be let through, got %v", err)
+	}
+	breaker.record(nil, now.Add(2*time.Minute))
+	if health := breaker.health(); health.State != CircuitClosed || health.ConsecutiveFailures != 0 {
+		t.Errorf("expected the circuit to be closed after the probe succeeded, got %+v", health)
+	}
+}
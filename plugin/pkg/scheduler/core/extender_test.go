@@ -21,6 +21,7 @@ import (
 	"testing"
 	"time"
 
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/kubernetes/pkg/api/v1"
@@ -110,6 +111,7 @@ type FakeExtender struct {
 	weight           int
 	nodeCacheCapable bool
 	filteredNodes    []*v1.Node
+	notInterested    bool
 }
 
 func (f *FakeExtender) Filter(pod *v1.Pod, nodes []*v1.Node, nodeNameToInfo map[string]*schedulercache.NodeInfo) ([]*v1.Node, schedulerapi.FailedNodesMap, error) {
@@ -183,6 +185,10 @@ func (f *FakeExtender) IsBinder() bool {
 	return true
 }
 
+func (f *FakeExtender) IsInterested(pod *v1.Pod) bool {
+	return !f.notInterested
+}
+
 func TestGenericSchedulerWithExtenders(t *testing.T) {
 	tests := []struct {
 		name                 string
@@ -302,6 +308,37 @@ func TestGenericSchedulerWithExtenders(t *testing.T) {
 			expectedHost: "machine2", // machine2 has higher score
 			name:         "test 7",
 		},
+		{
+			predicates:   map[string]algorithm.FitPredicate{"true": truePredicate},
+			prioritizers: []algorithm.PriorityConfig{{Map: EqualPriorityMap, Weight: 1}},
+			extenders: []FakeExtender{
+				{
+					predicates: []fitPredicate{truePredicateExtender},
+				},
+				{
+					predicates:    []fitPredicate{falsePredicateExtender},
+					notInterested: true,
+				},
+			},
+			nodes:        []string{"machine1"},
+			expectedHost: "machine1", // the extender failing all nodes is not called
+			name:         "test 8",
+		},
+		{
+			predicates:   map[string]algorithm.FitPredicate{"true": truePredicate},
+			prioritizers: []algorithm.PriorityConfig{{Function: machine2Prioritizer, Weight: 1}},
+			extenders: []FakeExtender{
+				{
+					predicates:    []fitPredicate{truePredicateExtender},
+					prioritizers:  []priorityConfig{{machine1PrioritizerExtender, 10}},
+					weight:        5,
+					notInterested: true,
+				},
+			},
+			nodes:        []string{"machine1", "machine2"},
+			expectedHost: "machine2", // the extender preferring machine1 is not called
+			name:         "test 9",
+		},
 	}
 
 	for _, test := range tests {
@@ -314,7 +351,7 @@ func TestGenericSchedulerWithExtenders(t *testing.T) {
 			cache.AddNode(&v1.Node{ObjectMeta: metav1.ObjectMeta{Name: name}})
 		}
 		scheduler := NewGenericScheduler(
-			cache, nil, test.predicates, algorithm.EmptyMetadataProducer, test.prioritizers, algorithm.EmptyMetadataProducer, extenders)
+			cache, nil, test.predicates, algorithm.EmptyMetadataProducer, test.prioritizers, algorithm.EmptyMetadataProducer, extenders, NodeSampling{}, PredicateEvaluation{})
 		podIgnored := &v1.Pod{}
 		machine, err := scheduler.Schedule(podIgnored, schedulertesting.FakeNodeLister(makeNodeList(test.nodes)))
 		if test.expectsErr {
@@ -332,3 +369,65 @@ func TestGenericSchedulerWithExtenders(t *testing.T) {
 		}
 	}
 }
+
+func TestHTTPExtenderIsInterested(t *testing.T) {
+	gpu := v1.ResourceName("example.com/gpu")
+	gpuPod := func(init bool, list v1.ResourceList) *v1.Pod {
+		containers := []v1.Container{{Resources: v1.ResourceRequirements{Requests: list}}}
+		if init {
+			return &v1.Pod{Spec: v1.PodSpec{InitContainers: containers}}
+		}
+		return &v1.Pod{Spec: v1.PodSpec{Containers: containers}}
+	}
+	tests := []struct {
+		name       string
+		config     schedulerapi.ExtenderConfig
+		pod        *v1.Pod
+		interested bool
+	}{
+		{
+			name:       "interested in all pods if not configured",
+			pod:        &v1.Pod{},
+			interested: true,
+		},
+		{
+			name:       "pod requesting a managed resource",
+			config:     schedulerapi.ExtenderConfig{ManagedResources: []string{string(gpu)}},
+			pod:        gpuPod(false, v1.ResourceList{gpu: resource.MustParse("1")}),
+			interested: true,
+		},
+		{
+			name:       "init container requesting a managed resource",
+			config:     schedulerapi.ExtenderConfig{ManagedResources: []string{string(gpu)}},
+			pod:        gpuPod(true, v1.ResourceList{gpu: resource.MustParse("1")}),
+			interested: true,
+		},
+		{
+			name:   "pod requesting other resources",
+			config: schedulerapi.ExtenderConfig{ManagedResources: []string{string(gpu)}},
+			pod:    gpuPod(false, v1.ResourceList{v1.ResourceCPU: resource.MustParse("1")}),
+		},
+		{
+			name: "pod matched by the label selector",
+			config: schedulerapi.ExtenderConfig{ManagedResources: []string{string(gpu)},
+				LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"accelerator": "gpu"}}},
+			pod:        &v1.Pod{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"accelerator": "gpu"}}},
+			interested: true,
+		},
+		{
+			name:   "pod not matched by the label selector",
+			config: schedulerapi.ExtenderConfig{LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"accelerator": "gpu"}}},
+			pod:    gpuPod(false, v1.ResourceList{gpu: resource.MustParse("1")}),
+		},
+	}
+	for _, test := range tests {
+		test.config.URLPrefix = "http://127.0.0.1:8081/extender"
+		extender, err := NewHTTPExtender(&test.config)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", test.name, err)
+		}
+		if interested := extender.IsInterested(test.pod); interested != test.interested {
+			t.Errorf("%s: expected interested %v, got %v", test.name, test.interested, interested)
+		}
+	}
+}
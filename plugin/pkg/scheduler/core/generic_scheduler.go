@@ -32,6 +32,7 @@ import (
 	"k8s.io/kubernetes/plugin/pkg/scheduler/algorithm"
 	"k8s.io/kubernetes/plugin/pkg/scheduler/algorithm/predicates"
 	schedulerapi "k8s.io/kubernetes/plugin/pkg/scheduler/api"
+	"k8s.io/kubernetes/plugin/pkg/scheduler/metrics"
 	"k8s.io/kubernetes/plugin/pkg/scheduler/schedulercache"
 )
 
@@ -46,6 +47,59 @@ var ErrNoNodesAvailable = fmt.Errorf("no nodes available to schedule pods")
 
 const NoNodeAvailableMsg = "No nodes are available that match all of the following predicates:"
 
+const (
+	// DefaultParallelism is the number of nodes the predicates and priorities are
+	// evaluated on at once, if not configured.
+	DefaultParallelism = 16
+	// minFeasibleNodesToFind is the least number of feasible nodes searched for when
+	// only a percentage of the nodes is to be found, so that small clusters are not sampled.
+	minFeasibleNodesToFind = 100
+)
+
+// NodeSampling configures on how many nodes a pod is evaluated. The search for feasible
+// nodes stops once enough are found, and only those are prioritized. Each search starts
+// where the previous one stopped, so that all nodes get their turn.
+type NodeSampling struct {
+	// Parallelism is the number of nodes evaluated at once. DefaultParallelism is used if zero.
+	Parallelism int
+	// NumFeasibleNodesToFind is the number of feasible nodes to find. All nodes are
+	// searched if zero.
+	NumFeasibleNodesToFind int
+	// PercentageOfNodesToFind is the number of feasible nodes to find, as a percentage of
+	// all nodes, but no less than 100 nodes. All nodes are searched if zero. If both are
+	// set, the larger number is found.
+	PercentageOfNodesToFind int
+}
+
+// parallelism returns the configured parallelism or the default.
+func (s NodeSampling) parallelism() int {
+	if s.Parallelism > 0 {
+		return s.Parallelism
+	}
+	return DefaultParallelism
+}
+
+// numFeasibleNodesToFind returns the number of feasible nodes to find among numAllNodes nodes.
+func (s NodeSampling) numFeasibleNodesToFind(numAllNodes int) int {
+	if s.NumFeasibleNodesToFind <= 0 && s.PercentageOfNodesToFind <= 0 {
+		return numAllNodes
+	}
+	numNodes := s.NumFeasibleNodesToFind
+	if s.PercentageOfNodesToFind > 0 {
+		fromPercentage := numAllNodes * s.PercentageOfNodesToFind / 100
+		if fromPercentage < minFeasibleNodesToFind {
+			fromPercentage = minFeasibleNodesToFind
+		}
+		if fromPercentage > numNodes {
+			numNodes = fromPercentage
+		}
+	}
+	if numNodes > numAllNodes {
+		return numAllNodes
+	}
+	return numNodes
+}
+
 // Error returns detailed information of why the pod failed to fit on each node
 func (f *FitError) Error() string {
 	reasons := make(map[string]int)
@@ -78,6 +132,18 @@ type genericScheduler struct {
 	pods                  algorithm.PodLister
 	lastNodeIndexLock     sync.Mutex
 	lastNodeIndex         uint64
+	sampling              NodeSampling
+	evaluation            PredicateEvaluation
+	// newPlugins creates the predicates and priorities again for another cache, if set.
+	newPlugins PluginsFactory
+	// scheduleLock guards cachedNodeInfoMap, which is shared by the workers scheduling groups
+	// and single pods unless they schedule on a cache of their own. It is only held while the
+	// nodes are taken from the cache, not while the predicates, priorities and extenders run,
+	// which may wait for extenders to recover.
+	scheduleLock sync.Mutex
+	// nextStartNodeIndex is where the next search for feasible nodes starts. It is shared with
+	// the schedulers on other caches, see OnCache, and accessed atomically.
+	nextStartNodeIndex *int64
 
 	cachedNodeInfoMap map[string]*schedulercache.NodeInfo
 }
@@ -97,17 +163,33 @@ func (g *genericScheduler) Schedule(pod *v1.Pod, nodeLister algorithm.NodeLister
 		return "", ErrNoNodesAvailable
 	}
 
-	// Used for all fit and priority funcs.
+	// Used for all fit and priority funcs. The cache replaces the nodes which changed rather
+	// than changing them, so the nodes taken stay as they are while other attempts update
+	// cachedNodeInfoMap.
+	// The generation of the equivalence cache is taken before the nodes, the results computed on
+	// nodes invalidated since are not cached.
+	g.scheduleLock.Lock()
+	generation := g.equivalenceCache.Generation()
 	err = g.cache.UpdateNodeNameToInfoMap(g.cachedNodeInfoMap)
+	nodeNameToInfo := make(map[string]*schedulercache.NodeInfo, len(g.cachedNodeInfoMap))
+	for name, info := range g.cachedNodeInfoMap {
+		nodeNameToInfo[name] = info
+	}
+	g.scheduleLock.Unlock()
+	start := int(atomic.LoadInt64(g.nextStartNodeIndex) % int64(len(nodes)))
 	if err != nil {
 		return "", err
 	}
 
 	trace.Step("Computing predicates")
-	filteredNodes, failedPredicateMap, err := findNodesThatFit(pod, g.cachedNodeInfoMap, nodes, g.predicates, g.extenders, g.predicateMetaProducer, g.equivalenceCache)
+	rotated := make([]*v1.Node, 0, len(nodes))
+	rotated = append(append(rotated, nodes[start:]...), nodes[:start]...)
+	filteredNodes, failedPredicateMap, err := findNodesThatFit(pod, nodeNameToInfo, rotated, g.predicates, g.extenders, g.predicateMetaProducer, g.equivalenceCache,
+		generation, g.sampling.numFeasibleNodesToFind(len(nodes)), g.sampling.parallelism(), g.evaluation)
 	if err != nil {
 		return "", err
 	}
+	atomic.StoreInt64(g.nextStartNodeIndex, int64((start+len(filteredNodes)+len(failedPredicateMap))%len(nodes)))
 
 	if len(filteredNodes) == 0 {
 		return "", &FitError{
@@ -117,8 +199,8 @@ func (g *genericScheduler) Schedule(pod *v1.Pod, nodeLister algorithm.NodeLister
 	}
 
 	trace.Step("Prioritizing")
-	metaPrioritiesInterface := g.priorityMetaProducer(pod, g.cachedNodeInfoMap)
-	priorityList, err := PrioritizeNodes(pod, g.cachedNodeInfoMap, metaPrioritiesInterface, g.prioritizers, filteredNodes, g.extenders)
+	metaPrioritiesInterface := g.priorityMetaProducer(pod, nodeNameToInfo)
+	priorityList, err := PrioritizeNodes(pod, nodeNameToInfo, metaPrioritiesInterface, g.prioritizers, filteredNodes, g.extenders, g.sampling.parallelism())
 	if err != nil {
 		return "", err
 	}
@@ -127,6 +209,42 @@ func (g *genericScheduler) Schedule(pod *v1.Pod, nodeLister algorithm.NodeLister
 	return g.selectHost(priorityList)
 }
 
+// OnCache returns a scheduler with the same predicates, priorities and extenders which reads
+// the nodes from cache, e.g. the snapshot a worker places a group on. If the scheduler was
+// created from a PluginsFactory, the predicates and priorities are created again listing the
+// pods from cache, so they see the pods assumed on it. The predicate results are cached in
+// eCache, which is to be invalidated with cache: predicate results on a snapshot don't hold
+// for the scheduler cache.
+func (g *genericScheduler) OnCache(cache schedulercache.Cache, eCache *EquivalenceCache) (algorithm.ScheduleAlgorithm, error) {
+	plugins := &Plugins{
+		Predicates:            g.predicates,
+		PredicateMetaProducer: g.predicateMetaProducer,
+		Prioritizers:          g.prioritizers,
+		PriorityMetaProducer:  g.priorityMetaProducer,
+	}
+	if g.newPlugins != nil {
+		var err error
+		if plugins, err = g.newPlugins(cache); err != nil {
+			return nil, err
+		}
+	}
+	return &genericScheduler{
+		cache:                 cache,
+		equivalenceCache:      eCache,
+		predicates:            plugins.Predicates,
+		predicateMetaProducer: plugins.PredicateMetaProducer,
+		prioritizers:          plugins.Prioritizers,
+		priorityMetaProducer:  plugins.PriorityMetaProducer,
+		extenders:             g.extenders,
+		pods:                  g.pods,
+		newPlugins:            g.newPlugins,
+		sampling:              g.sampling,
+		evaluation:            g.evaluation,
+		nextStartNodeIndex:    g.nextStartNodeIndex,
+		cachedNodeInfoMap:     make(map[string]*schedulercache.NodeInfo),
+	}, nil
+}
+
 // Prioritizers returns a slice containing all the scheduler's priority
 // functions and their config. It is exposed for testing only.
 func (g *genericScheduler) Prioritizers() []algorithm.PriorityConfig {
@@ -160,6 +278,8 @@ func (g *genericScheduler) selectHost(priorityList schedulerapi.HostPriorityList
 
 // Filters the nodes to find the ones that fit based on the given predicate functions
 // Each node is passed through the predicate functions to determine if it is a fit
+// The search stops once numNodesToFind nodes fit, evaluating parallelism nodes at once
+// The nodes which are not evaluated are neither in the filtered nodes nor in the map
 func findNodesThatFit(
 	pod *v1.Pod,
 	nodeNameToInfo map[string]*schedulercache.NodeInfo,
@@ -168,12 +288,19 @@ func findNodesThatFit(
 	extenders []algorithm.SchedulerExtender,
 	metadataProducer algorithm.MetadataProducer,
 	ecache *EquivalenceCache,
+	generation *Generation,
+	numNodesToFind int,
+	parallelism int,
+	evaluation PredicateEvaluation,
 ) ([]*v1.Node, FailedPredicateMap, error) {
 	var filtered []*v1.Node
 	failedPredicateMap := FailedPredicateMap{}
 
 	if len(predicateFuncs) == 0 {
 		filtered = nodes
+		if len(filtered) > numNodesToFind {
+			filtered = filtered[:numNodesToFind]
+		}
 	} else {
 		// Create filtered list with enough space to avoid growing it
 		// and allow assigning.
@@ -184,9 +311,17 @@ func findNodesThatFit(
 
 		// We can use the same metadata producer for all nodes.
 		meta := metadataProducer(pod, nodeNameToInfo)
+		if candidateMeta, ok := meta.(algorithm.CandidateNodesMetadata); ok {
+			candidateMeta.SetCandidateNodes(nodes)
+		}
+		predicateKeys := evaluation.orderedPredicateKeys(predicateFuncs)
 		checkNode := func(i int) {
+			// Workqueue can't be cancelled, the remaining nodes are skipped instead.
+			if atomic.LoadInt32(&filteredLen) >= int32(numNodesToFind) {
+				return
+			}
 			nodeName := nodes[i].Name
-			fits, failedPredicates, err := podFitsOnNode(pod, meta, nodeNameToInfo[nodeName], predicateFuncs, ecache)
+			fits, failedPredicates, err := podFitsOnNode(pod, meta, nodeNameToInfo[nodeName], predicateFuncs, predicateKeys, evaluation.ShortCircuit, ecache, generation)
 			if err != nil {
 				predicateResultLock.Lock()
 				errs[err.Error()]++
@@ -194,14 +329,20 @@ func findNodesThatFit(
 				return
 			}
 			if fits {
-				filtered[atomic.AddInt32(&filteredLen, 1)-1] = nodes[i]
+				length := atomic.AddInt32(&filteredLen, 1)
+				if length > int32(numNodesToFind) {
+					// Found by another worker in the meantime.
+					atomic.AddInt32(&filteredLen, -1)
+				} else {
+					filtered[length-1] = nodes[i]
+				}
 			} else {
 				predicateResultLock.Lock()
 				failedPredicateMap[nodeName] = failedPredicates
 				predicateResultLock.Unlock()
 			}
 		}
-		workqueue.Parallelize(16, len(nodes), checkNode)
+		workqueue.Parallelize(parallelism, len(nodes), checkNode)
 		filtered = filtered[:filteredLen]
 		if len(errs) > 0 {
 			return []*v1.Node{}, FailedPredicateMap{}, errors.CreateAggregateFromMessageCountMap(errs)
@@ -210,6 +351,9 @@ func findNodesThatFit(
 
 	if len(filtered) > 0 && len(extenders) != 0 {
 		for _, extender := range extenders {
+			if !extender.IsInterested(pod) {
+				continue
+			}
 			filteredList, failedMap, err := extender.Filter(pod, filtered, nodeNameToInfo)
 			if err != nil {
 				return []*v1.Node{}, FailedPredicateMap{}, err
@@ -231,8 +375,10 @@ func findNodesThatFit(
 }
 
 // Checks whether node with a given name and NodeInfo satisfies all predicateFuncs.
+// The predicates are evaluated in the order of predicateKeys. If shortCircuit is set,
+// the evaluation stops at the first predicate which fails.
 func podFitsOnNode(pod *v1.Pod, meta interface{}, info *schedulercache.NodeInfo, predicateFuncs map[string]algorithm.FitPredicate,
-	ecache *EquivalenceCache) (bool, []algorithm.PredicateFailureReason, error) {
+	predicateKeys []string, shortCircuit bool, ecache *EquivalenceCache, generation *Generation) (bool, []algorithm.PredicateFailureReason, error) {
 	var (
 		equivalenceHash  uint64
 		failedPredicates []algorithm.PredicateFailureReason
@@ -247,30 +393,41 @@ func podFitsOnNode(pod *v1.Pod, meta interface{}, info *schedulercache.NodeInfo,
 		equivalenceHash = ecache.getHashEquivalencePod(pod)
 		eCacheAvailable = (equivalenceHash != 0)
 	}
-	for predicateKey, predicate := range predicateFuncs {
+	for _, predicateKey := range predicateKeys {
+		predicate := predicateFuncs[predicateKey]
+		cached := eCacheAvailable && !candidatePredicates.Has(predicateKey)
 		// If equivalenceCache is available
-		if eCacheAvailable {
+		if cached {
 			// PredicateWithECache will returns it's cached predicate results
 			fit, reasons, invalid = ecache.PredicateWithECache(pod, info.Node().GetName(), predicateKey, equivalenceHash)
 		}
 
-		if !eCacheAvailable || invalid {
+		if !cached || invalid {
 			// we need to execute predicate functions since equivalence cache does not work
+			start := time.Now()
 			fit, reasons, err = predicate(pod, meta, info)
+			metrics.PredicateEvaluationLatency.WithLabelValues(predicateKey).Observe(metrics.SinceInMicroseconds(start))
 			if err != nil {
 				return false, []algorithm.PredicateFailureReason{}, err
 			}
 
-			if eCacheAvailable {
-				// update equivalence cache with newly computed fit & reasons
-				// TODO(resouer) should we do this in another thread? any race?
-				ecache.UpdateCachedPredicateItem(pod, info.Node().GetName(), predicateKey, fit, reasons, equivalenceHash)
+			if cached {
+				// update equivalence cache with newly computed fit & reasons, unless the node
+				// was invalidated since its info was taken
+				ecache.UpdateCachedPredicateItem(pod, info.Node().GetName(), predicateKey, fit, reasons, equivalenceHash, generation)
 			}
 		}
 
 		if !fit {
 			// eCache is available and valid, and predicates result is unfit, record the fail reasons
 			failedPredicates = append(failedPredicates, reasons...)
+			metrics.PredicateFailures.WithLabelValues(predicateKey).Inc()
+			for _, reason := range reasons {
+				metrics.PredicateFailureReasons.WithLabelValues(reason.GetReason()).Inc()
+			}
+			if shortCircuit {
+				break
+			}
 		}
 	}
 	return len(failedPredicates) == 0, failedPredicates, nil
@@ -289,6 +446,7 @@ func PrioritizeNodes(
 	priorityConfigs []algorithm.PriorityConfig,
 	nodes []*v1.Node,
 	extenders []algorithm.SchedulerExtender,
+	parallelism int,
 ) (schedulerapi.HostPriorityList, error) {
 	// If no priority configs are provided, then the EqualPriority function is applied
 	// This is required to generate the priority list in the required format
@@ -326,7 +484,9 @@ func PrioritizeNodes(
 			go func(index int, config algorithm.PriorityConfig) {
 				defer wg.Done()
 				var err error
+				start := time.Now()
 				results[index], err = config.Function(pod, nodeNameToInfo, nodes)
+				metrics.PriorityEvaluationLatency.WithLabelValues(config.Name, "function").Observe(metrics.SinceInMicroseconds(start))
 				if err != nil {
 					appendError(err)
 				}
@@ -342,14 +502,16 @@ func PrioritizeNodes(
 			if priorityConfigs[i].Function != nil {
 				continue
 			}
+			start := time.Now()
 			results[i][index], err = priorityConfigs[i].Map(pod, meta, nodeInfo)
+			metrics.PriorityEvaluationLatency.WithLabelValues(priorityConfigs[i].Name, "map").Observe(metrics.SinceInMicroseconds(start))
 			if err != nil {
 				appendError(err)
 				return
 			}
 		}
 	}
-	workqueue.Parallelize(16, len(nodes), processNode)
+	workqueue.Parallelize(parallelism, len(nodes), processNode)
 	for i, priorityConfig := range priorityConfigs {
 		if priorityConfig.Reduce == nil {
 			continue
@@ -357,7 +519,10 @@ func PrioritizeNodes(
 		wg.Add(1)
 		go func(index int, config algorithm.PriorityConfig) {
 			defer wg.Done()
-			if err := config.Reduce(pod, meta, nodeNameToInfo, results[index]); err != nil {
+			start := time.Now()
+			err := config.Reduce(pod, meta, nodeNameToInfo, results[index])
+			metrics.PriorityEvaluationLatency.WithLabelValues(config.Name, "reduce").Observe(metrics.SinceInMicroseconds(start))
+			if err != nil {
 				appendError(err)
 			}
 		}(i, priorityConfig)
@@ -381,6 +546,9 @@ func PrioritizeNodes(
 	if len(extenders) != 0 && nodes != nil {
 		combinedScores := make(map[string]int, len(nodeNameToInfo))
 		for _, extender := range extenders {
+			if !extender.IsInterested(pod) {
+				continue
+			}
 			wg.Add(1)
 			go func(ext algorithm.SchedulerExtender) {
 				defer wg.Done()
@@ -424,6 +592,35 @@ func EqualPriorityMap(_ *v1.Pod, _ interface{}, nodeInfo *schedulercache.NodeInf
 	}, nil
 }
 
+// Plugins are the predicates and priorities of a scheduler with their metadata producers.
+type Plugins struct {
+	Predicates            map[string]algorithm.FitPredicate
+	PredicateMetaProducer algorithm.MetadataProducer
+	Prioritizers          []algorithm.PriorityConfig
+	PriorityMetaProducer  algorithm.MetadataProducer
+}
+
+// PluginsFactory creates the plugins of a scheduler listing the pods from pods.
+type PluginsFactory func(pods algorithm.PodLister) (*Plugins, error)
+
+// NewGenericSchedulerFromPlugins creates a scheduler with the plugins newPlugins creates for
+// cache. A scheduler reading another cache, see OnCache, creates its plugins for that cache.
+func NewGenericSchedulerFromPlugins(
+	cache schedulercache.Cache,
+	eCache *EquivalenceCache,
+	newPlugins PluginsFactory,
+	extenders []algorithm.SchedulerExtender,
+	sampling NodeSampling,
+	evaluation PredicateEvaluation) (algorithm.ScheduleAlgorithm, error) {
+	plugins, err := newPlugins(cache)
+	if err != nil {
+		return nil, err
+	}
+	g := NewGenericScheduler(cache, eCache, plugins.Predicates, plugins.PredicateMetaProducer, plugins.Prioritizers, plugins.PriorityMetaProducer, extenders, sampling, evaluation).(*genericScheduler)
+	g.newPlugins = newPlugins
+	return g, nil
+}
+
 func NewGenericScheduler(
 	cache schedulercache.Cache,
 	eCache *EquivalenceCache,
@@ -431,7 +628,9 @@ func NewGenericScheduler(
 	predicateMetaProducer algorithm.MetadataProducer,
 	prioritizers []algorithm.PriorityConfig,
 	priorityMetaProducer algorithm.MetadataProducer,
-	extenders []algorithm.SchedulerExtender) algorithm.ScheduleAlgorithm {
+	extenders []algorithm.SchedulerExtender,
+	sampling NodeSampling,
+	evaluation PredicateEvaluation) algorithm.ScheduleAlgorithm {
 	return &genericScheduler{
 		cache:                 cache,
 		equivalenceCache:      eCache,
@@ -440,6 +639,9 @@ func NewGenericScheduler(
 		prioritizers:          prioritizers,
 		priorityMetaProducer:  priorityMetaProducer,
 		extenders:             extenders,
+		sampling:              sampling,
+		evaluation:            evaluation,
+		nextStartNodeIndex:    new(int64),
 		cachedNodeInfoMap:     make(map[string]*schedulercache.NodeInfo),
 	}
 }
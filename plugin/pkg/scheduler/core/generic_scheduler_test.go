@@ -308,7 +308,7 @@ func TestGenericScheduler(t *testing.T) {
 
 		scheduler := NewGenericScheduler(
 			cache, nil, test.predicates, algorithm.EmptyMetadataProducer, test.prioritizers, algorithm.EmptyMetadataProducer,
-			[]algorithm.SchedulerExtender{})
+			[]algorithm.SchedulerExtender{}, NodeSampling{}, PredicateEvaluation{})
 		machine, err := scheduler.Schedule(test.pod, schedulertesting.FakeNodeLister(makeNodeList(test.nodes)))
 
 		if !reflect.DeepEqual(err, test.wErr) {
@@ -328,7 +328,7 @@ func TestFindFitAllError(t *testing.T) {
 		"2": schedulercache.NewNodeInfo(),
 		"1": schedulercache.NewNodeInfo(),
 	}
-	_, predicateMap, err := findNodesThatFit(&v1.Pod{}, nodeNameToInfo, makeNodeList(nodes), predicates, nil, algorithm.EmptyMetadataProducer, nil)
+	_, predicateMap, err := findNodesThatFit(&v1.Pod{}, nodeNameToInfo, makeNodeList(nodes), predicates, nil, algorithm.EmptyMetadataProducer, nil, nil, len(nodes), DefaultParallelism, PredicateEvaluation{})
 
 	if err != nil {
 		t.Errorf("unexpected error: %v", err)
@@ -362,7 +362,7 @@ func TestFindFitSomeError(t *testing.T) {
 		nodeNameToInfo[name].SetNode(&v1.Node{ObjectMeta: metav1.ObjectMeta{Name: name}})
 	}
 
-	_, predicateMap, err := findNodesThatFit(pod, nodeNameToInfo, makeNodeList(nodes), predicates, nil, algorithm.EmptyMetadataProducer, nil)
+	_, predicateMap, err := findNodesThatFit(pod, nodeNameToInfo, makeNodeList(nodes), predicates, nil, algorithm.EmptyMetadataProducer, nil, nil, len(nodes), DefaultParallelism, PredicateEvaluation{})
 	if err != nil {
 		t.Errorf("unexpected error: %v", err)
 	}
@@ -527,7 +527,7 @@ func TestZeroRequest(t *testing.T) {
 		nodeNameToInfo := schedulercache.CreateNodeNameToInfoMap(test.pods, test.nodes)
 		list, err := PrioritizeNodes(
 			test.pod, nodeNameToInfo, algorithm.EmptyMetadataProducer, priorityConfigs,
-			schedulertesting.FakeNodeLister(test.nodes), []algorithm.SchedulerExtender{})
+			schedulertesting.FakeNodeLister(test.nodes), []algorithm.SchedulerExtender{}, DefaultParallelism)
 		if err != nil {
 			t.Errorf("unexpected error: %v", err)
 		}
@@ -544,3 +544,99 @@ func TestZeroRequest(t *testing.T) {
 		}
 	}
 }
+
+func TestNumFeasibleNodesToFind(t *testing.T) {
+	tests := []struct {
+		name        string
+		sampling    NodeSampling
+		numAllNodes int
+		expected    int
+	}{
+		{name: "all nodes by default", numAllNodes: 2000, expected: 2000},
+		{name: "absolute number", sampling: NodeSampling{NumFeasibleNodesToFind: 300}, numAllNodes: 2000, expected: 300},
+		{name: "absolute number capped to all nodes", sampling: NodeSampling{NumFeasibleNodesToFind: 300}, numAllNodes: 200, expected: 200},
+		{name: "percentage", sampling: NodeSampling{PercentageOfNodesToFind: 10}, numAllNodes: 2000, expected: 200},
+		{name: "percentage of a small cluster", sampling: NodeSampling{PercentageOfNodesToFind: 10}, numAllNodes: 500, expected: 100},
+		{name: "larger of both", sampling: NodeSampling{NumFeasibleNodesToFind: 300, PercentageOfNodesToFind: 10}, numAllNodes: 2000, expected: 300},
+	}
+	for _, test := range tests {
+		if got := test.sampling.numFeasibleNodesToFind(test.numAllNodes); got != test.expected {
+			t.Errorf("%s: expected %d nodes to find, got %d", test.name, test.expected, got)
+		}
+	}
+}
+
+func TestFindFitSampling(t *testing.T) {
+	nodes := []string{"1", "2", "3", "4", "5", "6"}
+	predicates := map[string]algorithm.FitPredicate{"true": truePredicate}
+	nodeNameToInfo := map[string]*schedulercache.NodeInfo{}
+	for _, name := range nodes {
+		nodeNameToInfo[name] = schedulercache.NewNodeInfo()
+		nodeNameToInfo[name].SetNode(&v1.Node{ObjectMeta: metav1.ObjectMeta{Name: name}})
+	}
+
+	filtered, predicateMap, err := findNodesThatFit(&v1.Pod{}, nodeNameToInfo, makeNodeList(nodes), predicates, nil, algorithm.EmptyMetadataProducer, nil, nil, 2, 1, PredicateEvaluation{})
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if len(filtered) != 2 || len(predicateMap) != 0 {
+		t.Errorf("expected 2 nodes to be found, got %v and failures %v", filtered, predicateMap)
+	}
+}
+
+func TestScheduleStartsWhereLastSearchStopped(t *testing.T) {
+	nodes := []string{"1", "2", "3", "4"}
+	cache := schedulercache.New(time.Duration(0), wait.NeverStop)
+	for _, name := range nodes {
+		cache.AddNode(&v1.Node{ObjectMeta: metav1.ObjectMeta{Name: name}})
+	}
+	scheduler := NewGenericScheduler(
+		cache, nil, map[string]algorithm.FitPredicate{"true": truePredicate}, algorithm.EmptyMetadataProducer,
+		[]algorithm.PriorityConfig{{Map: EqualPriorityMap, Weight: 1}}, algorithm.EmptyMetadataProducer,
+		[]algorithm.SchedulerExtender{}, NodeSampling{NumFeasibleNodesToFind: 1, Parallelism: 1}, PredicateEvaluation{})
+
+	var hosts []string
+	for i := 0; i < len(nodes); i++ {
+		host, err := scheduler.Schedule(&v1.Pod{}, schedulertesting.FakeNodeLister(makeNodeList(nodes)))
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+		hosts = append(hosts, host)
+	}
+	if !reflect.DeepEqual(hosts, nodes) {
+		t.Errorf("expected the nodes to be found in turn %v, got %v", nodes, hosts)
+	}
+}
+
+func TestScheduleOnCacheStartsWhereLastSearchStopped(t *testing.T) {
+	nodes := []string{"1", "2", "3", "4"}
+	cache := schedulercache.New(time.Duration(0), wait.NeverStop)
+	for _, name := range nodes {
+		cache.AddNode(&v1.Node{ObjectMeta: metav1.ObjectMeta{Name: name}})
+	}
+	scheduler := NewGenericScheduler(
+		cache, nil, map[string]algorithm.FitPredicate{"true": truePredicate}, algorithm.EmptyMetadataProducer,
+		[]algorithm.PriorityConfig{{Map: EqualPriorityMap, Weight: 1}}, algorithm.EmptyMetadataProducer,
+		[]algorithm.SchedulerExtender{}, NodeSampling{NumFeasibleNodesToFind: 1, Parallelism: 1}, PredicateEvaluation{})
+	snapshot := schedulercache.NewSnapshot(cache)
+	if err := snapshot.Update(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	onCache, err := scheduler.(*genericScheduler).OnCache(snapshot, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// The searches on either cache continue where the last one on the other stopped.
+	var hosts []string
+	for _, algo := range []algorithm.ScheduleAlgorithm{scheduler, onCache, onCache, scheduler} {
+		host, err := algo.Schedule(&v1.Pod{}, schedulertesting.FakeNodeLister(makeNodeList(nodes)))
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+		hosts = append(hosts, host)
+	}
+	if !reflect.DeepEqual(hosts, nodes) {
+		t.Errorf("expected the nodes to be found in turn %v, got %v", nodes, hosts)
+	}
+}
@@ -0,0 +1,125 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"fmt"
+	"sort"
+
+	"k8s.io/kubernetes/pkg/api/v1"
+	schedulerapi "k8s.io/kubernetes/plugin/pkg/scheduler/api"
+	"k8s.io/kubernetes/plugin/pkg/scheduler/metrics"
+	"k8s.io/kubernetes/plugin/pkg/scheduler/tools"
+)
+
+// GroupPrioritize based on extender implemented group priority functions. The nodes are
+// scored for the group as a whole, no scores are returned if the extender doesn't
+// implement the group prioritize verb.
+func (h *HTTPExtender) GroupPrioritize(group *schedulerapi.SchedulingGroup, nodes []*v1.Node) (*schedulerapi.HostPriorityList, int, error) {
+	var result schedulerapi.HostPriorityList
+	if h.groupPrioritizeVerb == "" {
+		return &result, 0, nil
+	}
+
+	args := groupArgs(group, nil)
+	if h.nodeCacheCapable {
+		nodeNameSlice := make([]string, 0, len(nodes))
+		for _, node := range nodes {
+			nodeNameSlice = append(nodeNameSlice, node.Name)
+		}
+		args.NodeNames = &nodeNameSlice
+	} else {
+		args.Nodes = &v1.NodeList{}
+		for _, node := range nodes {
+			args.Nodes.Items = append(args.Nodes.Items, *node)
+		}
+	}
+
+	if err := h.send(h.groupPrioritizeVerb, args, &result); err != nil {
+		return nil, 0, err
+	}
+	return &result, h.weight, nil
+}
+
+// GroupFilter sends the placement proposed for the members of the group to the extender,
+// which may reject it or override the node of some members.
+func (h *HTTPExtender) GroupFilter(group *schedulerapi.SchedulingGroup, assignments []schedulerapi.ExtenderPodAssignment) ([]schedulerapi.ExtenderPodAssignment, error) {
+	var result schedulerapi.ExtenderGroupFilterResult
+	if h.groupFilterVerb == "" {
+		return nil, nil
+	}
+	err := h.send(h.groupFilterVerb, groupArgs(group, assignments), &result)
+	if err == nil && result.Error != "" {
+		metrics.ExtenderErrors.WithLabelValues(h.extenderURL, h.groupFilterVerb).Inc()
+		err = fmt.Errorf(result.Error)
+	}
+	if err != nil {
+		if h.ignore(h.groupFilterVerb, err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return result.Assignments, nil
+}
+
+// GroupBind delegates the action of binding all members of the group to the extender.
+func (h *HTTPExtender) GroupBind(group *schedulerapi.SchedulingGroup, assignments []schedulerapi.ExtenderPodAssignment) error {
+	var result schedulerapi.ExtenderBindingResult
+	if !h.IsGroupBinder() {
+		// This shouldn't happen as this extender wouldn't have become a group binder.
+		return fmt.Errorf("Unexpected empty groupBindVerb in extender")
+	}
+	if err := h.send(h.groupBindVerb, groupArgs(group, assignments), &result); err != nil {
+		return err
+	}
+	if result.Error != "" {
+		metrics.ExtenderErrors.WithLabelValues(h.extenderURL, h.groupBindVerb).Inc()
+		return fmt.Errorf(result.Error)
+	}
+	return nil
+}
+
+// IsGroupBinder returns whether this extender is configured for the GroupBind method.
+func (h *HTTPExtender) IsGroupBinder() bool {
+	return h.groupBindVerb != ""
+}
+
+// groupArgs returns the roles of the group with their pending members, ordered by name, and
+// the given assignments as arguments to an extender.
+func groupArgs(group *schedulerapi.SchedulingGroup, assignments []schedulerapi.ExtenderPodAssignment) *schedulerapi.ExtenderGroupArgs {
+	args := &schedulerapi.ExtenderGroupArgs{
+		Group:       group.Group,
+		Priority:    tools.GetGroupPriority(group),
+		Roles:       make([]schedulerapi.ExtenderGroupRole, 0, len(group.Resources)),
+		Assignments: assignments,
+	}
+	for _, rb := range group.Resources {
+		role := schedulerapi.ExtenderGroupRole{
+			Role: rb.Role,
+			Min:  rb.Min,
+			Max:  rb.Max,
+			Pods: make([]v1.Pod, 0, len(rb.PendingPods)),
+		}
+		for _, pod := range rb.PendingPods {
+			role.Pods = append(role.Pods, *pod)
+		}
+		sort.Slice(role.Pods, func(i, j int) bool { return role.Pods[i].Name < role.Pods[j].Name })
+		args.Roles = append(args.Roles, role)
+	}
+	sort.Slice(args.Roles, func(i, j int) bool { return args.Roles[i].Role < args.Roles[j].Role })
+	return args
+}
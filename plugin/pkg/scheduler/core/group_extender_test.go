@@ -0,0 +1,126 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kubernetes/pkg/api/v1"
+	schedulerapi "k8s.io/kubernetes/plugin/pkg/scheduler/api"
+)
+
+func newGroupExtenderServer(t *testing.T, handle func(verb string, args *schedulerapi.ExtenderGroupArgs) interface{}) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var args schedulerapi.ExtenderGroupArgs
+		if err := json.NewDecoder(r.Body).Decode(&args); err != nil {
+			t.Errorf("Failed to decode group args: %v", err)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		json.NewEncoder(w).Encode(handle(r.URL.Path, &args))
+	}))
+}
+
+func TestHTTPExtenderGroupVerbs(t *testing.T) {
+	group := &schedulerapi.SchedulingGroup{
+		Group: "bar/group",
+		Resources: []*schedulerapi.ResourceObject{
+			{
+				Role: "worker",
+				Min:  2,
+				Max:  2,
+				PendingPods: map[string]*v1.Pod{
+					"worker-1": {ObjectMeta: metav1.ObjectMeta{Name: "worker-1", Namespace: "bar"}},
+					"worker-0": {ObjectMeta: metav1.ObjectMeta{Name: "worker-0", Namespace: "bar"}},
+				},
+			},
+		},
+	}
+	assignments := []schedulerapi.ExtenderPodAssignment{
+		{PodName: "worker-0", PodNamespace: "bar", Role: "worker", Node: "machine1"},
+		{PodName: "worker-1", PodNamespace: "bar", Role: "worker", Node: "machine1"},
+	}
+	var bound []schedulerapi.ExtenderPodAssignment
+	server := newGroupExtenderServer(t, func(verb string, args *schedulerapi.ExtenderGroupArgs) interface{} {
+		if args.Group != group.Group || len(args.Roles) != 1 || len(args.Roles[0].Pods) != 2 || args.Roles[0].Pods[0].Name != "worker-0" {
+			t.Errorf("%s: unexpected group args %+v", verb, args)
+		}
+		switch verb {
+		case "/groupprioritize":
+			if args.NodeNames == nil || !reflect.DeepEqual(*args.NodeNames, []string{"machine1", "machine2"}) {
+				t.Errorf("%s: expected the names of the nodes, got %+v", verb, args)
+			}
+			return schedulerapi.HostPriorityList{{Host: "machine1", Score: 1}, {Host: "machine2", Score: 5}}
+		case "/groupfilter":
+			if args.Assignments[1].Node == "machine1" {
+				return schedulerapi.ExtenderGroupFilterResult{Assignments: []schedulerapi.ExtenderPodAssignment{
+					{PodName: "worker-1", PodNamespace: "bar", Role: "worker", Node: "machine2"},
+				}}
+			}
+			return schedulerapi.ExtenderGroupFilterResult{Error: "no fabric left"}
+		case "/groupbind":
+			bound = args.Assignments
+			return schedulerapi.ExtenderBindingResult{}
+		}
+		t.Errorf("Unexpected verb %s", verb)
+		return nil
+	})
+	defer server.Close()
+
+	extender, err := NewHTTPExtender(&schedulerapi.ExtenderConfig{
+		URLPrefix:           server.URL,
+		GroupFilterVerb:     "groupfilter",
+		GroupPrioritizeVerb: "groupprioritize",
+		GroupBindVerb:       "groupbind",
+		Weight:              2,
+		NodeCacheCapable:    true,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create extender: %v", err)
+	}
+	groupExtender := extender.(*HTTPExtender)
+
+	nodes := []*v1.Node{makeNode("machine1", 1000, 1000), makeNode("machine2", 1000, 1000)}
+	scores, weight, err := groupExtender.GroupPrioritize(group, nodes)
+	if err != nil || weight != 2 || len(*scores) != 2 || (*scores)[1].Score != 5 {
+		t.Errorf("Unexpected group prioritize result %v, %d, %v", scores, weight, err)
+	}
+
+	overrides, err := groupExtender.GroupFilter(group, assignments)
+	if err != nil || len(overrides) != 1 || overrides[0].Node != "machine2" {
+		t.Errorf("Unexpected group filter result %v, %v", overrides, err)
+	}
+	assignments[1].Node = "machine2"
+	if _, err := groupExtender.GroupFilter(group, assignments); err == nil || err.Error() != "no fabric left" {
+		t.Errorf("Expected the placement to be rejected, got %v", err)
+	}
+
+	if !groupExtender.IsGroupBinder() {
+		t.Errorf("Expected extender to bind groups")
+	}
+	if err := groupExtender.GroupBind(group, assignments); err != nil {
+		t.Errorf("Unexpected group bind error %v", err)
+	}
+	if !reflect.DeepEqual(bound, assignments) {
+		t.Errorf("Expected group bound as %v, got %v", assignments, bound)
+	}
+}
@@ -0,0 +1,379 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/kubernetes/pkg/api/v1"
+	"k8s.io/kubernetes/plugin/pkg/scheduler/algorithm"
+	schedulerapi "k8s.io/kubernetes/plugin/pkg/scheduler/api"
+	extenderv1alpha1 "k8s.io/kubernetes/plugin/pkg/scheduler/api/extender/v1alpha1"
+	"k8s.io/kubernetes/plugin/pkg/scheduler/metrics"
+	"k8s.io/kubernetes/plugin/pkg/scheduler/schedulercache"
+)
+
+// NodeSyncer is implemented by the extenders keeping their cache of nodes in sync with the
+// nodes of the cluster.
+type NodeSyncer interface {
+	AddNode(node *v1.Node)
+	UpdateNode(oldNode, newNode *v1.Node)
+	RemoveNode(node *v1.Node)
+}
+
+// GRPCExtender implements the algorithm.SchedulerExtender interface over gRPC. All calls
+// share a persistent connection to the extender. If the extender caches nodes, the changes
+// of the nodes are streamed to it, and only the names of the nodes are sent in calls.
+type GRPCExtender struct {
+	*extenderCalls
+	extenderInterest
+	filterVerb       string
+	prioritizeVerb   string
+	bindVerb         string
+	weight           int
+	timeout          time.Duration
+	nodeCacheCapable bool
+	client           extenderv1alpha1.ExtenderClient
+	nodeLister       algorithm.NodeLister
+
+	// Changes of the nodes not sent to the extender yet. They are only queued while the
+	// stream is open, all nodes are sent again when it is reopened.
+	lock      sync.Mutex
+	streaming bool
+	deltas    []*extenderv1alpha1.NodeDelta
+	newDeltas chan struct{}
+}
+
+// NewGRPCExtender connects to the extender at the address of the URL prefix of the config.
+// If the extender caches nodes, the nodes listed are streamed to it until stop is closed.
+func NewGRPCExtender(config *schedulerapi.ExtenderConfig, nodeLister algorithm.NodeLister, stop <-chan struct{}) (algorithm.SchedulerExtender, error) {
+	if config.HTTPTimeout.Nanoseconds() == 0 {
+		config.HTTPTimeout = time.Duration(DefaultExtenderTimeout)
+	}
+
+	dialOption := grpc.WithInsecure()
+	if config.EnableHttps {
+		tlsConfig, err := makeTLSConfig(config)
+		if err != nil {
+			return nil, err
+		}
+		dialOption = grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig))
+	}
+	conn, err := grpc.Dial(config.URLPrefix, dialOption)
+	if err != nil {
+		return nil, err
+	}
+	interest, err := newExtenderInterest(config)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	g := &GRPCExtender{
+		extenderCalls:    newExtenderCalls(config),
+		extenderInterest: interest,
+		filterVerb:       config.FilterVerb,
+		prioritizeVerb:   config.PrioritizeVerb,
+		bindVerb:         config.BindVerb,
+		weight:           config.Weight,
+		timeout:          config.HTTPTimeout,
+		nodeCacheCapable: config.NodeCacheCapable,
+		client:           extenderv1alpha1.NewExtenderClient(conn),
+		nodeLister:       nodeLister,
+		newDeltas:        make(chan struct{}, 1),
+	}
+	registerExtender(g.extenderCalls)
+	if g.nodeCacheCapable {
+		go wait.Until(func() { g.syncNodes(stop) }, time.Second, stop)
+	}
+	go func() {
+		<-stop
+		conn.Close()
+	}()
+	return g, nil
+}
+
+// Filter based on extender implemented predicate functions. The filtered list is
+// expected to be a subset of the supplied list. failedNodesMap optionally contains
+// the list of failed nodes and failure reasons.
+func (g *GRPCExtender) Filter(pod *v1.Pod, nodes []*v1.Node, nodeNameToInfo map[string]*schedulercache.NodeInfo) ([]*v1.Node, schedulerapi.FailedNodesMap, error) {
+	if g.filterVerb == "" {
+		return nodes, schedulerapi.FailedNodesMap{}, nil
+	}
+	args, err := g.args(pod, nodes)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var result *extenderv1alpha1.ExtenderFilterResult
+	err = g.call(g.filterVerb, true, func() error {
+		ctx, cancel := context.WithTimeout(context.Background(), g.timeout)
+		defer cancel()
+		var err error
+		result, err = g.client.Filter(ctx, args)
+		return grpcError(err)
+	})
+	if err == nil && result.Error != "" {
+		metrics.ExtenderErrors.WithLabelValues(g.extenderURL, g.filterVerb).Inc()
+		err = fmt.Errorf(result.Error)
+	}
+	if err != nil {
+		if g.ignore(g.filterVerb, err) {
+			return nodes, schedulerapi.FailedNodesMap{}, nil
+		}
+		return nil, nil, err
+	}
+
+	candidates := make(map[string]*v1.Node, len(nodes))
+	for _, node := range nodes {
+		candidates[node.Name] = node
+	}
+	nodeResult := make([]*v1.Node, 0, len(result.NodeNames))
+	for _, name := range result.NodeNames {
+		if node, ok := candidates[name]; ok {
+			nodeResult = append(nodeResult, node)
+		}
+	}
+	return nodeResult, schedulerapi.FailedNodesMap(result.FailedNodes), nil
+}
+
+// Prioritize based on extender implemented priority functions. Weight*priority is added
+// up for each such priority function. The returned score is added to the score computed
+// by Kubernetes scheduler. The total score is used to do the host selection.
+func (g *GRPCExtender) Prioritize(pod *v1.Pod, nodes []*v1.Node) (*schedulerapi.HostPriorityList, int, error) {
+	if g.prioritizeVerb == "" {
+		result := schedulerapi.HostPriorityList{}
+		for _, node := range nodes {
+			result = append(result, schedulerapi.HostPriority{Host: node.Name, Score: 0})
+		}
+		return &result, 0, nil
+	}
+	args, err := g.args(pod, nodes)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var priorities *extenderv1alpha1.HostPriorityList
+	err = g.call(g.prioritizeVerb, true, func() error {
+		ctx, cancel := context.WithTimeout(context.Background(), g.timeout)
+		defer cancel()
+		var err error
+		priorities, err = g.client.Prioritize(ctx, args)
+		return grpcError(err)
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	result := make(schedulerapi.HostPriorityList, 0, len(priorities.Priorities))
+	for _, priority := range priorities.Priorities {
+		result = append(result, schedulerapi.HostPriority{Host: priority.Host, Score: int(priority.Score)})
+	}
+	return &result, g.weight, nil
+}
+
+// Bind delegates the action of binding a pod to a node to the extender.
+func (g *GRPCExtender) Bind(binding *v1.Binding) error {
+	if !g.IsBinder() {
+		// This shouldn't happen as this extender wouldn't have become a Binder.
+		return fmt.Errorf("Unexpected empty bindVerb in extender")
+	}
+	args := &extenderv1alpha1.ExtenderBindingArgs{
+		PodName:      binding.Name,
+		PodNamespace: binding.Namespace,
+		PodUid:       string(binding.UID),
+		Node:         binding.Target.Name,
+	}
+	var result *extenderv1alpha1.ExtenderBindingResult
+	err := g.call(g.bindVerb, false, func() error {
+		ctx, cancel := context.WithTimeout(context.Background(), g.timeout)
+		defer cancel()
+		var err error
+		result, err = g.client.Bind(ctx, args)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	if result.Error != "" {
+		metrics.ExtenderErrors.WithLabelValues(g.extenderURL, g.bindVerb).Inc()
+		return fmt.Errorf(result.Error)
+	}
+	return nil
+}
+
+// IsBinder returns whether this extender is configured for the Bind method.
+func (g *GRPCExtender) IsBinder() bool {
+	return g.bindVerb != ""
+}
+
+// grpcError marks the errors of calls which didn't get through to the extender, or failed
+// in it, as transient.
+func grpcError(err error) error {
+	switch grpc.Code(err) {
+	case codes.OK:
+		return nil
+	case codes.Unavailable, codes.DeadlineExceeded, codes.Internal:
+		return transientError{err}
+	}
+	return err
+}
+
+// args returns the pod and the candidate nodes, or only their names if the extender
+// caches nodes.
+func (g *GRPCExtender) args(pod *v1.Pod, nodes []*v1.Node) (*extenderv1alpha1.ExtenderArgs, error) {
+	data, err := pod.Marshal()
+	if err != nil {
+		return nil, err
+	}
+	args := &extenderv1alpha1.ExtenderArgs{Pod: data}
+	for _, node := range nodes {
+		if g.nodeCacheCapable {
+			args.NodeNames = append(args.NodeNames, node.Name)
+			continue
+		}
+		data, err := node.Marshal()
+		if err != nil {
+			return nil, err
+		}
+		args.Nodes = append(args.Nodes, data)
+	}
+	return args, nil
+}
+
+// AddNode streams the node added to the extender if it caches nodes.
+func (g *GRPCExtender) AddNode(node *v1.Node) {
+	g.queueNode(extenderv1alpha1.NodeDelta_ADDED, node)
+}
+
+// UpdateNode streams the node modified to the extender if it caches nodes. The updates of
+// the heartbeats of the node are not streamed.
+func (g *GRPCExtender) UpdateNode(oldNode, newNode *v1.Node) {
+	if nodeChanged(oldNode, newNode) {
+		g.queueNode(extenderv1alpha1.NodeDelta_MODIFIED, newNode)
+	}
+}
+
+// RemoveNode streams the name of the node deleted to the extender if it caches nodes.
+func (g *GRPCExtender) RemoveNode(node *v1.Node) {
+	g.queue(&extenderv1alpha1.NodeDelta{Type: extenderv1alpha1.NodeDelta_DELETED, NodeName: node.Name})
+}
+
+func (g *GRPCExtender) queueNode(deltaType extenderv1alpha1.NodeDelta_Type, node *v1.Node) {
+	if !g.nodeCacheCapable {
+		return
+	}
+	data, err := node.Marshal()
+	if err != nil {
+		glog.Errorf("Failed to marshal node %s for extender at %v: %v", node.Name, g.extenderURL, err)
+		return
+	}
+	g.queue(&extenderv1alpha1.NodeDelta{Type: deltaType, Node: data})
+}
+
+func (g *GRPCExtender) queue(delta *extenderv1alpha1.NodeDelta) {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	if !g.streaming {
+		return
+	}
+	g.deltas = append(g.deltas, delta)
+	select {
+	case g.newDeltas <- struct{}{}:
+	default:
+	}
+}
+
+// syncNodes opens the stream of the changes of the nodes, sends all nodes listed, and then
+// their changes until the stream breaks or stop is closed.
+func (g *GRPCExtender) syncNodes(stop <-chan struct{}) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stream, err := g.client.SyncNodes(ctx)
+	if err != nil {
+		glog.Errorf("Failed to open stream of nodes to extender at %v: %v", g.extenderURL, err)
+		return
+	}
+	// Changes queued from now on may already be part of the nodes listed, sending them
+	// again is harmless as nodes added or modified replace the ones cached.
+	g.lock.Lock()
+	g.streaming, g.deltas = true, nil
+	g.lock.Unlock()
+	defer func() {
+		g.lock.Lock()
+		g.streaming, g.deltas = false, nil
+		g.lock.Unlock()
+	}()
+
+	nodes, err := g.nodeLister.List()
+	if err != nil {
+		glog.Errorf("Failed to list nodes to stream to extender at %v: %v", g.extenderURL, err)
+		return
+	}
+	deltas := []*extenderv1alpha1.NodeDelta{{Type: extenderv1alpha1.NodeDelta_RESET}}
+	for _, node := range nodes {
+		data, err := node.Marshal()
+		if err != nil {
+			glog.Errorf("Failed to marshal node %s for extender at %v: %v", node.Name, g.extenderURL, err)
+			continue
+		}
+		deltas = append(deltas, &extenderv1alpha1.NodeDelta{Type: extenderv1alpha1.NodeDelta_ADDED, Node: data})
+	}
+	for {
+		for _, delta := range deltas {
+			if err := stream.Send(delta); err != nil {
+				glog.Errorf("Stream of nodes to extender at %v broke: %v", g.extenderURL, err)
+				return
+			}
+		}
+		select {
+		case <-stop:
+			stream.CloseAndRecv()
+			return
+		case <-g.newDeltas:
+		}
+		g.lock.Lock()
+		deltas, g.deltas = g.deltas, nil
+		g.lock.Unlock()
+	}
+}
+
+// nodeChanged returns true if the node changed in more than the heartbeats of its conditions.
+func nodeChanged(oldNode, newNode *v1.Node) bool {
+	if !reflect.DeepEqual(oldNode.Labels, newNode.Labels) ||
+		!reflect.DeepEqual(oldNode.Annotations, newNode.Annotations) ||
+		!reflect.DeepEqual(oldNode.Spec, newNode.Spec) ||
+		!reflect.DeepEqual(oldNode.Status.Capacity, newNode.Status.Capacity) ||
+		!reflect.DeepEqual(oldNode.Status.Allocatable, newNode.Status.Allocatable) ||
+		len(oldNode.Status.Conditions) != len(newNode.Status.Conditions) {
+		return true
+	}
+	for i := range oldNode.Status.Conditions {
+		oldCondition, newCondition := oldNode.Status.Conditions[i], newNode.Status.Conditions[i]
+		if oldCondition.Type != newCondition.Type || oldCondition.Status != newCondition.Status || oldCondition.Reason != newCondition.Reason {
+			return true
+		}
+	}
+	return false
+}
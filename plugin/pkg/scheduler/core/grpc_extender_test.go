@@ -0,0 +1,215 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"net"
+	"reflect"
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/kubernetes/pkg/api/v1"
+	schedulerapi "k8s.io/kubernetes/plugin/pkg/scheduler/api"
+	extenderv1alpha1 "k8s.io/kubernetes/plugin/pkg/scheduler/api/extender/v1alpha1"
+	"k8s.io/kubernetes/plugin/pkg/scheduler/schedulercache"
+	schedulertesting "k8s.io/kubernetes/plugin/pkg/scheduler/testing"
+)
+
+// fakeGRPCExtenderServer rejects machine2, prefers machine1 and records the bindings and the
+// changes of the nodes it is sent.
+type fakeGRPCExtenderServer struct {
+	bindings []*extenderv1alpha1.ExtenderBindingArgs
+	deltas   chan *extenderv1alpha1.NodeDelta
+}
+
+func (s *fakeGRPCExtenderServer) Filter(ctx context.Context, args *extenderv1alpha1.ExtenderArgs) (*extenderv1alpha1.ExtenderFilterResult, error) {
+	result := &extenderv1alpha1.ExtenderFilterResult{FailedNodes: map[string]string{}}
+	for _, name := range nodeNames(args) {
+		if name == "machine2" {
+			result.FailedNodes[name] = "no license left"
+			continue
+		}
+		result.NodeNames = append(result.NodeNames, name)
+	}
+	return result, nil
+}
+
+func (s *fakeGRPCExtenderServer) Prioritize(ctx context.Context, args *extenderv1alpha1.ExtenderArgs) (*extenderv1alpha1.HostPriorityList, error) {
+	result := &extenderv1alpha1.HostPriorityList{}
+	for _, name := range nodeNames(args) {
+		priority := &extenderv1alpha1.HostPriority{Host: name}
+		if name == "machine1" {
+			priority.Score = 10
+		}
+		result.Priorities = append(result.Priorities, priority)
+	}
+	return result, nil
+}
+
+func (s *fakeGRPCExtenderServer) Bind(ctx context.Context, args *extenderv1alpha1.ExtenderBindingArgs) (*extenderv1alpha1.ExtenderBindingResult, error) {
+	s.bindings = append(s.bindings, args)
+	return &extenderv1alpha1.ExtenderBindingResult{}, nil
+}
+
+func (s *fakeGRPCExtenderServer) SyncNodes(stream extenderv1alpha1.Extender_SyncNodesServer) error {
+	for {
+		delta, err := stream.Recv()
+		if err != nil {
+			return stream.SendAndClose(&extenderv1alpha1.SyncNodesResponse{})
+		}
+		s.deltas <- delta
+	}
+}
+
+// nodeNames returns the names of the candidate nodes, unmarshalling them if they were sent in full.
+func nodeNames(args *extenderv1alpha1.ExtenderArgs) []string {
+	if len(args.NodeNames) > 0 {
+		return args.NodeNames
+	}
+	var names []string
+	for _, data := range args.Nodes {
+		node := &v1.Node{}
+		if err := node.Unmarshal(data); err == nil {
+			names = append(names, node.Name)
+		}
+	}
+	return names
+}
+
+func startGRPCExtenderServer(t *testing.T) (*fakeGRPCExtenderServer, string, func()) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	server := grpc.NewServer()
+	fake := &fakeGRPCExtenderServer{deltas: make(chan *extenderv1alpha1.NodeDelta, 10)}
+	extenderv1alpha1.RegisterExtenderServer(server, fake)
+	go server.Serve(listener)
+	return fake, listener.Addr().String(), server.Stop
+}
+
+func TestGRPCExtender(t *testing.T) {
+	fake, address, stopServer := startGRPCExtenderServer(t)
+	defer stopServer()
+	nodes := []*v1.Node{makeNode("machine1", 1000, 1000), makeNode("machine2", 1000, 1000), makeNode("machine3", 1000, 1000)}
+	nodeNameToInfo := schedulercache.CreateNodeNameToInfoMap(nil, nodes)
+	pod := &v1.Pod{}
+	pod.Name, pod.Namespace, pod.UID = "worker-0", "bar", "uid"
+
+	for _, nodeCacheCapable := range []bool{false, true} {
+		stop := make(chan struct{})
+		extender, err := NewGRPCExtender(&schedulerapi.ExtenderConfig{
+			URLPrefix:        address,
+			Transport:        schedulerapi.GRPCExtenderTransport,
+			FilterVerb:       "filter",
+			PrioritizeVerb:   "prioritize",
+			BindVerb:         "bind",
+			Weight:           2,
+			NodeCacheCapable: nodeCacheCapable,
+		}, schedulertesting.FakeNodeLister(nodes), stop)
+		if err != nil {
+			t.Fatalf("Failed to create extender: %v", err)
+		}
+
+		filtered, failed, err := extender.Filter(pod, nodes, nodeNameToInfo)
+		if err != nil {
+			t.Errorf("Unexpected error filtering with node cache %v: %v", nodeCacheCapable, err)
+		}
+		if !reflect.DeepEqual(filtered, []*v1.Node{nodes[0], nodes[2]}) || len(failed) != 1 || failed["machine2"] != "no license left" {
+			t.Errorf("Unexpected filter result with node cache %v: %v, %v", nodeCacheCapable, filtered, failed)
+		}
+
+		priorities, weight, err := extender.Prioritize(pod, nodes)
+		if err != nil {
+			t.Errorf("Unexpected error prioritizing with node cache %v: %v", nodeCacheCapable, err)
+		}
+		expected := schedulerapi.HostPriorityList{{Host: "machine1", Score: 10}, {Host: "machine2"}, {Host: "machine3"}}
+		if weight != 2 || !reflect.DeepEqual(*priorities, expected) {
+			t.Errorf("Unexpected priorities with node cache %v: %v with weight %d", nodeCacheCapable, *priorities, weight)
+		}
+		close(stop)
+	}
+
+	stop := make(chan struct{})
+	defer close(stop)
+	extender, err := NewGRPCExtender(&schedulerapi.ExtenderConfig{URLPrefix: address, BindVerb: "bind"}, schedulertesting.FakeNodeLister(nodes), stop)
+	if err != nil {
+		t.Fatalf("Failed to create extender: %v", err)
+	}
+	binding := &v1.Binding{ObjectMeta: pod.ObjectMeta, Target: v1.ObjectReference{Kind: "Node", Name: "machine1"}}
+	if err := extender.Bind(binding); err != nil {
+		t.Errorf("Unexpected error binding: %v", err)
+	}
+	expected := []*extenderv1alpha1.ExtenderBindingArgs{{PodName: "worker-0", PodNamespace: "bar", PodUid: "uid", Node: "machine1"}}
+	if !reflect.DeepEqual(fake.bindings, expected) {
+		t.Errorf("Expected bindings %v, got %v", expected, fake.bindings)
+	}
+}
+
+func TestGRPCExtenderSyncNodes(t *testing.T) {
+	fake, address, stopServer := startGRPCExtenderServer(t)
+	defer stopServer()
+	stop := make(chan struct{})
+	defer close(stop)
+	machine1 := makeNode("machine1", 1000, 1000)
+	extender, err := NewGRPCExtender(&schedulerapi.ExtenderConfig{URLPrefix: address, FilterVerb: "filter", NodeCacheCapable: true},
+		schedulertesting.FakeNodeLister([]*v1.Node{machine1}), stop)
+	if err != nil {
+		t.Fatalf("Failed to create extender: %v", err)
+	}
+	syncer := extender.(NodeSyncer)
+
+	expectDelta := func(deltaType extenderv1alpha1.NodeDelta_Type, name string) {
+		select {
+		case delta := <-fake.deltas:
+			deltaName := delta.NodeName
+			if len(delta.Node) > 0 {
+				node := &v1.Node{}
+				if err := node.Unmarshal(delta.Node); err != nil {
+					t.Fatalf("Failed to unmarshal node: %v", err)
+				}
+				deltaName = node.Name
+			}
+			if delta.Type != deltaType || deltaName != name {
+				t.Errorf("Expected %v of node %q, got %v of node %q", deltaType, name, delta.Type, deltaName)
+			}
+		case <-time.After(wait.ForeverTestTimeout):
+			t.Fatalf("Timed out waiting for %v of node %q", deltaType, name)
+		}
+	}
+	expectDelta(extenderv1alpha1.NodeDelta_RESET, "")
+	expectDelta(extenderv1alpha1.NodeDelta_ADDED, "machine1")
+
+	machine2 := makeNode("machine2", 1000, 1000)
+	syncer.AddNode(machine2)
+	expectDelta(extenderv1alpha1.NodeDelta_ADDED, "machine2")
+
+	heartbeat := *machine2
+	heartbeat.Status.Conditions = []v1.NodeCondition{{Type: v1.NodeReady, Status: v1.ConditionTrue}}
+	syncer.UpdateNode(machine2, &heartbeat)
+	expectDelta(extenderv1alpha1.NodeDelta_MODIFIED, "machine2")
+	updated := heartbeat
+	updated.Status.Conditions = []v1.NodeCondition{{Type: v1.NodeReady, Status: v1.ConditionTrue, LastHeartbeatTime: metav1.Now()}}
+	syncer.UpdateNode(&heartbeat, &updated)
+
+	syncer.RemoveNode(machine1)
+	expectDelta(extenderv1alpha1.NodeDelta_DELETED, "machine1")
+}
@@ -0,0 +1,68 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"sort"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/kubernetes/plugin/pkg/scheduler/algorithm"
+)
+
+// defaultPredicatesOrdering is the order the predicates are evaluated in on a node, unless
+// configured otherwise. Cheap checks of the node and of its resources come first, checks
+// looking at the pods on other nodes last. Predicates not listed are evaluated after these,
+// in the order of their names.
+var defaultPredicatesOrdering = []string{
+	"GeneralPredicates", "PodFitsResources", "HostName", "PodFitsHostPorts", "PodFitsPorts",
+	"MatchNodeSelector", "MatchAcceleratorType", "PodToleratesNodeTaints",
+	"CheckNodeMemoryPressure", "CheckNodeDiskPressure", "NoDiskConflict",
+	"NoVolumeZoneConflict", "MaxEBSVolumeCount", "MaxGCEPDVolumeCount", "MaxAzureDiskVolumeCount",
+	"NoVolumeNodeConflict", "MatchRoleConstraints", "MatchRoleSpread", "MatchInterPodAffinity",
+}
+
+// PredicateEvaluation configures how the predicates are evaluated on a node.
+type PredicateEvaluation struct {
+	// Ordering is the order the predicates are evaluated in. Predicates not listed are
+	// evaluated after these, in the default order.
+	Ordering []string
+	// ShortCircuit stops the evaluation on a node at the first predicate the pod fails.
+	// Otherwise all predicates are evaluated, so that all failure reasons are reported.
+	ShortCircuit bool
+}
+
+// orderedPredicateKeys returns the keys of predicateFuncs in the order they are evaluated in.
+func (e PredicateEvaluation) orderedPredicateKeys(predicateFuncs map[string]algorithm.FitPredicate) []string {
+	keys := make([]string, 0, len(predicateFuncs))
+	ordered := sets.NewString()
+	for _, ordering := range [][]string{e.Ordering, defaultPredicatesOrdering} {
+		for _, key := range ordering {
+			if _, ok := predicateFuncs[key]; ok && !ordered.Has(key) {
+				keys = append(keys, key)
+				ordered.Insert(key)
+			}
+		}
+	}
+	var others []string
+	for key := range predicateFuncs {
+		if !ordered.Has(key) {
+			others = append(others, key)
+		}
+	}
+	sort.Strings(others)
+	return append(keys, others...)
+}
@@ -0,0 +1,96 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"reflect"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kubernetes/pkg/api/v1"
+	"k8s.io/kubernetes/plugin/pkg/scheduler/algorithm"
+	algorithmpredicates "k8s.io/kubernetes/plugin/pkg/scheduler/algorithm/predicates"
+	"k8s.io/kubernetes/plugin/pkg/scheduler/schedulercache"
+)
+
+func TestOrderedPredicateKeys(t *testing.T) {
+	predicateFuncs := map[string]algorithm.FitPredicate{
+		"MatchInterPodAffinity": truePredicate,
+		"PodFitsResources":      truePredicate,
+		"MatchNodeSelector":     truePredicate,
+		"custom-b":              truePredicate,
+		"custom-a":              truePredicate,
+	}
+	tests := []struct {
+		name       string
+		evaluation PredicateEvaluation
+		expected   []string
+	}{
+		{
+			name:     "default ordering, unknown predicates last by name",
+			expected: []string{"PodFitsResources", "MatchNodeSelector", "MatchInterPodAffinity", "custom-a", "custom-b"},
+		},
+		{
+			name:       "configured ordering first, skipping predicates not in use",
+			evaluation: PredicateEvaluation{Ordering: []string{"custom-b", "NoDiskConflict", "MatchNodeSelector"}},
+			expected:   []string{"custom-b", "MatchNodeSelector", "PodFitsResources", "MatchInterPodAffinity", "custom-a"},
+		},
+	}
+	for _, test := range tests {
+		if keys := test.evaluation.orderedPredicateKeys(predicateFuncs); !reflect.DeepEqual(keys, test.expected) {
+			t.Errorf("%s: expected %v, got %v", test.name, test.expected, keys)
+		}
+	}
+}
+
+func TestPodFitsOnNodeShortCircuit(t *testing.T) {
+	evaluated := 0
+	countingFalsePredicate := func(pod *v1.Pod, meta interface{}, nodeInfo *schedulercache.NodeInfo) (bool, []algorithm.PredicateFailureReason, error) {
+		evaluated++
+		return falsePredicate(pod, meta, nodeInfo)
+	}
+	predicateFuncs := map[string]algorithm.FitPredicate{"a": countingFalsePredicate, "b": countingFalsePredicate}
+	predicateKeys := PredicateEvaluation{}.orderedPredicateKeys(predicateFuncs)
+	info := schedulercache.NewNodeInfo()
+	info.SetNode(&v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "machine"}})
+
+	tests := []struct {
+		shortCircuit      bool
+		expectedEvaluated int
+	}{
+		{shortCircuit: false, expectedEvaluated: 2},
+		{shortCircuit: true, expectedEvaluated: 1},
+	}
+	for _, test := range tests {
+		evaluated = 0
+		fits, reasons, err := podFitsOnNode(&v1.Pod{}, nil, info, predicateFuncs, predicateKeys, test.shortCircuit, nil, nil)
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+		if fits {
+			t.Errorf("short circuit %v: expected the pod not to fit", test.shortCircuit)
+		}
+		if evaluated != test.expectedEvaluated || len(reasons) != test.expectedEvaluated {
+			t.Errorf("short circuit %v: expected %d predicates evaluated, got %d with reasons %v", test.shortCircuit, test.expectedEvaluated, evaluated, reasons)
+		}
+		for _, reason := range reasons {
+			if reason != algorithmpredicates.ErrFakePredicate {
+				t.Errorf("unexpected failure reason: %v", reason)
+			}
+		}
+	}
+}
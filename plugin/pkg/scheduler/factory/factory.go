@@ -20,6 +20,8 @@ package factory
 
 import (
 	"fmt"
+	"reflect"
+	"sync"
 	"time"
 
 	"github.com/golang/glog"
@@ -32,6 +34,7 @@ import (
 	"k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/flowcontrol"
 	"k8s.io/kubernetes/pkg/api/v1"
 	podutil "k8s.io/kubernetes/pkg/api/v1/pod"
 	"k8s.io/kubernetes/pkg/client/clientset_generated/clientset"
@@ -60,10 +63,15 @@ const (
 // ConfigFactory is the default implementation of the scheduler.Configurator interface.
 // TODO make this private if possible, so that only its interface is externally used.
 type ConfigFactory struct {
-	client   clientset.Interface
-	groupMap map[string]*schedulerapi.SchedulingGroup
-	// queue for groups that need scheduling
-	groupQueue *cache.FIFO
+	client clientset.Interface
+	// groupLock guards groupMap and the pending pods of the groups in it, groups are forgotten
+	// by the workers scheduling them. The workers schedule copies of the groups taken under it.
+	groupLock sync.Mutex
+	groupMap  map[string]*schedulerapi.SchedulingGroup
+	// queue for groups that need scheduling, ordered by priority
+	groupQueue *groupQueue
+	// queue for pods that are not members of a group and are scheduled on their own
+	podQueue *cache.FIFO
 	// a means to list all known scheduled pods.
 	scheduledPodLister corelisters.PodLister
 	// a means to list all known scheduled pods and pods assumed to have been scheduled.
@@ -101,6 +109,13 @@ type ConfigFactory struct {
 
 	// Equivalence class cache
 	equivalencePodCache *core.EquivalenceCache
+
+	// GPU requests of the pending members of scheduling groups
+	gpuDemand *pendingGPUDemand
+
+	// Extenders the changes of the nodes are streamed to
+	nodeSyncersLock sync.Mutex
+	nodeSyncers     []core.NodeSyncer
 }
 
 // NewConfigFactory initializes the default implementation of a Configurator To encourage eventual privatization of the struct type, we only
@@ -125,7 +140,8 @@ func NewConfigFactory(
 		client:                         client,
 		podLister:                      schedulerCache,
 		groupMap:                       make(map[string]*schedulerapi.SchedulingGroup),
-		groupQueue:                     cache.NewFIFO(tools.KeyFunc),
+		groupQueue:                     newGroupQueue(),
+		podQueue:                       cache.NewFIFO(cache.MetaNamespaceKeyFunc),
 		pVLister:                       pvInformer.Lister(),
 		pVCLister:                      pvcInformer.Lister(),
 		serviceLister:                  serviceInformer.Lister(),
@@ -136,6 +152,15 @@ func NewConfigFactory(
 		StopEverything:                 stopEverything,
 		schedulerName:                  schedulerName,
 		hardPodAffinitySymmetricWeight: hardPodAffinitySymmetricWeight,
+		gpuDemand:                      newPendingGPUDemand(),
+	}
+
+	// Members of a gang role are equivalent, the predicates not affected by the members
+	// placed before them are evaluated once per role and node. The cache is shared by all
+	// configs the factory creates, so the informers invalidate the one in use after a reload.
+	if getEquivalencePodFunc != nil {
+		c.equivalencePodCache = core.NewEquivalenceCache(getEquivalencePodFunc)
+		glog.V(2).Infof("Created equivalence class cache")
 	}
 
 	c.scheduledPodsHasSynced = podInformer.Informer().HasSynced
@@ -164,7 +189,9 @@ func NewConfigFactory(
 			FilterFunc: func(obj interface{}) bool {
 				switch t := obj.(type) {
 				case *v1.Pod:
-					return unassignedNonTerminatedPod(t)
+					// Members of groups are kept whatever their scheduler, to find the groups
+					// whose members disagree on it.
+					return unassignedNonTerminatedPod(t) && (c.ResponsibleForPod(t) || tools.HasSchedulingGroup(t))
 				default:
 					runtime.HandleError(fmt.Errorf("unable to handle object in %T: %T", c, obj))
 					return false
@@ -172,23 +199,61 @@ func NewConfigFactory(
 			},
 			Handler: cache.ResourceEventHandlerFuncs{
 				AddFunc: func(obj interface{}) {
+					if pod, ok := obj.(*v1.Pod); ok && !tools.HasSchedulingGroup(pod) {
+						if err := c.podQueue.Add(pod); err != nil {
+							runtime.HandleError(fmt.Errorf("unable to queue %T: %v", obj, err))
+						}
+						c.gpuDemand.update(pod)
+						return
+					}
 					pod, mini, targetGroup := c.GetSchedulingGroup(obj, true)
 					if pod == nil || mini == nil || targetGroup == nil {
 						glog.Warningf("Add: failed to get scheduling group.")
 						return
 					}
 					c.AddPodToResourceObject(pod, mini, targetGroup)
+					c.gpuDemand.update(pod)
 				},
 				UpdateFunc: func(oldObj, newObj interface{}) {
+					if pod, ok := newObj.(*v1.Pod); ok && !tools.HasSchedulingGroup(pod) {
+						if err := c.podQueue.Update(pod); err != nil {
+							runtime.HandleError(fmt.Errorf("unable to update %T: %v", newObj, err))
+						}
+						c.gpuDemand.update(pod)
+						return
+					}
 					pod, mini, targetGroup := c.GetSchedulingGroup(newObj, false)
 					if pod == nil || mini == nil || targetGroup == nil {
 						glog.Warningf("Update: failed to get scheduling group.")
 						return
 					}
 					c.UpdatePodInResourceObject(pod, mini, targetGroup)
+					c.gpuDemand.update(pod)
 				},
 				DeleteFunc: func(obj interface{}) {
-					pod, mini, targetGroup := c.GetSchedulingGroup(obj, false)
+					var pod *v1.Pod
+					switch t := obj.(type) {
+					case *v1.Pod:
+						pod = t
+					case cache.DeletedFinalStateUnknown:
+						var ok bool
+						pod, ok = t.Obj.(*v1.Pod)
+						if !ok {
+							glog.Errorf("cannot convert to *v1.Pod: %v", t.Obj)
+							return
+						}
+					default:
+						glog.Errorf("cannot convert to *v1.Pod: %v", t)
+						return
+					}
+					c.gpuDemand.remove(pod)
+					if !tools.HasSchedulingGroup(pod) {
+						if err := c.podQueue.Delete(pod); err != nil {
+							runtime.HandleError(fmt.Errorf("unable to dequeue %T: %v", obj, err))
+						}
+						return
+					}
+					pod, mini, targetGroup := c.GetSchedulingGroup(pod, false)
 					if pod == nil || mini == nil || targetGroup == nil {
 						glog.Info("Delete: scheduling group is not exists.")
 						return
@@ -214,8 +279,6 @@ func NewConfigFactory(
 	)
 	c.nodeLister = nodeInformer.Lister()
 
-	// TODO(harryz) need to fill all the handlers here and below for equivalence cache
-
 	return c
 }
 
@@ -227,24 +290,35 @@ func (c *ConfigFactory) GetSchedulingGroup(obj interface{}, newIfNotExists bool)
 	}
 
 	miniGroup := tools.GetSchedulingGroup(pod)
+	c.groupLock.Lock()
+	defer c.groupLock.Unlock()
 	targetGroup, ok := c.groupMap[miniGroup.Group]
 
 	if !ok {
 		if !newIfNotExists {
 			return nil, nil, nil
 		}
-		targetGroup = tools.MiniGroupToGroup(miniGroup)
+		targetGroup = tools.MiniGroupToGroup(miniGroup, pod)
 		c.groupMap[miniGroup.Group] = targetGroup
 		glog.V(4).Infof("add group %s to group queue.", targetGroup.Group)
 		if err := c.groupQueue.Add(targetGroup); err != nil {
 			runtime.HandleError(fmt.Errorf("unable to queue %T: %v", obj, err))
 		}
+	} else if newIfNotExists && c.ResponsibleForPod(pod) && !c.ResponsibleForGroup(targetGroup) {
+		// The group was created by a member of another scheduler. This scheduler takes it
+		// over to report that its members disagree on the scheduler.
+		glog.Warningf("Members of group %s disagree on the scheduler, %s and %s", targetGroup.Group, targetGroup.SchedulerName, pod.Spec.SchedulerName)
+		targetGroup.SchedulerName = pod.Spec.SchedulerName
+		c.groupQueue.AddIfNotPresent(targetGroup)
 	}
 
 	return pod, miniGroup, targetGroup
 }
 
 func (c *ConfigFactory) AddPodToResourceObject(pod *v1.Pod, miniGroup *schedulerapi.MiniGroup, group *schedulerapi.SchedulingGroup) {
+	c.groupLock.Lock()
+	defer c.groupLock.Unlock()
+	defer c.groupQueue.Reprioritize(group)
 	for _, ro := range group.Resources {
 		if ro.Role == miniGroup.Role {
 			_, ok := ro.PendingPods[pod.Name]
@@ -262,6 +336,8 @@ func (c *ConfigFactory) AddPodToResourceObject(pod *v1.Pod, miniGroup *scheduler
 		Min:             miniGroup.MinReplicas,
 		Max:             miniGroup.MaxReplicas,
 		Priority:        miniGroup.Priority,
+		DependsOn:       miniGroup.DependsOn,
+		Accelerator:     miniGroup.Accelerator,
 	}
 	resourceObject.PendingPods[pod.Name] = pod
 	resourceObject.PendingPodCount++
@@ -270,6 +346,9 @@ func (c *ConfigFactory) AddPodToResourceObject(pod *v1.Pod, miniGroup *scheduler
 }
 
 func (c *ConfigFactory) UpdatePodInResourceObject(pod *v1.Pod, miniGroup *schedulerapi.MiniGroup, group *schedulerapi.SchedulingGroup) {
+	c.groupLock.Lock()
+	defer c.groupLock.Unlock()
+	defer c.groupQueue.Reprioritize(group)
 	for _, ro := range group.Resources {
 		if ro.Role == miniGroup.Role {
 			_, ok := ro.PendingPods[pod.Name]
@@ -282,9 +361,13 @@ func (c *ConfigFactory) UpdatePodInResourceObject(pod *v1.Pod, miniGroup *schedu
 }
 
 func (c *ConfigFactory) DeletePodInResourceObject(pod *v1.Pod, miniGroup *schedulerapi.MiniGroup, group *schedulerapi.SchedulingGroup) {
+	c.groupLock.Lock()
+	defer c.groupLock.Unlock()
+	defer c.groupQueue.Reprioritize(group)
 	zeroPodResourceObjectCount := 0
 	for _, ro := range group.Resources {
-		if ro.Role == miniGroup.Role {
+		// Pods bound by the scheduler are no longer pending already.
+		if _, ok := ro.PendingPods[pod.Name]; ok && ro.Role == miniGroup.Role {
 			delete(ro.PendingPods, pod.Name)
 			ro.PendingPodCount--
 		}
@@ -323,7 +406,6 @@ func (c *ConfigFactory) GetScheduledPodLister() corelisters.PodLister {
 	return c.scheduledPodLister
 }
 
-// TODO(resouer) need to update all the handlers here and below for equivalence cache
 func (c *ConfigFactory) addPodToCache(obj interface{}) {
 	pod, ok := obj.(*v1.Pod)
 	if !ok {
@@ -334,6 +416,9 @@ func (c *ConfigFactory) addPodToCache(obj interface{}) {
 	if err := c.schedulerCache.AddPod(pod); err != nil {
 		glog.Errorf("scheduler cache AddPod failed: %v", err)
 	}
+	if c.equivalencePodCache != nil {
+		c.equivalencePodCache.InvalidateCachedPredicateItemForPodAdd(pod, pod.Spec.NodeName)
+	}
 }
 
 func (c *ConfigFactory) updatePodInCache(oldObj, newObj interface{}) {
@@ -351,6 +436,25 @@ func (c *ConfigFactory) updatePodInCache(oldObj, newObj interface{}) {
 	if err := c.schedulerCache.UpdatePod(oldPod, newPod); err != nil {
 		glog.Errorf("scheduler cache UpdatePod failed: %v", err)
 	}
+	c.invalidateCachedPredicatesOnUpdatePod(newPod, oldPod)
+}
+
+func (c *ConfigFactory) invalidateCachedPredicatesOnUpdatePod(newPod *v1.Pod, oldPod *v1.Pod) {
+	if c.equivalencePodCache == nil {
+		return
+	}
+	// If the bound node of the pod has been changed, that case is handled by pod add & delete.
+	if len(newPod.Spec.NodeName) == 0 || newPod.Spec.NodeName != oldPod.Spec.NodeName {
+		return
+	}
+	if !reflect.DeepEqual(oldPod.GetLabels(), newPod.GetLabels()) {
+		// MatchInterPodAffinity needs to be reconsidered for all nodes in the
+		// failure domains of the node.
+		c.equivalencePodCache.InvalidateCachedPredicateItemOfAllNodes(sets.NewString("MatchInterPodAffinity"))
+	}
+	if !reflect.DeepEqual(predicates.GetResourceRequest(newPod), predicates.GetResourceRequest(oldPod)) {
+		c.equivalencePodCache.InvalidateCachedPredicateItemForPodAdd(newPod, newPod.Spec.NodeName)
+	}
 }
 
 func (c *ConfigFactory) deletePodFromCache(obj interface{}) {
@@ -372,6 +476,9 @@ func (c *ConfigFactory) deletePodFromCache(obj interface{}) {
 	if err := c.schedulerCache.RemovePod(pod); err != nil {
 		glog.Errorf("scheduler cache RemovePod failed: %v", err)
 	}
+	if c.equivalencePodCache != nil {
+		c.equivalencePodCache.InvalidateCachedPredicateItemForPodDelete(pod, pod.Spec.NodeName)
+	}
 }
 
 func (c *ConfigFactory) addNodeToCache(obj interface{}) {
@@ -384,6 +491,7 @@ func (c *ConfigFactory) addNodeToCache(obj interface{}) {
 	if err := c.schedulerCache.AddNode(node); err != nil {
 		glog.Errorf("scheduler cache AddNode failed: %v", err)
 	}
+	c.syncNodes(func(syncer core.NodeSyncer) { syncer.AddNode(node) })
 }
 
 func (c *ConfigFactory) updateNodeInCache(oldObj, newObj interface{}) {
@@ -401,6 +509,39 @@ func (c *ConfigFactory) updateNodeInCache(oldObj, newObj interface{}) {
 	if err := c.schedulerCache.UpdateNode(oldNode, newNode); err != nil {
 		glog.Errorf("scheduler cache UpdateNode failed: %v", err)
 	}
+	c.invalidateCachedPredicatesOnNodeUpdate(newNode, oldNode)
+	c.syncNodes(func(syncer core.NodeSyncer) { syncer.UpdateNode(oldNode, newNode) })
+}
+
+func (c *ConfigFactory) invalidateCachedPredicatesOnNodeUpdate(newNode *v1.Node, oldNode *v1.Node) {
+	if c.equivalencePodCache == nil {
+		return
+	}
+	// Node status is updated by every heartbeat, only the fields the predicates read
+	// invalidate the cached results of the node.
+	if !reflect.DeepEqual(oldNode.Status.Allocatable, newNode.Status.Allocatable) ||
+		!reflect.DeepEqual(oldNode.Spec, newNode.Spec) ||
+		!reflect.DeepEqual(oldNode.GetAnnotations(), newNode.GetAnnotations()) ||
+		!reflect.DeepEqual(nodeConditionStatuses(oldNode), nodeConditionStatuses(newNode)) {
+		c.equivalencePodCache.InvalidateAllCachedPredicateItemOfNode(newNode.Name)
+	}
+	if !reflect.DeepEqual(oldNode.GetLabels(), newNode.GetLabels()) {
+		// The topology domains of the node changed, which affects the predicates counting
+		// pods and group members in the domains on all nodes.
+		c.equivalencePodCache.InvalidateAllCachedPredicateItemOfNode(newNode.Name)
+		c.equivalencePodCache.InvalidateCachedPredicateItemOfAllNodes(
+			sets.NewString("MatchInterPodAffinity", "MatchRoleConstraints", "MatchRoleSpread"))
+	}
+}
+
+// nodeConditionStatuses returns the status of each condition of the node, leaving out the
+// heartbeat and transition times.
+func nodeConditionStatuses(node *v1.Node) map[v1.NodeConditionType]v1.ConditionStatus {
+	statuses := make(map[v1.NodeConditionType]v1.ConditionStatus, len(node.Status.Conditions))
+	for _, condition := range node.Status.Conditions {
+		statuses[condition.Type] = condition.Status
+	}
+	return statuses
 }
 
 func (c *ConfigFactory) deleteNodeFromCache(obj interface{}) {
@@ -422,6 +563,10 @@ func (c *ConfigFactory) deleteNodeFromCache(obj interface{}) {
 	if err := c.schedulerCache.RemoveNode(node); err != nil {
 		glog.Errorf("scheduler cache RemoveNode failed: %v", err)
 	}
+	c.syncNodes(func(syncer core.NodeSyncer) { syncer.RemoveNode(node) })
+	if c.equivalencePodCache != nil {
+		c.equivalencePodCache.InvalidateAllCachedPredicateItemOfNode(node.Name)
+	}
 }
 
 // Create creates a scheduler with the default algorithm provider.
@@ -437,7 +582,42 @@ func (f *ConfigFactory) CreateFromProvider(providerName string) (*scheduler.Conf
 		return nil, err
 	}
 
-	return f.CreateFromKeys(provider.FitPredicateKeys, provider.PriorityFunctionKeys, []algorithm.SchedulerExtender{})
+	config, err := f.CreateFromKeys(provider.FitPredicateKeys, provider.PriorityFunctionKeys, []algorithm.SchedulerExtender{})
+	if err != nil {
+		return nil, err
+	}
+	f.setNodeSyncers(nil)
+	return config, nil
+}
+
+// configOptions are the options of a config which a policy may set. They are kept apart from
+// the factory, which creates the configs reloaded from a new policy while the current config
+// keeps being used.
+type configOptions struct {
+	// HardPodAffinitySymmetricWeight represents the weight of implicit PreferredDuringScheduling
+	// affinity rule, in the range 0-100.
+	hardPodAffinitySymmetricWeight int
+
+	// How many nodes are evaluated for a pod, and how many at once
+	nodeSampling core.NodeSampling
+
+	// The order of the predicates, and if they are evaluated after one fails
+	predicateEvaluation core.PredicateEvaluation
+
+	// How many groups are scheduled at once
+	groupWorkers int
+
+	// How many members of a group are bound at once, and how long they may take
+	binding scheduler.BindingPolicy
+
+	// The rate events, pod conditions and group status are written at
+	statusUpdateQPS   float32
+	statusUpdateBurst int
+}
+
+// defaultOptions returns the options of a config created without a policy.
+func (f *ConfigFactory) defaultOptions() configOptions {
+	return configOptions{hardPodAffinitySymmetricWeight: f.hardPodAffinitySymmetricWeight}
 }
 
 // Creates a scheduler from the configuration file
@@ -449,84 +629,210 @@ func (f *ConfigFactory) CreateFromConfig(policy schedulerapi.Policy) (*scheduler
 		return nil, err
 	}
 
-	predicateKeys := sets.NewString()
-	for _, predicate := range policy.Predicates {
-		glog.V(2).Infof("Registering predicate: %s", predicate.Name)
-		predicateKeys.Insert(RegisterCustomFitPredicate(predicate))
-	}
+	// The predicates and priorities are registered once the whole policy was created, a policy
+	// which fails to be created doesn't change the registry.
+	factories := getCustomPluginFactories(policy.Predicates, policy.Priorities)
 
-	priorityKeys := sets.NewString()
-	for _, priority := range policy.Priorities {
-		glog.V(2).Infof("Registering priority: %s", priority.Name)
-		priorityKeys.Insert(RegisterCustomPriorityFunction(priority))
+	// The extenders of the config are stopped once it is replaced by a reloaded one.
+	stopExtenders := make(chan struct{})
+	var stopOnce sync.Once
+	stop := func() { stopOnce.Do(func() { close(stopExtenders) }) }
+	extenders, nodeSyncers, err := f.createExtenders(policy.ExtenderConfigs, stopExtenders)
+	if err != nil {
+		stop()
+		return nil, err
+	}
+	options := f.defaultOptions()
+	// Providing HardPodAffinitySymmetricWeight in the policy config is the new and preferred way of providing the value.
+	// Give it higher precedence than scheduler CLI configuration when it is provided.
+	if policy.HardPodAffinitySymmetricWeight != 0 {
+		options.hardPodAffinitySymmetricWeight = policy.HardPodAffinitySymmetricWeight
+	}
+	options.nodeSampling = core.NodeSampling{
+		Parallelism:             policy.Parallelism,
+		NumFeasibleNodesToFind:  policy.NumFeasibleNodesToFind,
+		PercentageOfNodesToFind: policy.PercentageOfNodesToFind,
 	}
+	options.predicateEvaluation = core.PredicateEvaluation{
+		Ordering:     policy.PredicateOrdering,
+		ShortCircuit: policy.ShortCircuitPredicates,
+	}
+	options.groupWorkers = policy.GroupWorkers
+	options.binding = scheduler.BindingPolicy{
+		Workers: policy.BindingWorkers,
+		Timeout: time.Duration(policy.BindingTimeoutSeconds) * time.Second,
+	}
+	options.statusUpdateQPS = policy.StatusUpdateQPS
+	options.statusUpdateBurst = policy.StatusUpdateBurst
+	config, err := f.createFromPlugins(factories, extenders, options)
+	if err != nil {
+		stop()
+		return nil, err
+	}
+	profiles, profileNodeSyncers, err := f.createProfiles(policy.Profiles, stopExtenders, options)
+	if err != nil {
+		stop()
+		return nil, err
+	}
+	config.Profiles = profiles
+	config.NamespaceProfiles = policy.NamespaceProfiles
+	factories.register()
+	f.setNodeSyncers(append(nodeSyncers, profileNodeSyncers...))
+	config.StopExtenders = stop
+	go func() {
+		select {
+		case <-f.StopEverything:
+			stop()
+		case <-stopExtenders:
+		}
+	}()
+	return config, nil
+}
 
+// createExtenders creates the extenders of the configs, and returns the ones caching nodes
+// the changes of the nodes are streamed to as well. The health of the extenders is exposed
+// until stop is closed.
+func (f *ConfigFactory) createExtenders(configs []schedulerapi.ExtenderConfig, stop <-chan struct{}) ([]algorithm.SchedulerExtender, []core.NodeSyncer, error) {
 	extenders := make([]algorithm.SchedulerExtender, 0)
-	if len(policy.ExtenderConfigs) != 0 {
-		for ii := range policy.ExtenderConfigs {
-			glog.V(2).Infof("Creating extender with config %+v", policy.ExtenderConfigs[ii])
-			if extender, err := core.NewHTTPExtender(&policy.ExtenderConfigs[ii]); err != nil {
-				return nil, err
-			} else {
-				extenders = append(extenders, extender)
-			}
+	var nodeSyncers []core.NodeSyncer
+	for ii := range configs {
+		glog.V(2).Infof("Creating extender with config %+v", configs[ii])
+		extender, err := f.newExtender(&configs[ii], stop)
+		if err != nil {
+			return nil, nil, err
+		}
+		go func() {
+			<-stop
+			core.UnregisterExtender(extender)
+		}()
+		extenders = append(extenders, extender)
+		if syncer, ok := extender.(core.NodeSyncer); ok && configs[ii].NodeCacheCapable {
+			nodeSyncers = append(nodeSyncers, syncer)
 		}
 	}
-	// Providing HardPodAffinitySymmetricWeight in the policy config is the new and preferred way of providing the value.
-	// Give it higher precedence than scheduler CLI configuration when it is provided.
-	if policy.HardPodAffinitySymmetricWeight != 0 {
-		f.hardPodAffinitySymmetricWeight = policy.HardPodAffinitySymmetricWeight
+	return extenders, nodeSyncers, nil
+}
+
+// createProfiles creates the algorithm of each profile. The predicates and priorities of a
+// profile are resolved on their own and not registered, so that a profile is not affected by
+// the weights and arguments another profile or the policy uses under the same names.
+func (f *ConfigFactory) createProfiles(policies []schedulerapi.ProfilePolicy, stop <-chan struct{}, options configOptions) (map[string]*scheduler.Profile, []core.NodeSyncer, error) {
+	if len(policies) == 0 {
+		return nil, nil, nil
+	}
+	profiles := make(map[string]*scheduler.Profile, len(policies))
+	var nodeSyncers []core.NodeSyncer
+	for _, policy := range policies {
+		glog.V(2).Infof("Creating scheduling profile %s", policy.Name)
+		factories := getCustomPluginFactories(policy.Predicates, policy.Priorities)
+		extenders, syncers, err := f.createExtenders(policy.ExtenderConfigs, stop)
+		if err != nil {
+			return nil, nil, fmt.Errorf("profile %s: %v", policy.Name, err)
+		}
+		algo, err := f.createAlgorithm(factories, extenders, options)
+		if err != nil {
+			return nil, nil, fmt.Errorf("profile %s: %v", policy.Name, err)
+		}
+		profiles[policy.Name] = &scheduler.Profile{
+			Algorithm:      algo,
+			GetBinder:      f.getBinder(extenders),
+			GroupExtenders: f.getGroupExtenders(extenders),
+		}
+		nodeSyncers = append(nodeSyncers, syncers...)
 	}
-	return f.CreateFromKeys(predicateKeys, priorityKeys, extenders)
+	return profiles, nodeSyncers, nil
 }
 
-// getBinder returns an extender that supports bind or a default binder.
-func (f *ConfigFactory) getBinder(extenders []algorithm.SchedulerExtender) scheduler.Binder {
+// newExtender creates the extender with the transport of the config. Extenders over gRPC
+// keep their connection until stop is closed.
+func (f *ConfigFactory) newExtender(config *schedulerapi.ExtenderConfig, stop <-chan struct{}) (algorithm.SchedulerExtender, error) {
+	if config.Transport != schedulerapi.GRPCExtenderTransport {
+		return core.NewHTTPExtender(config)
+	}
+	return core.NewGRPCExtender(config, &allNodesLister{f.nodeLister}, stop)
+}
+
+// setNodeSyncers replaces the extenders the changes of the nodes are streamed to by the
+// ones of the config created last.
+func (c *ConfigFactory) setNodeSyncers(nodeSyncers []core.NodeSyncer) {
+	c.nodeSyncersLock.Lock()
+	defer c.nodeSyncersLock.Unlock()
+	c.nodeSyncers = nodeSyncers
+}
+
+// syncNodes passes a change of the nodes on to the extenders caching nodes.
+func (c *ConfigFactory) syncNodes(notify func(syncer core.NodeSyncer)) {
+	c.nodeSyncersLock.Lock()
+	defer c.nodeSyncersLock.Unlock()
+	for _, syncer := range c.nodeSyncers {
+		notify(syncer)
+	}
+}
+
+// getBinder returns a function returning the extender that supports bind if it is
+// interested in the pod, or the default binder.
+func (f *ConfigFactory) getBinder(extenders []algorithm.SchedulerExtender) func(pod *v1.Pod) scheduler.Binder {
+	defaultBinder := &binder{f.client}
+	return func(pod *v1.Pod) scheduler.Binder {
+		for i := range extenders {
+			if extenders[i].IsBinder() && extenders[i].IsInterested(pod) {
+				return extenders[i]
+			}
+		}
+		return defaultBinder
+	}
+}
+
+// getGroupExtenders returns the extenders that are called with whole groups.
+func (f *ConfigFactory) getGroupExtenders(extenders []algorithm.SchedulerExtender) []algorithm.GroupExtender {
+	var groupExtenders []algorithm.GroupExtender
 	for i := range extenders {
-		if extenders[i].IsBinder() {
-			return extenders[i]
+		if groupExtender, ok := extenders[i].(algorithm.GroupExtender); ok {
+			groupExtenders = append(groupExtenders, groupExtender)
 		}
 	}
-	return &binder{f.client}
+	return groupExtenders
 }
 
 // Creates a scheduler from a set of registered fit predicate keys and priority keys.
 func (f *ConfigFactory) CreateFromKeys(predicateKeys, priorityKeys sets.String, extenders []algorithm.SchedulerExtender) (*scheduler.Config, error) {
 	glog.V(2).Infof("Creating scheduler with fit predicates '%v' and priority functions '%v", predicateKeys, priorityKeys)
 
-	if f.GetHardPodAffinitySymmetricWeight() < 1 || f.GetHardPodAffinitySymmetricWeight() > 100 {
-		return nil, fmt.Errorf("invalid hardPodAffinitySymmetricWeight: %d, must be in the range 1-100", f.GetHardPodAffinitySymmetricWeight())
-	}
-
-	predicateFuncs, err := f.GetPredicates(predicateKeys)
+	factories, err := getPluginFactories(predicateKeys, priorityKeys)
 	if err != nil {
 		return nil, err
 	}
+	return f.createFromPlugins(factories, extenders, f.defaultOptions())
+}
 
-	priorityConfigs, err := f.GetPriorityFunctionConfigs(priorityKeys)
-	if err != nil {
-		return nil, err
+// createFromPlugins creates a scheduler from the factories of its predicates and priorities
+// with the options given.
+func (f *ConfigFactory) createFromPlugins(factories *pluginFactories, extenders []algorithm.SchedulerExtender, options configOptions) (*scheduler.Config, error) {
+	if options.hardPodAffinitySymmetricWeight < 1 || options.hardPodAffinitySymmetricWeight > 100 {
+		return nil, fmt.Errorf("invalid hardPodAffinitySymmetricWeight: %d, must be in the range 1-100", options.hardPodAffinitySymmetricWeight)
 	}
 
-	priorityMetaProducer, err := f.GetPriorityMetadataProducer()
+	algo, err := f.createAlgorithm(factories, extenders, options)
 	if err != nil {
 		return nil, err
 	}
-
-	predicateMetaProducer, err := f.GetPredicateMetadataProducer()
-	if err != nil {
-		return nil, err
+	qps, burst := options.statusUpdateQPS, options.statusUpdateBurst
+	if qps == 0 {
+		qps = scheduler.DefaultStatusUpdateQPS
 	}
-
-	// TODO(resouer) use equivalence cache instead of nil here when #36238 get merged
-	algo := core.NewGenericScheduler(f.schedulerCache, nil, predicateFuncs, predicateMetaProducer, priorityConfigs, priorityMetaProducer, extenders)
-	//podBackoff := util.CreateDefaultPodBackoff()
+	if burst == 0 {
+		burst = scheduler.DefaultStatusUpdateBurst
+	}
+	podBackoff := util.CreateDefaultPodBackoff()
 	return &scheduler.Config{
 		SchedulerCache: f.schedulerCache,
+		Ecache:         f.equivalencePodCache,
 		// The scheduler only needs to consider schedulable nodes.
 		NodeLister:          &nodePredicateLister{f.nodeLister},
 		Algorithm:           algo,
-		Binder:              f.getBinder(extenders),
+		Binder:              &binder{f.client},
+		GetBinder:           f.getBinder(extenders),
+		GroupExtenders:      f.getGroupExtenders(extenders),
 		PodConditionUpdater: &podConditionUpdater{f.client},
 		ConfigMapTool:       &configMapTool{f.client},
 		WaitForCacheSync: func() bool {
@@ -539,14 +845,66 @@ func (f *ConfigFactory) CreateFromKeys(predicateKeys, priorityKeys sets.String,
 			f.pushbackSchedulingGroup(group)
 			time.Sleep(2 * time.Second)
 		},
+		RecordSchedulingGroupBindings: func(group *schedulerapi.SchedulingGroup, bound []*v1.Pod) {
+			f.recordSchedulingGroupBindings(group, bound)
+		},
 		ForgetSchedulingGroup: func(group string) {
+			f.groupLock.Lock()
+			defer f.groupLock.Unlock()
 			delete(f.groupMap, group)
 		},
-		//Error:          f.MakeDefaultErrorFunc(podBackoff, f.podQueue),
-		StopEverything: f.StopEverything,
+		DoneSchedulingGroup: func(group *schedulerapi.SchedulingGroup) {
+			f.groupQueue.Done(group)
+		},
+		GroupWorkers: options.groupWorkers,
+		NextPod: func() *v1.Pod {
+			return f.getNextPod()
+		},
+		Binding:             options.binding,
+		StatusUpdateLimiter: flowcontrol.NewTokenBucketRateLimiter(qps, burst),
+		Error:               f.MakeDefaultErrorFunc(podBackoff, f.podQueue),
+		StopEverything:      f.StopEverything,
 	}, nil
 }
 
+// createAlgorithm creates the generic scheduler of the factories of its predicates and
+// priorities, sharing the cache and the equivalence cache of the factory. On the snapshot of a
+// group worker the predicates and priorities are created again from the same factories,
+// listing the pods from the snapshot.
+func (f *ConfigFactory) createAlgorithm(factories *pluginFactories, extenders []algorithm.SchedulerExtender, options configOptions) (algorithm.ScheduleAlgorithm, error) {
+	newPlugins := func(pods algorithm.PodLister) (*core.Plugins, error) {
+		pluginArgs, err := f.getPluginArgs()
+		if err != nil {
+			return nil, err
+		}
+		pluginArgs.PodLister = pods
+		pluginArgs.HardPodAffinitySymmetricWeight = options.hardPodAffinitySymmetricWeight
+
+		priorityConfigs, err := factories.newPriorityConfigs(*pluginArgs)
+		if err != nil {
+			return nil, err
+		}
+
+		return &core.Plugins{
+			Predicates:            factories.newPredicates(*pluginArgs),
+			PredicateMetaProducer: newMetadataProducer(factories.predicateMetadataProducer, *pluginArgs),
+			Prioritizers:          priorityConfigs,
+			PriorityMetaProducer:  newMetadataProducer(factories.priorityMetadataProducer, *pluginArgs),
+		}, nil
+	}
+
+	return core.NewGenericSchedulerFromPlugins(f.schedulerCache, f.equivalencePodCache, newPlugins, extenders, options.nodeSampling, options.predicateEvaluation)
+}
+
+// allNodesLister lists all nodes, schedulable or not.
+type allNodesLister struct {
+	corelisters.NodeLister
+}
+
+func (n *allNodesLister) List() ([]*v1.Node, error) {
+	return n.NodeLister.List(labels.Everything())
+}
+
 type nodePredicateLister struct {
 	corelisters.NodeLister
 }
@@ -603,27 +961,100 @@ func (f *ConfigFactory) getPluginArgs() (*PluginFactoryArgs, error) {
 		PVInfo:     &predicates.CachedPersistentVolumeInfo{PersistentVolumeLister: f.pVLister},
 		PVCInfo:    &predicates.CachedPersistentVolumeClaimInfo{PersistentVolumeClaimLister: f.pVCLister},
 		HardPodAffinitySymmetricWeight: f.hardPodAffinitySymmetricWeight,
+		GPUDemandLister:                f.gpuDemand,
 	}, nil
 }
 
 func (f *ConfigFactory) getNextSchedulingGroup() *schedulerapi.SchedulingGroup {
 	for {
-		group := cache.Pop(f.groupQueue).(*schedulerapi.SchedulingGroup)
+		group := f.copySchedulingGroup(f.groupQueue.Pop())
 		if f.ResponsibleForGroup(group) {
 			glog.V(4).Infof("About to try and schedule group %v", group.Group)
 			return group
 		}
+		f.groupQueue.Done(group)
+	}
+}
+
+func (f *ConfigFactory) getNextPod() *v1.Pod {
+	for {
+		pod := cache.Pop(f.podQueue).(*v1.Pod)
+		if f.ResponsibleForPod(pod) {
+			glog.V(4).Infof("About to try and schedule pod %v/%v", pod.Namespace, pod.Name)
+			return pod
+		}
+	}
+}
+
+// copySchedulingGroup returns a copy of the group for an attempt to schedule it, as the
+// informers keep changing the pending pods of the group while it is scheduled.
+func (f *ConfigFactory) copySchedulingGroup(group *schedulerapi.SchedulingGroup) *schedulerapi.SchedulingGroup {
+	f.groupLock.Lock()
+	defer f.groupLock.Unlock()
+	copied := *group
+	if group.Status != nil {
+		status := *group.Status
+		copied.Status = &status
+	}
+	copied.Resources = make([]*schedulerapi.ResourceObject, 0, len(group.Resources))
+	for _, ro := range group.Resources {
+		role := *ro
+		role.PendingPods = make(map[string]*v1.Pod, len(ro.PendingPods))
+		for name, pod := range ro.PendingPods {
+			role.PendingPods[name] = pod
+		}
+		copied.Resources = append(copied.Resources, &role)
+	}
+	return &copied
+}
+
+// recordSchedulingGroupBindings takes over the bindings an attempt recorded in its copy of the
+// group. The pods bound are no longer pending unless the informers saw them bound already.
+func (f *ConfigFactory) recordSchedulingGroupBindings(group *schedulerapi.SchedulingGroup, bound []*v1.Pod) {
+	f.groupLock.Lock()
+	defer f.groupLock.Unlock()
+	original, ok := f.groupMap[group.Group]
+	if !ok {
+		return
+	}
+	defer f.groupQueue.Reprioritize(original)
+	for _, ro := range original.Resources {
+		for _, attempted := range group.Resources {
+			if attempted.Role == ro.Role {
+				ro.Bound = attempted.Bound
+				ro.BoundPodCount = attempted.BoundPodCount
+			}
+		}
+		for _, pod := range bound {
+			if _, ok := ro.PendingPods[pod.Name]; ok {
+				delete(ro.PendingPods, pod.Name)
+				ro.PendingPodCount--
+			}
+		}
 	}
 }
 
+// pushbackSchedulingGroup queues the group an attempt failed to schedule again. The group kept
+// is queued rather than the copy of the attempt, unless all its members were deleted since.
 func (f *ConfigFactory) pushbackSchedulingGroup(group *schedulerapi.SchedulingGroup) {
-	f.groupQueue.AddIfNotPresent(group)
+	f.groupLock.Lock()
+	defer f.groupLock.Unlock()
+	original, ok := f.groupMap[group.Group]
+	if !ok {
+		return
+	}
+	f.groupQueue.AddIfNotPresent(original)
 }
 
 func (f *ConfigFactory) ResponsibleForGroup(group *schedulerapi.SchedulingGroup) bool {
 	return f.schedulerName == group.SchedulerName || group.SchedulerName == ""
 }
 
+// ResponsibleForPod returns whether the pod is to be scheduled by this scheduler.
+func (f *ConfigFactory) ResponsibleForPod(pod *v1.Pod) bool {
+	return f.schedulerName == pod.Spec.SchedulerName
+}
+
 func getNodeConditionPredicate() corelisters.NodeConditionPredicate {
 	return func(node *v1.Node) bool {
 		for i := range node.Status.Conditions {
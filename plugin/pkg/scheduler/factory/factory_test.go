@@ -37,7 +37,9 @@ import (
 	"k8s.io/kubernetes/plugin/pkg/scheduler/algorithm"
 	schedulerapi "k8s.io/kubernetes/plugin/pkg/scheduler/api"
 	latestschedulerapi "k8s.io/kubernetes/plugin/pkg/scheduler/api/latest"
+	"k8s.io/kubernetes/plugin/pkg/scheduler/core"
 	"k8s.io/kubernetes/plugin/pkg/scheduler/schedulercache"
+	"k8s.io/kubernetes/plugin/pkg/scheduler/tools"
 	"k8s.io/kubernetes/plugin/pkg/scheduler/util"
 )
 
@@ -67,6 +69,47 @@ func TestCreate(t *testing.T) {
 	factory.Create()
 }
 
+// Test the configs created by a factory share its equivalence cache, which the informers
+// invalidate.
+func TestCreateSharesEquivalenceCache(t *testing.T) {
+	handler := utiltesting.FakeHandler{
+		StatusCode:   500,
+		ResponseBody: "",
+		T:            t,
+	}
+	server := httptest.NewServer(&handler)
+	defer server.Close()
+	client := clientset.NewForConfigOrDie(&restclient.Config{Host: server.URL, ContentConfig: restclient.ContentConfig{GroupVersion: &api.Registry.GroupOrDie(v1.GroupName).GroupVersion}})
+	informerFactory := informers.NewSharedInformerFactory(client, 0)
+	previous := getEquivalencePodFunc
+	defer RegisterGetEquivalencePodFunction(previous)
+	RegisterGetEquivalencePodFunction(func(pod *v1.Pod) interface{} { return nil })
+	factory := NewConfigFactory(
+		v1.DefaultSchedulerName,
+		client,
+		informerFactory.Core().V1().Nodes(),
+		informerFactory.Core().V1().Pods(),
+		informerFactory.Core().V1().PersistentVolumes(),
+		informerFactory.Core().V1().PersistentVolumeClaims(),
+		informerFactory.Core().V1().ReplicationControllers(),
+		informerFactory.Extensions().V1beta1().ReplicaSets(),
+		informerFactory.Apps().V1beta1().StatefulSets(),
+		informerFactory.Core().V1().Services(),
+		v1.DefaultHardPodAffinitySymmetricWeight,
+	)
+	first, err := factory.Create()
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	second, err := factory.Create()
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if first.Ecache == nil || first.Ecache != second.Ecache {
+		t.Errorf("Expected the configs to share the equivalence cache of the factory")
+	}
+}
+
 // Test configures a scheduler from a policies defined in a file
 // It combines some configurable predicate/priorities with some pre-defined ones
 func TestCreateFromConfig(t *testing.T) {
@@ -159,6 +202,15 @@ func TestCreateFromConfigWithHardPodAffinitySymmetricWeight(t *testing.T) {
 	RegisterFitPredicate("PredicateTwo", PredicateTwo)
 	RegisterPriorityFunction("PriorityOne", PriorityOne, 1)
 	RegisterPriorityFunction("PriorityTwo", PriorityTwo, 1)
+	// The weight the priorities of the config are created with.
+	var created []int
+	RegisterPriorityConfigFactory("PriorityOfWeight", PriorityConfigFactory{
+		Function: func(args PluginFactoryArgs) algorithm.PriorityFunction {
+			created = append(created, args.HardPodAffinitySymmetricWeight)
+			return PriorityOne
+		},
+		Weight: 1,
+	})
 
 	configData = []byte(`{
 		"kind" : "Policy",
@@ -172,17 +224,121 @@ func TestCreateFromConfigWithHardPodAffinitySymmetricWeight(t *testing.T) {
 		"priorities" : [
 			{"name" : "RackSpread", "weight" : 3, "argument" : {"serviceAntiAffinity" : {"label" : "rack"}}},
 			{"name" : "PriorityOne", "weight" : 2},
-			{"name" : "PriorityTwo", "weight" : 1}
+			{"name" : "PriorityTwo", "weight" : 1},
+			{"name" : "PriorityOfWeight", "weight" : 1}
 		],
 		"hardPodAffinitySymmetricWeight" : 10
 	}`)
 	if err := runtime.DecodeInto(latestschedulerapi.Codec, configData, &policy); err != nil {
 		t.Errorf("Invalid configuration: %v", err)
 	}
-	factory.CreateFromConfig(policy)
+	if _, err := factory.CreateFromConfig(policy); err != nil {
+		t.Fatalf("Failed to create config: %v", err)
+	}
+	if len(created) == 0 || created[len(created)-1] != 10 {
+		t.Errorf("Wrong hardPodAffinitySymmetricWeight of the config, expected: %d, got: %v", 10, created)
+	}
+	// The weight of the policy is not kept in the factory, a config created without the policy
+	// keeps using the weight of the factory.
 	hpa := factory.GetHardPodAffinitySymmetricWeight()
-	if hpa != 10 {
-		t.Errorf("Wrong hardPodAffinitySymmetricWeight, ecpected: %d, got: %d", 10, hpa)
+	if hpa != v1.DefaultHardPodAffinitySymmetricWeight {
+		t.Errorf("Wrong hardPodAffinitySymmetricWeight, ecpected: %d, got: %d", v1.DefaultHardPodAffinitySymmetricWeight, hpa)
+	}
+}
+
+// Test configures a scheduler with profiles weighting the same priority differently
+func TestCreateFromConfigWithProfiles(t *testing.T) {
+	var policy schedulerapi.Policy
+
+	handler := utiltesting.FakeHandler{
+		StatusCode:   500,
+		ResponseBody: "",
+		T:            t,
+	}
+	server := httptest.NewServer(&handler)
+	defer server.Close()
+	client := clientset.NewForConfigOrDie(&restclient.Config{Host: server.URL, ContentConfig: restclient.ContentConfig{GroupVersion: &api.Registry.GroupOrDie(v1.GroupName).GroupVersion}})
+	informerFactory := informers.NewSharedInformerFactory(client, 0)
+	factory := NewConfigFactory(
+		v1.DefaultSchedulerName,
+		client,
+		informerFactory.Core().V1().Nodes(),
+		informerFactory.Core().V1().Pods(),
+		informerFactory.Core().V1().PersistentVolumes(),
+		informerFactory.Core().V1().PersistentVolumeClaims(),
+		informerFactory.Core().V1().ReplicationControllers(),
+		informerFactory.Extensions().V1beta1().ReplicaSets(),
+		informerFactory.Apps().V1beta1().StatefulSets(),
+		informerFactory.Core().V1().Services(),
+		v1.DefaultHardPodAffinitySymmetricWeight,
+	)
+
+	RegisterFitPredicate("PredicateOne", PredicateOne)
+	RegisterPriorityFunction("PriorityOne", PriorityOne, 1)
+	RegisterPriorityFunction("PriorityTwo", PriorityTwo, 1)
+
+	configData := []byte(`{
+		"kind" : "Policy",
+		"apiVersion" : "v1",
+		"predicates" : [{"name" : "PredicateOne"}],
+		"priorities" : [{"name" : "PriorityOne", "weight" : 1}],
+		"profiles" : [
+			{"name" : "spread", "predicates" : [{"name" : "PredicateOne"}], "priorities" : [{"name" : "PriorityOne", "weight" : 5}]},
+			{"name" : "binpack", "priorities" : [{"name" : "PriorityOne", "weight" : 2}, {"name" : "PriorityTwo", "weight" : 3}]}
+		],
+		"namespaceProfiles" : {"training" : "binpack"}
+	}`)
+	if err := runtime.DecodeInto(latestschedulerapi.Codec, configData, &policy); err != nil {
+		t.Errorf("Invalid configuration: %v", err)
+	}
+
+	config, err := factory.CreateFromConfig(policy)
+	if err != nil {
+		t.Fatalf("Failed to create scheduler from configuration: %v", err)
+	}
+	weights := func(algo algorithm.ScheduleAlgorithm) map[string]int {
+		result := map[string]int{}
+		for _, priority := range algo.Prioritizers() {
+			result[priority.Name] = priority.Weight
+		}
+		return result
+	}
+	expected := map[string]map[string]int{
+		"":        {"PriorityOne": 1},
+		"spread":  {"PriorityOne": 5},
+		"binpack": {"PriorityOne": 2, "PriorityTwo": 3},
+	}
+	if len(config.Profiles) != 2 {
+		t.Fatalf("Expected 2 profiles, got %v", config.Profiles)
+	}
+	for name, expectedWeights := range expected {
+		algo := config.Algorithm
+		if name != "" {
+			algo = config.Profiles[name].Algorithm
+		}
+		if got := weights(algo); !reflect.DeepEqual(got, expectedWeights) {
+			t.Errorf("Expected priority weights %v of profile %q, got %v", expectedWeights, name, got)
+		}
+	}
+	if config.NamespaceProfiles["training"] != "binpack" {
+		t.Errorf("Expected the profile of namespace training to be binpack, got %v", config.NamespaceProfiles)
+	}
+	// The priorities of the policy are registered, the ones of the profiles are not.
+	if pcf, _ := getPriorityConfigFactory("PriorityOne"); pcf.Weight != 1 {
+		t.Errorf("Expected the weight of the policy to be registered, got %d", pcf.Weight)
+	}
+	// The priorities created again on a snapshot keep the weights of the profile, whatever is
+	// registered since.
+	RegisterPriorityFunction("PriorityOne", PriorityOne, 7)
+	snapshotAlgorithm := config.Profiles["spread"].Algorithm.(interface {
+		OnCache(schedulercache.Cache, *core.EquivalenceCache) (algorithm.ScheduleAlgorithm, error)
+	})
+	onSnapshot, err := snapshotAlgorithm.OnCache(schedulercache.NewSnapshot(factory.schedulerCache), nil)
+	if err != nil {
+		t.Fatalf("Failed to create the algorithm on a snapshot: %v", err)
+	}
+	if got := weights(onSnapshot); !reflect.DeepEqual(got, expected["spread"]) {
+		t.Errorf("Expected priority weights %v on the snapshot, got %v", expected["spread"], got)
 	}
 }
 
@@ -438,6 +594,115 @@ func TestResponsibleForPod(t *testing.T) {
 	}
 }
 
+// TestSchedulerNameOfGroup tests that a group is scheduled by the scheduler of its members, and
+// taken over by this scheduler if its members disagree on the scheduler.
+func TestSchedulerNameOfGroup(t *testing.T) {
+	handler := utiltesting.FakeHandler{
+		StatusCode:   500,
+		ResponseBody: "",
+		T:            t,
+	}
+	server := httptest.NewServer(&handler)
+	defer server.Close()
+	client := clientset.NewForConfigOrDie(&restclient.Config{Host: server.URL, ContentConfig: restclient.ContentConfig{GroupVersion: &api.Registry.GroupOrDie(v1.GroupName).GroupVersion}})
+	informerFactory := informers.NewSharedInformerFactory(client, 0)
+	factory := NewConfigFactory(
+		"foo-scheduler",
+		client,
+		informerFactory.Core().V1().Nodes(),
+		informerFactory.Core().V1().Pods(),
+		informerFactory.Core().V1().PersistentVolumes(),
+		informerFactory.Core().V1().PersistentVolumeClaims(),
+		informerFactory.Core().V1().ReplicationControllers(),
+		informerFactory.Extensions().V1beta1().ReplicaSets(),
+		informerFactory.Apps().V1beta1().StatefulSets(),
+		informerFactory.Core().V1().Services(),
+		v1.DefaultHardPodAffinitySymmetricWeight,
+	)
+	member := func(name, schedulerName string) *v1.Pod {
+		return &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        name,
+				Namespace:   "bar",
+				Annotations: map[string]string{tools.SchedulingGroup: `{"group": "bar/gang", "role": "worker", "roleCount": 1}`},
+			},
+			Spec: v1.PodSpec{SchedulerName: schedulerName},
+		}
+	}
+
+	_, _, group := factory.GetSchedulingGroup(member("worker-0", "bar-scheduler"), true)
+	if group.SchedulerName != "bar-scheduler" || factory.ResponsibleForGroup(group) {
+		t.Errorf("Expected the group to be scheduled by bar-scheduler, got %q", group.SchedulerName)
+	}
+	_, _, group = factory.GetSchedulingGroup(member("worker-1", "foo-scheduler"), true)
+	if group.SchedulerName != "foo-scheduler" || !factory.ResponsibleForGroup(group) {
+		t.Errorf("Expected the group to be taken over by foo-scheduler, got %q", group.SchedulerName)
+	}
+}
+
+// TestSchedulingGroupCopies tests that a group is scheduled on a copy the informers don't change,
+// and that the bindings of the attempt are taken over by the group.
+func TestSchedulingGroupCopies(t *testing.T) {
+	handler := utiltesting.FakeHandler{
+		StatusCode:   500,
+		ResponseBody: "",
+		T:            t,
+	}
+	server := httptest.NewServer(&handler)
+	defer server.Close()
+	client := clientset.NewForConfigOrDie(&restclient.Config{Host: server.URL, ContentConfig: restclient.ContentConfig{GroupVersion: &api.Registry.GroupOrDie(v1.GroupName).GroupVersion}})
+	informerFactory := informers.NewSharedInformerFactory(client, 0)
+	factory := NewConfigFactory(
+		v1.DefaultSchedulerName,
+		client,
+		informerFactory.Core().V1().Nodes(),
+		informerFactory.Core().V1().Pods(),
+		informerFactory.Core().V1().PersistentVolumes(),
+		informerFactory.Core().V1().PersistentVolumeClaims(),
+		informerFactory.Core().V1().ReplicationControllers(),
+		informerFactory.Extensions().V1beta1().ReplicaSets(),
+		informerFactory.Apps().V1beta1().StatefulSets(),
+		informerFactory.Core().V1().Services(),
+		v1.DefaultHardPodAffinitySymmetricWeight,
+	)
+	add := func(name string) {
+		pod, mini, group := factory.GetSchedulingGroup(&v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        name,
+				Namespace:   "bar",
+				Annotations: map[string]string{tools.SchedulingGroup: `{"group": "bar/gang", "role": "worker", "roleCount": 1}`},
+			},
+			Spec: v1.PodSpec{SchedulerName: v1.DefaultSchedulerName},
+		}, true)
+		factory.AddPodToResourceObject(pod, mini, group)
+	}
+
+	add("worker-0")
+	add("worker-1")
+	attempt := factory.getNextSchedulingGroup()
+	add("worker-2")
+	if role := attempt.Resources[0]; role.PendingPodCount != 2 || len(role.PendingPods) != 2 {
+		t.Errorf("Expected the attempt to see 2 pending pods, got %d", len(role.PendingPods))
+	}
+
+	bound := attempt.Resources[0].PendingPods["worker-0"]
+	delete(attempt.Resources[0].PendingPods, "worker-0")
+	attempt.Resources[0].PendingPodCount--
+	attempt.Resources[0].BoundPodCount++
+	factory.recordSchedulingGroupBindings(attempt, []*v1.Pod{bound})
+	factory.pushbackSchedulingGroup(attempt)
+	factory.groupQueue.Done(attempt)
+
+	next := factory.getNextSchedulingGroup()
+	if next == attempt {
+		t.Fatalf("Expected a new copy of the group to be scheduled")
+	}
+	role := next.Resources[0]
+	if _, ok := role.PendingPods["worker-0"]; ok || role.PendingPodCount != 2 || role.BoundPodCount != 1 {
+		t.Errorf("Expected worker-1 and worker-2 pending and 1 pod bound, got %d pending and %d bound", role.PendingPodCount, role.BoundPodCount)
+	}
+}
+
 func TestInvalidHardPodAffinitySymmetricWeight(t *testing.T) {
 	handler := utiltesting.FakeHandler{
 		StatusCode:   500,
@@ -467,6 +732,18 @@ func TestInvalidHardPodAffinitySymmetricWeight(t *testing.T) {
 	if err == nil {
 		t.Errorf("expected err: invalid hardPodAffinitySymmetricWeight, got nothing")
 	}
+
+	// A policy which fails to be created doesn't register its predicates.
+	policy := schedulerapi.Policy{Predicates: []schedulerapi.PredicatePolicy{{
+		Name:     "PredicateOfRejectedPolicy",
+		Argument: &schedulerapi.PredicateArgument{LabelsPresence: &schedulerapi.LabelsPresence{Labels: []string{"zone"}, Presence: true}},
+	}}}
+	if _, err := factory.CreateFromConfig(policy); err == nil {
+		t.Errorf("expected err: invalid hardPodAffinitySymmetricWeight, got nothing")
+	}
+	if IsFitPredicateRegistered("PredicateOfRejectedPolicy") {
+		t.Errorf("expected the predicate of the rejected policy not to be registered")
+	}
 }
 
 func TestInvalidFactoryArgs(t *testing.T) {
@@ -0,0 +1,75 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package factory
+
+import (
+	"sync"
+
+	"k8s.io/kubernetes/pkg/api/v1"
+	"k8s.io/kubernetes/plugin/pkg/scheduler/algorithm"
+	"k8s.io/kubernetes/plugin/pkg/scheduler/algorithm/predicates"
+	"k8s.io/kubernetes/plugin/pkg/scheduler/tools"
+)
+
+var _ algorithm.GPUDemandLister = &pendingGPUDemand{}
+
+// pendingGPUDemand tracks the GPU requests of the pending members of scheduling groups.
+// It is fed by the unscheduled pod informer and read by the priority functions, so it
+// is safe for concurrent use.
+type pendingGPUDemand struct {
+	lock     sync.RWMutex
+	requests map[string]int64
+}
+
+func newPendingGPUDemand() *pendingGPUDemand {
+	return &pendingGPUDemand{requests: map[string]int64{}}
+}
+
+func (d *pendingGPUDemand) update(pod *v1.Pod) {
+	if _, ok := pod.Annotations[tools.SchedulingGroup]; !ok {
+		return
+	}
+	request := predicates.GetResourceRequest(pod).NvidiaGPU
+	key := pod.Namespace + "/" + pod.Name
+
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	if request > 0 {
+		d.requests[key] = request
+	} else {
+		delete(d.requests, key)
+	}
+}
+
+func (d *pendingGPUDemand) remove(pod *v1.Pod) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	delete(d.requests, pod.Namespace+"/"+pod.Name)
+}
+
+// MaxPendingGPURequest implements algorithm.GPUDemandLister.
+func (d *pendingGPUDemand) MaxPendingGPURequest() int64 {
+	d.lock.RLock()
+	defer d.lock.RUnlock()
+	var result int64
+	for _, request := range d.requests {
+		if request > result {
+			result = request
+		}
+	}
+	return result
+}
@@ -0,0 +1,143 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package factory
+
+import (
+	"sync"
+
+	schedulerapi "k8s.io/kubernetes/plugin/pkg/scheduler/api"
+	"k8s.io/kubernetes/plugin/pkg/scheduler/tools"
+)
+
+// groupQueue holds the groups waiting to be scheduled. Pop returns the group of the highest
+// priority, and the group queued first among groups of the same priority. The priority of a
+// group is evaluated when it is queued, and again by Reprioritize when its members change.
+// The pods of the group must not change meanwhile; the callers hold the lock guarding them.
+// A group popped is not popped again until Done is called for it, so that workers scheduling
+// groups concurrently never schedule the same group at once. It may be queued meanwhile.
+type groupQueue struct {
+	lock  sync.Mutex
+	cond  sync.Cond
+	items map[string]*queuedGroup
+	// processing are the keys of the groups popped and not done yet.
+	processing map[string]bool
+	// seq orders the groups of the same priority by the time they were queued.
+	seq uint64
+}
+
+type queuedGroup struct {
+	group    *schedulerapi.SchedulingGroup
+	priority int32
+	seq      uint64
+}
+
+func newGroupQueue() *groupQueue {
+	q := &groupQueue{items: map[string]*queuedGroup{}, processing: map[string]bool{}}
+	q.cond.L = &q.lock
+	return q
+}
+
+// Add queues the group, or replaces the queued group of the same key keeping its place.
+func (q *groupQueue) Add(group *schedulerapi.SchedulingGroup) error {
+	key, err := tools.KeyFunc(group)
+	if err != nil {
+		return err
+	}
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	if item, ok := q.items[key]; ok {
+		item.group = group
+		item.priority = tools.GetGroupPriority(group)
+		return nil
+	}
+	q.add(key, group)
+	return nil
+}
+
+// AddIfNotPresent queues the group unless a group of the same key is queued already.
+func (q *groupQueue) AddIfNotPresent(group *schedulerapi.SchedulingGroup) error {
+	key, err := tools.KeyFunc(group)
+	if err != nil {
+		return err
+	}
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	if _, ok := q.items[key]; !ok {
+		q.add(key, group)
+	}
+	return nil
+}
+
+// Reprioritize evaluates the priority of the group again if it is queued.
+func (q *groupQueue) Reprioritize(group *schedulerapi.SchedulingGroup) error {
+	key, err := tools.KeyFunc(group)
+	if err != nil {
+		return err
+	}
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	if item, ok := q.items[key]; ok {
+		item.priority = tools.GetGroupPriority(group)
+	}
+	return nil
+}
+
+func (q *groupQueue) add(key string, group *schedulerapi.SchedulingGroup) {
+	q.seq++
+	q.items[key] = &queuedGroup{group: group, priority: tools.GetGroupPriority(group), seq: q.seq}
+	q.cond.Broadcast()
+}
+
+// Pop blocks until a group which is not processed is queued, and removes and returns the
+// group to schedule next.
+func (q *groupQueue) Pop() *schedulerapi.SchedulingGroup {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	for {
+		var nextKey string
+		var next *queuedGroup
+		for key, item := range q.items {
+			if q.processing[key] {
+				continue
+			}
+			if next == nil || item.priority > next.priority || (item.priority == next.priority && item.seq < next.seq) {
+				nextKey, next = key, item
+			}
+		}
+		if next != nil {
+			delete(q.items, nextKey)
+			q.processing[nextKey] = true
+			return next.group
+		}
+		q.cond.Wait()
+	}
+}
+
+// Done marks the group popped as processed, it can be popped again if it was queued meanwhile.
+func (q *groupQueue) Done(group *schedulerapi.SchedulingGroup) error {
+	key, err := tools.KeyFunc(group)
+	if err != nil {
+		return err
+	}
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	delete(q.processing, key)
+	if _, ok := q.items[key]; ok {
+		q.cond.Broadcast()
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package factory
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/kubernetes/pkg/api/v1"
+	schedulerapi "k8s.io/kubernetes/plugin/pkg/scheduler/api"
+	"k8s.io/kubernetes/plugin/pkg/scheduler/tools"
+)
+
+func groupWithPriority(name, priority string) *schedulerapi.SchedulingGroup {
+	pod := &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name + "-0", Namespace: "foo"}}
+	if priority != "" {
+		pod.Annotations = map[string]string{tools.PodPriority: priority}
+	}
+	return &schedulerapi.SchedulingGroup{
+		Group:     "foo/" + name,
+		Resources: []*schedulerapi.ResourceObject{{Role: "worker", PendingPods: map[string]*v1.Pod{pod.Name: pod}}},
+	}
+}
+
+func TestGroupQueue(t *testing.T) {
+	q := newGroupQueue()
+	q.Add(groupWithPriority("batch", ""))
+	q.Add(groupWithPriority("training", "100"))
+	q.Add(groupWithPriority("inference", "1000"))
+	q.Add(groupWithPriority("backfill", "-10"))
+	q.Add(groupWithPriority("tuning", "100"))
+	// Replacing a queued group keeps its place.
+	q.Add(groupWithPriority("training", "100"))
+	q.AddIfNotPresent(groupWithPriority("inference", ""))
+
+	var popped []string
+	for i := 0; i < 5; i++ {
+		popped = append(popped, q.Pop().Group)
+	}
+	expected := []string{"foo/inference", "foo/training", "foo/tuning", "foo/batch", "foo/backfill"}
+	if !reflect.DeepEqual(popped, expected) {
+		t.Errorf("Expected groups popped in order %v, got %v", expected, popped)
+	}
+
+	done := make(chan *schedulerapi.SchedulingGroup)
+	go func() { done <- q.Pop() }()
+	// The group popped before is queued again while it is processed, it is popped once done.
+	q.AddIfNotPresent(groupWithPriority("batch", ""))
+	select {
+	case group := <-done:
+		t.Errorf("Expected no group to be popped while foo/batch is processed, got %s", group.Group)
+	case <-time.After(100 * time.Millisecond):
+	}
+	q.Done(groupWithPriority("batch", ""))
+	select {
+	case group := <-done:
+		if group.Group != "foo/batch" {
+			t.Errorf("Expected group foo/batch, got %s", group.Group)
+		}
+	case <-time.After(wait.ForeverTestTimeout):
+		t.Errorf("Timed out waiting for the group to be popped")
+	}
+}
+
+func TestGroupQueueReprioritize(t *testing.T) {
+	q := newGroupQueue()
+	batch := groupWithPriority("batch", "")
+	q.Add(batch)
+	q.Add(groupWithPriority("training", "100"))
+
+	// A member of a higher priority joins the queued group.
+	pod := &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "batch-1", Namespace: "foo", Annotations: map[string]string{tools.PodPriority: "1000"}}}
+	batch.Resources[0].PendingPods[pod.Name] = pod
+	if group := q.Pop(); group.Group != "foo/training" {
+		t.Errorf("Expected the priority to be kept until the group is reprioritized, got %s", group.Group)
+	}
+	q.Add(groupWithPriority("training", "100"))
+	q.Done(groupWithPriority("training", "100"))
+	q.Reprioritize(batch)
+	if group := q.Pop(); group.Group != "foo/batch" {
+		t.Errorf("Expected group foo/batch, got %s", group.Group)
+	}
+}
@@ -43,6 +43,7 @@ type PluginFactoryArgs struct {
 	PVInfo                         predicates.PersistentVolumeInfo
 	PVCInfo                        predicates.PersistentVolumeClaimInfo
 	HardPodAffinitySymmetricWeight int
+	GPUDemandLister                algorithm.GPUDemandLister
 }
 
 // MetadataProducerFactory produces MetadataProducer from the given args.
@@ -112,8 +113,14 @@ func RegisterFitPredicateFactory(name string, predicateFactory FitPredicateFacto
 // RegisterCustomFitPredicate registers a custom fit predicate with the algorithm registry.
 // Returns the name, with which the predicate was registered.
 func RegisterCustomFitPredicate(policy schedulerapi.PredicatePolicy) string {
+	return RegisterFitPredicateFactory(policy.Name, newCustomFitPredicateFactory(policy))
+}
+
+// newCustomFitPredicateFactory returns the factory of a custom fit predicate without
+// registering it: the predicate of the argument of the policy, else the predicate registered
+// under its name.
+func newCustomFitPredicateFactory(policy schedulerapi.PredicatePolicy) FitPredicateFactory {
 	var predicateFactory FitPredicateFactory
-	var ok bool
 
 	validatePredicateOrDie(policy)
 
@@ -140,17 +147,25 @@ func RegisterCustomFitPredicate(policy schedulerapi.PredicatePolicy) string {
 				)
 			}
 		}
-	} else if predicateFactory, ok = fitPredicateMap[policy.Name]; ok {
+	} else if existingFactory, ok := getFitPredicateFactory(policy.Name); ok {
 		// checking to see if a pre-defined predicate is requested
 		glog.V(2).Infof("Predicate type %s already registered, reusing.", policy.Name)
-		return policy.Name
+		predicateFactory = existingFactory
 	}
 
 	if predicateFactory == nil {
 		glog.Fatalf("Invalid configuration: Predicate type not found for %s", policy.Name)
 	}
 
-	return RegisterFitPredicateFactory(policy.Name, predicateFactory)
+	return predicateFactory
+}
+
+// getFitPredicateFactory returns the fit predicate factory registered under the name.
+func getFitPredicateFactory(name string) (FitPredicateFactory, bool) {
+	schedulerFactoryMutex.Lock()
+	defer schedulerFactoryMutex.Unlock()
+	factory, ok := fitPredicateMap[name]
+	return factory, ok
 }
 
 // IsFitPredicateRegistered is useful for testing providers.
@@ -213,6 +228,13 @@ func RegisterPriorityConfigFactory(name string, pcf PriorityConfigFactory) strin
 // RegisterCustomPriorityFunction registers a custom priority function with the algorithm registry.
 // Returns the name, with which the priority function was registered.
 func RegisterCustomPriorityFunction(policy schedulerapi.PriorityPolicy) string {
+	return RegisterPriorityConfigFactory(policy.Name, newCustomPriorityConfigFactory(policy))
+}
+
+// newCustomPriorityConfigFactory returns the factory of a custom priority without registering
+// it: the priority of the argument of the policy, else the priority registered under its name
+// with the weight of the policy.
+func newCustomPriorityConfigFactory(policy schedulerapi.PriorityPolicy) PriorityConfigFactory {
 	var pcf *PriorityConfigFactory
 
 	validatePriorityOrDie(policy)
@@ -240,8 +262,19 @@ func RegisterCustomPriorityFunction(policy schedulerapi.PriorityPolicy) string {
 				},
 				Weight: policy.Weight,
 			}
+		} else if policy.Argument.NetworkTopology != nil {
+			pcf = &PriorityConfigFactory{
+				MapReduceFunction: func(args PluginFactoryArgs) (algorithm.PriorityMapFunction, algorithm.PriorityReduceFunction) {
+					return priorities.NewNetworkTopologyPriority(
+						args.PodLister,
+						args.NodeLister,
+						policy.Argument.NetworkTopology.Levels,
+					)
+				},
+				Weight: policy.Weight,
+			}
 		}
-	} else if existingPcf, ok := priorityFunctionMap[policy.Name]; ok {
+	} else if existingPcf, ok := getPriorityConfigFactory(policy.Name); ok {
 		glog.V(2).Infof("Priority type %s already registered, reusing.", policy.Name)
 		// set/update the weight based on the policy
 		pcf = &PriorityConfigFactory{
@@ -255,7 +288,15 @@ func RegisterCustomPriorityFunction(policy schedulerapi.PriorityPolicy) string {
 		glog.Fatalf("Invalid configuration: Priority type not found for %s", policy.Name)
 	}
 
-	return RegisterPriorityConfigFactory(policy.Name, *pcf)
+	return *pcf
+}
+
+// getPriorityConfigFactory returns the priority config factory registered under the name.
+func getPriorityConfigFactory(name string) (PriorityConfigFactory, bool) {
+	schedulerFactoryMutex.Lock()
+	defer schedulerFactoryMutex.Unlock()
+	pcf, ok := priorityFunctionMap[name]
+	return pcf, ok
 }
 
 func RegisterGetEquivalencePodFunction(equivalenceFunc algorithm.GetEquivalencePodFunc) {
@@ -297,58 +338,130 @@ func GetAlgorithmProvider(name string) (*AlgorithmProviderConfig, error) {
 }
 
 func getFitPredicateFunctions(names sets.String, args PluginFactoryArgs) (map[string]algorithm.FitPredicate, error) {
-	schedulerFactoryMutex.Lock()
-	defer schedulerFactoryMutex.Unlock()
-
-	predicates := map[string]algorithm.FitPredicate{}
-	for _, name := range names.List() {
-		factory, ok := fitPredicateMap[name]
-		if !ok {
-			return nil, fmt.Errorf("Invalid predicate name %q specified - no corresponding function found", name)
-		}
-		predicates[name] = factory(args)
+	factories, err := getPluginFactories(names, sets.NewString())
+	if err != nil {
+		return nil, err
 	}
-	return predicates, nil
+	return factories.newPredicates(args), nil
 }
 
 func getPriorityMetadataProducer(args PluginFactoryArgs) (algorithm.MetadataProducer, error) {
-	schedulerFactoryMutex.Lock()
-	defer schedulerFactoryMutex.Unlock()
-
-	if priorityMetadataProducer == nil {
-		return algorithm.EmptyMetadataProducer, nil
+	factories, err := getPluginFactories(sets.NewString(), sets.NewString())
+	if err != nil {
+		return nil, err
 	}
-	return priorityMetadataProducer(args), nil
+	return newMetadataProducer(factories.priorityMetadataProducer, args), nil
 }
 
 func getPredicateMetadataProducer(args PluginFactoryArgs) (algorithm.MetadataProducer, error) {
-	schedulerFactoryMutex.Lock()
-	defer schedulerFactoryMutex.Unlock()
-
-	if predicateMetadataProducer == nil {
-		return algorithm.EmptyMetadataProducer, nil
+	factories, err := getPluginFactories(sets.NewString(), sets.NewString())
+	if err != nil {
+		return nil, err
 	}
-	return predicateMetadataProducer(args), nil
+	return newMetadataProducer(factories.predicateMetadataProducer, args), nil
 }
 
 func getPriorityFunctionConfigs(names sets.String, args PluginFactoryArgs) ([]algorithm.PriorityConfig, error) {
+	factories, err := getPluginFactories(sets.NewString(), names)
+	if err != nil {
+		return nil, err
+	}
+	return factories.newPriorityConfigs(args)
+}
+
+// pluginFactories are the factories of the predicates and priorities of a scheduler and of
+// their metadata producers. They are resolved once, so that the plugins created again for
+// the snapshot of a group worker don't change with what is registered meanwhile.
+type pluginFactories struct {
+	predicates                map[string]FitPredicateFactory
+	priorities                map[string]PriorityConfigFactory
+	predicateMetadataProducer MetadataProducerFactory
+	priorityMetadataProducer  MetadataProducerFactory
+}
+
+// getPluginFactories resolves the factories registered under the predicate and priority names.
+func getPluginFactories(predicateNames, priorityNames sets.String) (*pluginFactories, error) {
 	schedulerFactoryMutex.Lock()
 	defer schedulerFactoryMutex.Unlock()
 
-	configs := []algorithm.PriorityConfig{}
-	for _, name := range names.List() {
+	factories := &pluginFactories{
+		predicates:                make(map[string]FitPredicateFactory, len(predicateNames)),
+		priorities:                make(map[string]PriorityConfigFactory, len(priorityNames)),
+		predicateMetadataProducer: predicateMetadataProducer,
+		priorityMetadataProducer:  priorityMetadataProducer,
+	}
+	for _, name := range predicateNames.List() {
+		factory, ok := fitPredicateMap[name]
+		if !ok {
+			return nil, fmt.Errorf("Invalid predicate name %q specified - no corresponding function found", name)
+		}
+		factories.predicates[name] = factory
+	}
+	for _, name := range priorityNames.List() {
 		factory, ok := priorityFunctionMap[name]
 		if !ok {
 			return nil, fmt.Errorf("Invalid priority name %s specified - no corresponding function found", name)
 		}
+		factories.priorities[name] = factory
+	}
+	return factories, nil
+}
+
+// getCustomPluginFactories resolves the factories of the custom predicates and priorities of
+// a policy without registering them, see register.
+func getCustomPluginFactories(predicatePolicies []schedulerapi.PredicatePolicy, priorityPolicies []schedulerapi.PriorityPolicy) *pluginFactories {
+	factories, _ := getPluginFactories(sets.NewString(), sets.NewString())
+	for _, policy := range predicatePolicies {
+		glog.V(2).Infof("Resolving predicate: %s", policy.Name)
+		factories.predicates[policy.Name] = newCustomFitPredicateFactory(policy)
+	}
+	for _, policy := range priorityPolicies {
+		glog.V(2).Infof("Resolving priority: %s", policy.Name)
+		factories.priorities[policy.Name] = newCustomPriorityConfigFactory(policy)
+	}
+	return factories
+}
+
+// register registers the predicates and priorities with the algorithm registry.
+func (p *pluginFactories) register() {
+	for name, factory := range p.predicates {
+		RegisterFitPredicateFactory(name, factory)
+	}
+	for name, factory := range p.priorities {
+		RegisterPriorityConfigFactory(name, factory)
+	}
+}
+
+// newPredicates creates the predicates with the args.
+func (p *pluginFactories) newPredicates(args PluginFactoryArgs) map[string]algorithm.FitPredicate {
+	predicates := make(map[string]algorithm.FitPredicate, len(p.predicates))
+	for name, factory := range p.predicates {
+		predicates[name] = factory(args)
+	}
+	return predicates
+}
+
+// newPriorityConfigs creates the priorities with the args, ordered by name.
+func (p *pluginFactories) newPriorityConfigs(args PluginFactoryArgs) ([]algorithm.PriorityConfig, error) {
+	names := make([]string, 0, len(p.priorities))
+	for name := range p.priorities {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	configs := []algorithm.PriorityConfig{}
+	for _, name := range names {
+		factory := p.priorities[name]
 		if factory.Function != nil {
 			configs = append(configs, algorithm.PriorityConfig{
+				Name:     name,
 				Function: factory.Function(args),
 				Weight:   factory.Weight,
 			})
 		} else {
 			mapFunction, reduceFunction := factory.MapReduceFunction(args)
 			configs = append(configs, algorithm.PriorityConfig{
+				Name:   name,
 				Map:    mapFunction,
 				Reduce: reduceFunction,
 				Weight: factory.Weight,
@@ -361,7 +474,15 @@ func getPriorityFunctionConfigs(names sets.String, args PluginFactoryArgs) ([]al
 	return configs, nil
 }
 
-// validateSelectedConfigs validates the config weights to avoid the overflow.
+// newMetadataProducer creates the metadata producer with the args, an empty one if there is
+// no factory.
+func newMetadataProducer(factory MetadataProducerFactory, args PluginFactoryArgs) algorithm.MetadataProducer {
+	if factory == nil {
+		return algorithm.EmptyMetadataProducer
+	}
+	return factory(args)
+}
+
 func validateSelectedConfigs(configs []algorithm.PriorityConfig) error {
 	var totalPriority int
 	for _, config := range configs {
@@ -406,6 +527,9 @@ func validatePriorityOrDie(priority schedulerapi.PriorityPolicy) {
 		if priority.Argument.LabelPreference != nil {
 			numArgs++
 		}
+		if priority.Argument.NetworkTopology != nil {
+			numArgs++
+		}
 		if numArgs != 1 {
 			glog.Fatalf("Exactly 1 priority argument is required, numArgs: %v, Priority: %s", numArgs, priority.Name)
 		}
@@ -17,8 +17,11 @@ limitations under the License.
 package factory
 
 import (
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/kubernetes/pkg/api/v1"
 	"k8s.io/kubernetes/plugin/pkg/scheduler/algorithm"
 	"k8s.io/kubernetes/plugin/pkg/scheduler/api"
+	"k8s.io/kubernetes/plugin/pkg/scheduler/schedulercache"
 	"testing"
 )
 
@@ -79,3 +82,16 @@ func TestValidatePriorityConfigOverFlow(t *testing.T) {
 		}
 	}
 }
+
+func TestPriorityConfigsAreNamed(t *testing.T) {
+	RegisterPriorityFunction2("TestNamedPriority", func(pod *v1.Pod, meta interface{}, nodeInfo *schedulercache.NodeInfo) (api.HostPriority, error) {
+		return api.HostPriority{}, nil
+	}, nil, 1)
+	configs, err := getPriorityFunctionConfigs(sets.NewString("TestNamedPriority"), PluginFactoryArgs{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(configs) != 1 || configs[0].Name != "TestNamedPriority" {
+		t.Errorf("expected a priority config named TestNamedPriority, got %+v", configs)
+	}
+}
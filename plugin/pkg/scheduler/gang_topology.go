@@ -0,0 +1,227 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/golang/glog"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/kubernetes/pkg/api/v1"
+	"k8s.io/kubernetes/plugin/pkg/scheduler/algorithm"
+	"k8s.io/kubernetes/plugin/pkg/scheduler/algorithm/predicates"
+	schedulerapi "k8s.io/kubernetes/plugin/pkg/scheduler/api"
+	"k8s.io/kubernetes/plugin/pkg/scheduler/schedulercache"
+	"k8s.io/kubernetes/plugin/pkg/scheduler/tools"
+)
+
+// domainNodeLister lists the nodes of the wrapped lister whose value of the topology key
+// is one of the given domains.
+type domainNodeLister struct {
+	algorithm.NodeLister
+	key     string
+	domains sets.String
+}
+
+func (l *domainNodeLister) List() ([]*v1.Node, error) {
+	nodes, err := l.NodeLister.List()
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*v1.Node, 0, len(nodes))
+	for _, node := range nodes {
+		if value, ok := node.Labels[l.key]; ok && l.domains.Has(value) {
+			result = append(result, node)
+		}
+	}
+	return result, nil
+}
+
+// topologyDomain is the free capacity of one value of the topology key.
+type topologyDomain struct {
+	name    string
+	free    schedulercache.Resource
+	members int
+}
+
+// fits checks if the domain has room for the request in total. Individual nodes may still
+// be too small, which is found out when the members are placed.
+func (d *topologyDomain) fits(request *schedulercache.Resource) bool {
+	return d.free.MilliCPU >= request.MilliCPU &&
+		d.free.Memory >= request.Memory &&
+		d.free.NvidiaGPU >= request.NvidiaGPU
+}
+
+// orderTopologyDomains returns the domains of the topology key to try for the group. Domains
+// which already host members of the group come first, then the domains are ordered by
+// ascending free capacity so that the gang fills the smallest domain it fits in. Domains
+// too small for the Min members of the roles are left out unless all is true.
+func (sched *Scheduler) orderTopologyDomains(group *schedulerapi.SchedulingGroup, roles []*schedulerapi.ResourceObject, key string, all bool) ([]string, error) {
+	nodes, err := sched.config.NodeLister.List()
+	if err != nil {
+		return nil, err
+	}
+	nodeNameToInfo := map[string]*schedulercache.NodeInfo{}
+	if err := sched.config.SchedulerCache.UpdateNodeNameToInfoMap(nodeNameToInfo); err != nil {
+		return nil, err
+	}
+
+	domains := map[string]*topologyDomain{}
+	for _, node := range nodes {
+		value, ok := node.Labels[key]
+		if !ok {
+			continue
+		}
+		domain, ok := domains[value]
+		if !ok {
+			domain = &topologyDomain{name: value}
+			domains[value] = domain
+		}
+		info, ok := nodeNameToInfo[node.Name]
+		if !ok {
+			continue
+		}
+		allocatable := info.AllocatableResource()
+		requested := info.RequestedResource()
+		domain.free.MilliCPU += allocatable.MilliCPU - requested.MilliCPU
+		domain.free.Memory += allocatable.Memory - requested.Memory
+		domain.free.NvidiaGPU += allocatable.NvidiaGPU - requested.NvidiaGPU
+	}
+	for _, member := range tools.GetGroupMembers(group.Group, nodeNameToInfo) {
+		if domain, ok := domains[member.Node.Labels[key]]; ok {
+			domain.members++
+		}
+	}
+
+	request := &schedulercache.Resource{}
+	for _, rb := range roles {
+		count := 0
+		for _, pod := range rb.PendingPods {
+			if count == rb.Min {
+				break
+			}
+			count++
+			podRequest := predicates.GetResourceRequest(pod)
+			request.MilliCPU += podRequest.MilliCPU
+			request.Memory += podRequest.Memory
+			request.NvidiaGPU += podRequest.NvidiaGPU
+		}
+	}
+
+	candidates := make([]*topologyDomain, 0, len(domains))
+	for _, domain := range domains {
+		if all || domain.fits(request) {
+			candidates = append(candidates, domain)
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if a.members != b.members {
+			return a.members > b.members
+		}
+		if a.free.NvidiaGPU != b.free.NvidiaGPU {
+			return a.free.NvidiaGPU < b.free.NvidiaGPU
+		}
+		if a.free.MilliCPU != b.free.MilliCPU {
+			return a.free.MilliCPU < b.free.MilliCPU
+		}
+		if a.free.Memory != b.free.Memory {
+			return a.free.Memory < b.free.Memory
+		}
+		return a.name < b.name
+	})
+
+	result := make([]string, 0, len(candidates))
+	for _, domain := range candidates {
+		result = append(result, domain.name)
+	}
+	return result, nil
+}
+
+// tentatively returns a scheduler placing pods like sched without recording misses on them.
+func (sched *Scheduler) tentatively() *Scheduler {
+	return &Scheduler{config: sched.config, snapshot: sched.snapshot, snapshotEcache: sched.snapshotEcache, tentative: true}
+}
+
+// placeGroup places the roles of the group. Without a topology the whole cluster is used.
+// In Hard mode all members are kept in one domain of the topology key. In Soft mode one
+// domain is tried first and more domains are added one at a time when members don't fit.
+// Pods which don't fit are only marked unschedulable once no other domain is left to try.
+func (sched *Scheduler) placeGroup(group *schedulerapi.SchedulingGroup, roles []*schedulerapi.ResourceObject) error {
+	topology := group.Topology
+	if topology == nil || len(topology.Key) == 0 {
+		return sched.placeRoles(group, roles, sched.config.NodeLister, nil)
+	}
+
+	domains, err := sched.orderTopologyDomains(group, roles, topology.Key, false)
+	if err != nil {
+		return err
+	}
+	for i, domain := range domains {
+		nodeLister := &domainNodeLister{
+			NodeLister: sched.config.NodeLister,
+			key:        topology.Key,
+			domains:    sets.NewString(domain),
+		}
+		placement := sched
+		if i < len(domains)-1 || topology.Mode == schedulerapi.TopologySoft {
+			placement = sched.tentatively()
+		}
+		err := placement.placeRoles(group, roles, nodeLister, nil)
+		if err == nil {
+			glog.V(3).Infof("Placed group %s in %s=%s", group.Group, topology.Key, domain)
+			return nil
+		}
+		glog.V(4).Infof("Group %s doesn't fit in %s=%s: %v", group.Group, topology.Key, domain, err)
+		sched.releaseResources(group)
+	}
+
+	if topology.Mode != schedulerapi.TopologySoft {
+		return fmt.Errorf("no %s domain can hold all members of group %s", topology.Key, group.Group)
+	}
+
+	// Spread over as few domains as possible, adding the next one whenever a member doesn't fit.
+	domains, err = sched.orderTopologyDomains(group, roles, topology.Key, true)
+	if err != nil {
+		return err
+	}
+	if len(domains) == 0 {
+		return sched.placeRoles(group, roles, sched.config.NodeLister, nil)
+	}
+	nodeLister := &domainNodeLister{
+		NodeLister: sched.config.NodeLister,
+		key:        topology.Key,
+		domains:    sets.NewString(domains[0]),
+	}
+	placement := sched
+	if len(domains) > 1 {
+		placement = sched.tentatively()
+	}
+	next := 1
+	expand := func() bool {
+		if next == len(domains) {
+			return false
+		}
+		nodeLister.domains.Insert(domains[next])
+		next++
+		// With the last domain added, a pod which doesn't fit can't be placed at all.
+		placement.tentative = next < len(domains)
+		return true
+	}
+	return placement.placeRoles(group, roles, nodeLister, expand)
+}
@@ -0,0 +1,193 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kubernetes/pkg/api/v1"
+	"k8s.io/kubernetes/plugin/pkg/scheduler/algorithm"
+	"k8s.io/kubernetes/plugin/pkg/scheduler/algorithm/predicates"
+	schedulerapi "k8s.io/kubernetes/plugin/pkg/scheduler/api"
+	"k8s.io/kubernetes/plugin/pkg/scheduler/core"
+	"k8s.io/kubernetes/plugin/pkg/scheduler/schedulercache"
+	schedulertesting "k8s.io/kubernetes/plugin/pkg/scheduler/testing"
+	"k8s.io/kubernetes/plugin/pkg/scheduler/tools"
+)
+
+func TestPlaceGroupInTopology(t *testing.T) {
+	zoneNode := func(name, zone string, milliCPU int64) *v1.Node {
+		return &v1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{"zone": zone}},
+			Status: v1.NodeStatus{
+				Capacity: v1.ResourceList{
+					v1.ResourceCPU:  *resource.NewMilliQuantity(milliCPU, resource.DecimalSI),
+					v1.ResourcePods: *resource.NewQuantity(10, resource.DecimalSI),
+				},
+				Allocatable: v1.ResourceList{
+					v1.ResourceCPU:  *resource.NewMilliQuantity(milliCPU, resource.DecimalSI),
+					v1.ResourcePods: *resource.NewQuantity(10, resource.DecimalSI),
+				},
+			},
+		}
+	}
+	member := func(name, node string, milliCPU int64) *v1.Pod {
+		requests := v1.ResourceList{v1.ResourceCPU: *resource.NewMilliQuantity(milliCPU, resource.DecimalSI)}
+		pod := podWithResources(name, node, requests, requests)
+		data, _ := json.Marshal(schedulerapi.MiniGroup{Group: "foo/gang", Role: "worker"})
+		pod.Annotations = map[string]string{tools.SchedulingGroup: string(data)}
+		return pod
+	}
+	threeZones := []*v1.Node{
+		zoneNode("machine-a1", "a", 2000),
+		zoneNode("machine-b1", "b", 2000),
+		zoneNode("machine-b2", "b", 2000),
+		zoneNode("machine-c1", "c", 6000),
+	}
+
+	tests := []struct {
+		name       string
+		nodes      []*v1.Node
+		placed     []*v1.Pod
+		mode       schedulerapi.TopologyMode
+		count      int
+		milliCPU   int64
+		domains    []string
+		expected   []string
+		expectErr  bool
+		conditions int
+	}{
+		{
+			name:     "smallest domain that fits",
+			nodes:    threeZones,
+			count:    2,
+			milliCPU: 1000,
+			domains:  []string{"a", "b", "c"},
+			expected: []string{"machine-a1", "machine-a1"},
+		},
+		{
+			name:     "domain holding members first",
+			nodes:    threeZones,
+			placed:   []*v1.Pod{member("worker-x", "machine-c1", 1000)},
+			count:    2,
+			milliCPU: 1000,
+			domains:  []string{"c", "a", "b"},
+			expected: []string{"machine-c1", "machine-c1"},
+		},
+		{
+			name:     "next domain when the nodes of a domain are too small",
+			nodes:    threeZones,
+			count:    3,
+			milliCPU: 1300,
+			domains:  []string{"b", "c"},
+			expected: []string{"machine-c1", "machine-c1", "machine-c1"},
+		},
+		{
+			name:       "hard fails when no domain holds the group",
+			nodes:      threeZones[:3],
+			count:      3,
+			milliCPU:   1300,
+			domains:    []string{"b"},
+			expectErr:  true,
+			conditions: 1,
+		},
+		{
+			name:     "soft expands to the next domain",
+			nodes:    threeZones[:3],
+			mode:     schedulerapi.TopologySoft,
+			count:    3,
+			milliCPU: 1300,
+			domains:  []string{"b"},
+			expected: []string{"machine-a1", "machine-b1", "machine-b2"},
+		},
+	}
+
+	for _, test := range tests {
+		stop := make(chan struct{})
+		scache := schedulercache.New(10*time.Minute, stop)
+		for _, node := range test.nodes {
+			scache.AddNode(node)
+		}
+		for _, pod := range test.placed {
+			if err := scache.AddPod(pod); err != nil {
+				t.Fatalf("%s: AddPod failed: %v", test.name, err)
+			}
+		}
+		algo := core.NewGenericScheduler(
+			scache,
+			nil,
+			map[string]algorithm.FitPredicate{"PodFitsResources": predicates.PodFitsResources},
+			algorithm.EmptyMetadataProducer,
+			[]algorithm.PriorityConfig{},
+			algorithm.EmptyMetadataProducer,
+			[]algorithm.SchedulerExtender{},
+			core.NodeSampling{},
+			core.PredicateEvaluation{})
+		updater := &countingPodConditionUpdater{}
+		sched := &Scheduler{config: &Config{
+			SchedulerCache:      scache,
+			NodeLister:          schedulertesting.FakeNodeLister(test.nodes),
+			Algorithm:           algo,
+			PodConditionUpdater: updater,
+		}}
+
+		role := &schedulerapi.ResourceObject{Role: "worker", Min: test.count, Max: test.count, PendingPods: map[string]*v1.Pod{}}
+		for i := 0; i < test.count; i++ {
+			pod := member(fmt.Sprintf("worker-%d", i), "", test.milliCPU)
+			role.PendingPods[pod.Name] = pod
+		}
+		role.PendingPodCount = len(role.PendingPods)
+		group := &schedulerapi.SchedulingGroup{
+			Group:     "foo/gang",
+			Topology:  &schedulerapi.GroupTopology{Key: "zone", Mode: test.mode},
+			Resources: []*schedulerapi.ResourceObject{role},
+			Status:    &schedulerapi.SchedulerGroupState{PodsToBind: map[string]*v1.Pod{}},
+		}
+
+		domains, err := sched.orderTopologyDomains(group, group.Resources, "zone", false)
+		if err != nil {
+			t.Fatalf("%s: orderTopologyDomains failed: %v", test.name, err)
+		}
+		if !reflect.DeepEqual(domains, test.domains) {
+			t.Errorf("%s: expected domains %v, got %v", test.name, test.domains, domains)
+		}
+
+		err = sched.placeGroup(group, group.Resources)
+		if (err != nil) != test.expectErr {
+			t.Errorf("%s: expected error %v, got %v", test.name, test.expectErr, err)
+		}
+		var placed []string
+		for _, pod := range group.Status.PodsToBind {
+			placed = append(placed, pod.Spec.NodeName)
+		}
+		sort.Strings(placed)
+		if !reflect.DeepEqual(placed, test.expected) {
+			t.Errorf("%s: expected members on %v, got %v", test.name, test.expected, placed)
+		}
+		if updater.updates != test.conditions {
+			t.Errorf("%s: expected %d pod conditions written, got %d", test.name, test.conditions, updater.updates)
+		}
+		close(stop)
+	}
+}
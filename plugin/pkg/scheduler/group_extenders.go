@@ -0,0 +1,146 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/golang/glog"
+	"k8s.io/kubernetes/pkg/api/v1"
+	"k8s.io/kubernetes/plugin/pkg/scheduler/algorithm"
+	schedulerapi "k8s.io/kubernetes/plugin/pkg/scheduler/api"
+	"k8s.io/kubernetes/plugin/pkg/scheduler/tools"
+)
+
+// prioritizeGroup asks the group extenders to score the nodes for the group, and keeps the
+// weighted sum of the scores in the status of the group. The members prefer the nodes scored
+// higher through GroupNodeScorePriority. Like the errors of prioritize calls for a pod, errors
+// are ignored.
+func (sched *Scheduler) prioritizeGroup(group *schedulerapi.SchedulingGroup) {
+	group.Status.NodeScores = nil
+	var pods []*v1.Pod
+	for _, rb := range group.Resources {
+		for _, pod := range rb.PendingPods {
+			pods = append(pods, pod)
+		}
+	}
+	extenders := sched.interestedGroupExtenders(pods)
+	if len(extenders) == 0 {
+		return
+	}
+	nodes, err := sched.config.NodeLister.List()
+	if err != nil {
+		glog.Errorf("Failed to list nodes to prioritize for group %s: %v", group.Group, err)
+		return
+	}
+	scores := map[string]int{}
+	for _, extender := range extenders {
+		prioritizedList, weight, err := extender.GroupPrioritize(group, nodes)
+		if err != nil {
+			glog.V(3).Infof("Group prioritize of group %s failed, ignoring: %v", group.Group, err)
+			continue
+		}
+		for _, hostPriority := range *prioritizedList {
+			if hostPriority.Score > 0 {
+				scores[hostPriority.Host] += hostPriority.Score * weight
+			}
+		}
+	}
+	if len(scores) > 0 {
+		group.Status.NodeScores = scores
+	}
+}
+
+// filterGroup sends the placement of the members of the group to the group extenders before
+// they are bound. An extender may reject the placement, or move members to other nodes. The
+// members moved are assumed on the new node without checking the predicates again, it is up
+// to the extender to pick a node which fits them.
+func (sched *Scheduler) filterGroup(group *schedulerapi.SchedulingGroup) error {
+	pods := make([]*v1.Pod, 0, len(group.Status.PodsToBind))
+	for _, pod := range group.Status.PodsToBind {
+		pods = append(pods, pod)
+	}
+	for _, extender := range sched.interestedGroupExtenders(pods) {
+		overrides, err := extender.GroupFilter(group, groupAssignments(pods))
+		if err != nil {
+			return fmt.Errorf("group extender rejected the placement: %v", err)
+		}
+		for _, assignment := range overrides {
+			pod, ok := group.Status.PodsToBind[assignment.PodName]
+			if !ok || pod.Namespace != assignment.PodNamespace {
+				return fmt.Errorf("group extender placed unknown pod %s/%s", assignment.PodNamespace, assignment.PodName)
+			}
+			if pod.Spec.NodeName == assignment.Node {
+				continue
+			}
+			glog.V(3).Infof("Group extender moves pod %s/%s from node %s to %s", pod.Namespace, pod.Name, pod.Spec.NodeName, assignment.Node)
+			if err := sched.forget(pod); err != nil {
+				return err
+			}
+			assumedPod := *pod
+			if err := sched.assume(&assumedPod, assignment.Node); err != nil {
+				delete(group.Status.PodsToBind, pod.Name)
+				pod.Spec.NodeName = ""
+				return err
+			}
+			pod.Spec.NodeName = assignment.Node
+		}
+	}
+	return nil
+}
+
+// groupBinder returns the group extender binding groups, if any is interested in the pods.
+// Otherwise the pods are bound by the binder of the scheduler.
+func (sched *Scheduler) groupBinder(pods []*v1.Pod) algorithm.GroupExtender {
+	for _, extender := range sched.interestedGroupExtenders(pods) {
+		if extender.IsGroupBinder() {
+			return extender
+		}
+	}
+	return nil
+}
+
+// interestedGroupExtenders returns the group extenders interested in at least one of the pods.
+func (sched *Scheduler) interestedGroupExtenders(pods []*v1.Pod) []algorithm.GroupExtender {
+	var extenders []algorithm.GroupExtender
+	for _, extender := range sched.config.GroupExtenders {
+		for _, pod := range pods {
+			if extender.IsInterested(pod) {
+				extenders = append(extenders, extender)
+				break
+			}
+		}
+	}
+	return extenders
+}
+
+// groupAssignments returns the nodes the pods are assumed on, ordered by pod name.
+func groupAssignments(pods []*v1.Pod) []schedulerapi.ExtenderPodAssignment {
+	assignments := make([]schedulerapi.ExtenderPodAssignment, 0, len(pods))
+	for _, pod := range pods {
+		assignments = append(assignments, schedulerapi.ExtenderPodAssignment{
+			PodName:      pod.Name,
+			PodNamespace: pod.Namespace,
+			PodUID:       pod.UID,
+			Role:         tools.GetSchedulingGroup(pod).Role,
+			Node:         pod.Spec.NodeName,
+		})
+	}
+	sort.Slice(assignments, func(i, j int) bool { return assignments[i].PodName < assignments[j].PodName })
+	return assignments
+}
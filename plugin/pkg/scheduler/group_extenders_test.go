@@ -0,0 +1,144 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kubernetes/pkg/api/v1"
+	"k8s.io/kubernetes/plugin/pkg/scheduler/algorithm"
+	schedulerapi "k8s.io/kubernetes/plugin/pkg/scheduler/api"
+	schedulertesting "k8s.io/kubernetes/plugin/pkg/scheduler/testing"
+)
+
+type fakeGroupExtender struct {
+	scores    schedulerapi.HostPriorityList
+	overrides []schedulerapi.ExtenderPodAssignment
+	err       error
+	bound     []schedulerapi.ExtenderPodAssignment
+	// interested is the name of the only member the extender is interested in, if set.
+	interested string
+}
+
+func (f *fakeGroupExtender) GroupPrioritize(group *schedulerapi.SchedulingGroup, nodes []*v1.Node) (*schedulerapi.HostPriorityList, int, error) {
+	return &f.scores, 1, nil
+}
+
+func (f *fakeGroupExtender) GroupFilter(group *schedulerapi.SchedulingGroup, assignments []schedulerapi.ExtenderPodAssignment) ([]schedulerapi.ExtenderPodAssignment, error) {
+	return f.overrides, f.err
+}
+
+func (f *fakeGroupExtender) GroupBind(group *schedulerapi.SchedulingGroup, assignments []schedulerapi.ExtenderPodAssignment) error {
+	f.bound = assignments
+	return f.err
+}
+
+func (f *fakeGroupExtender) IsGroupBinder() bool {
+	return true
+}
+
+func (f *fakeGroupExtender) IsInterested(pod *v1.Pod) bool {
+	return f.interested == "" || f.interested == pod.Name
+}
+
+func TestFilterGroup(t *testing.T) {
+	tests := []struct {
+		name      string
+		extender  *fakeGroupExtender
+		expectErr bool
+		nodes     map[string]string
+	}{
+		{
+			name:     "placement accepted",
+			extender: &fakeGroupExtender{},
+			nodes:    map[string]string{"worker-0": "machine1", "worker-1": "machine1"},
+		},
+		{
+			name: "member moved",
+			extender: &fakeGroupExtender{overrides: []schedulerapi.ExtenderPodAssignment{
+				{PodName: "worker-1", PodNamespace: "bar", Node: "machine2"},
+			}},
+			nodes: map[string]string{"worker-0": "machine1", "worker-1": "machine2"},
+		},
+		{
+			name: "unknown member",
+			extender: &fakeGroupExtender{overrides: []schedulerapi.ExtenderPodAssignment{
+				{PodName: "worker-2", PodNamespace: "bar", Node: "machine2"},
+			}},
+			expectErr: true,
+			nodes:     map[string]string{"worker-0": "machine1", "worker-1": "machine1"},
+		},
+		{
+			name:      "placement rejected",
+			extender:  &fakeGroupExtender{err: errors.New("no fabric left")},
+			expectErr: true,
+			nodes:     map[string]string{"worker-0": "machine1", "worker-1": "machine1"},
+		},
+		{
+			name:      "placement rejected by extender interested in a member",
+			extender:  &fakeGroupExtender{err: errors.New("no fabric left"), interested: "worker-1"},
+			expectErr: true,
+			nodes:     map[string]string{"worker-0": "machine1", "worker-1": "machine1"},
+		},
+		{
+			name:     "extender not interested in the group",
+			extender: &fakeGroupExtender{err: errors.New("no fabric left"), interested: "worker-2"},
+			nodes:    map[string]string{"worker-0": "machine1", "worker-1": "machine1"},
+		},
+	}
+
+	for _, test := range tests {
+		assumed := map[string]string{}
+		group := &schedulerapi.SchedulingGroup{
+			Group: "bar/group",
+			Status: &schedulerapi.SchedulerGroupState{
+				PodsToBind: map[string]*v1.Pod{},
+			},
+		}
+		for _, name := range []string{"worker-0", "worker-1"} {
+			group.Status.PodsToBind[name] = &v1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "bar"},
+				Spec:       v1.PodSpec{NodeName: "machine1"},
+			}
+			assumed[name] = "machine1"
+		}
+		sched := &Scheduler{
+			config: &Config{
+				SchedulerCache: &schedulertesting.FakeCache{
+					AssumeFunc: func(pod *v1.Pod) { assumed[pod.Name] = pod.Spec.NodeName },
+					ForgetFunc: func(pod *v1.Pod) { delete(assumed, pod.Name) },
+				},
+				GroupExtenders: []algorithm.GroupExtender{test.extender},
+			},
+		}
+
+		err := sched.filterGroup(group)
+		if (err != nil) != test.expectErr {
+			t.Errorf("%s: expected error %v, got %v", test.name, test.expectErr, err)
+		}
+		nodes := map[string]string{}
+		for name, pod := range group.Status.PodsToBind {
+			nodes[name] = pod.Spec.NodeName
+		}
+		if !reflect.DeepEqual(nodes, test.nodes) || !reflect.DeepEqual(assumed, test.nodes) {
+			t.Errorf("%s: expected members on %v, got %v and assumed %v", test.name, test.nodes, nodes, assumed)
+		}
+	}
+}
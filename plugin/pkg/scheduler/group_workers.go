@@ -0,0 +1,141 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"fmt"
+
+	"github.com/golang/glog"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/kubernetes/plugin/pkg/scheduler/algorithm"
+	schedulerapi "k8s.io/kubernetes/plugin/pkg/scheduler/api"
+	"k8s.io/kubernetes/plugin/pkg/scheduler/core"
+	"k8s.io/kubernetes/plugin/pkg/scheduler/metrics"
+	"k8s.io/kubernetes/plugin/pkg/scheduler/schedulercache"
+)
+
+const (
+	// DefaultGroupWorkers is the number of groups scheduled at once if not configured.
+	DefaultGroupWorkers = 1
+
+	// maxPlacementConflicts is how often a group is placed again in one attempt when a node it
+	// was placed on changed before it was committed.
+	maxPlacementConflicts = 3
+)
+
+// snapshotAlgorithm is implemented by the algorithms which can read the nodes and pods from
+// another cache than the one they were created with, like the generic scheduler.
+type snapshotAlgorithm interface {
+	OnCache(cache schedulercache.Cache, eCache *core.EquivalenceCache) (algorithm.ScheduleAlgorithm, error)
+}
+
+// runGroupWorkers starts the workers scheduling groups, each with a snapshot of the cache of
+// its own. The snapshot is kept across the attempts of the worker, only the nodes changed
+// since the last attempt are copied again. So is the equivalence cache of the predicates
+// evaluated on the snapshot, until the snapshot is updated.
+func (sched *Scheduler) runGroupWorkers() {
+	workers := sched.config.GroupWorkers
+	if workers <= 0 {
+		workers = DefaultGroupWorkers
+	}
+	for i := 0; i < workers; i++ {
+		snapshot := schedulercache.NewSnapshot(sched.config.SchedulerCache)
+		snapshotEcache := sched.config.Ecache.NewEmpty()
+		go wait.Until(func() { sched.scheduleOne(snapshot, snapshotEcache) }, 0, sched.config.StopEverything)
+	}
+}
+
+// onSnapshot returns the scheduler to place a group with on a snapshot of the cache: the
+// snapshot of the worker, or one of its own if the worker has none. The snapshot takes over
+// the changes of the cache first. The members are assumed on the snapshot and committed to the
+// cache at once, so other workers never see part of a gang. The algorithm reads the nodes and
+// pods from the snapshot if it can, so workers don't wait for each other; other algorithms
+// keep reading the cache they were created with.
+func (sched *Scheduler) onSnapshot() (*Scheduler, error) {
+	placement := &Scheduler{snapshot: sched.snapshot, snapshotEcache: sched.snapshotEcache}
+	if placement.snapshot == nil {
+		placement.snapshot = schedulercache.NewSnapshot(sched.config.SchedulerCache)
+		placement.snapshotEcache = sched.config.Ecache.NewEmpty()
+	}
+	if err := placement.updateSnapshot(); err != nil {
+		return nil, fmt.Errorf("failed to update the snapshot of the cache: %v", err)
+	}
+	config := *sched.config
+	config.SchedulerCache = placement.snapshot
+	if algo, ok := sched.config.Algorithm.(snapshotAlgorithm); ok {
+		onSnapshot, err := algo.OnCache(placement.snapshot, placement.snapshotEcache)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create the algorithm on the snapshot of the cache: %v", err)
+		}
+		config.Algorithm = onSnapshot
+	}
+	placement.config = &config
+	return placement, nil
+}
+
+// updateSnapshot takes over the changes of the cache in the snapshot. The predicates cached
+// on the snapshot are invalidated, the nodes they were evaluated on may have changed.
+func (sched *Scheduler) updateSnapshot() error {
+	if sched.snapshotEcache != nil {
+		sched.snapshotEcache.InvalidateAll()
+	}
+	return sched.snapshot.Update()
+}
+
+// placeAndCommitGroup places the roles of the group and has the group extenders check the
+// placement. On a snapshot the members are then committed to the cache at once. If another
+// worker changed a node they are placed on meanwhile, the snapshot is updated and the group is
+// placed again, up to maxPlacementConflicts times. On error the pods placed so far are left
+// assumed and in PodsToBind.
+func (sched *Scheduler) placeAndCommitGroup(group *schedulerapi.SchedulingGroup, roles []*schedulerapi.ResourceObject) error {
+	for conflicts := 0; ; conflicts++ {
+		err := sched.placeGroup(group, roles)
+		if err == nil {
+			err = sched.filterGroup(group)
+		}
+		if err != nil || sched.snapshot == nil {
+			return err
+		}
+
+		err = sched.snapshot.Commit()
+		if err == nil {
+			sched.invalidatePredicates(group)
+			return nil
+		}
+		if _, ok := err.(*schedulercache.ConflictError); !ok || conflicts == maxPlacementConflicts {
+			return err
+		}
+		metrics.PlacementConflicts.Inc()
+		glog.V(3).Infof("Placing group %s again: %v", group.Group, err)
+		sched.releaseResources(group)
+		if err := sched.updateSnapshot(); err != nil {
+			return err
+		}
+	}
+}
+
+// invalidatePredicates invalidates the predicates cached on the cache for the nodes the members
+// of the group were committed to. Assuming them on the snapshot only invalidated the predicates
+// cached on the snapshot.
+func (sched *Scheduler) invalidatePredicates(group *schedulerapi.SchedulingGroup) {
+	if sched.config.Ecache == nil {
+		return
+	}
+	for _, pod := range group.Status.PodsToBind {
+		sched.config.Ecache.InvalidateCachedPredicateItemForPodAdd(pod, pod.Spec.NodeName)
+	}
+}
@@ -0,0 +1,316 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/kubernetes/pkg/api/v1"
+	"k8s.io/kubernetes/plugin/pkg/scheduler/algorithm"
+	"k8s.io/kubernetes/plugin/pkg/scheduler/algorithm/predicates"
+	schedulerapi "k8s.io/kubernetes/plugin/pkg/scheduler/api"
+	"k8s.io/kubernetes/plugin/pkg/scheduler/core"
+	"k8s.io/kubernetes/plugin/pkg/scheduler/schedulercache"
+	schedulertesting "k8s.io/kubernetes/plugin/pkg/scheduler/testing"
+)
+
+// conflictingGroupExtender accepts every placement, and the first time it is asked assumes a
+// pod on the node of the first member in the cache, as if another worker committed it first.
+type conflictingGroupExtender struct {
+	fakeGroupExtender
+	cache    schedulercache.Cache
+	filtered int
+}
+
+func (e *conflictingGroupExtender) GroupFilter(group *schedulerapi.SchedulingGroup, assignments []schedulerapi.ExtenderPodAssignment) ([]schedulerapi.ExtenderPodAssignment, error) {
+	e.filtered++
+	if e.filtered == 1 {
+		other := podWithID("other", assignments[0].Node)
+		if err := e.cache.AssumePod(other); err != nil {
+			return nil, err
+		}
+	}
+	return nil, nil
+}
+
+func TestPlaceAndCommitGroupOnConflict(t *testing.T) {
+	stop := make(chan struct{})
+	defer close(stop)
+	scache := schedulercache.New(10*time.Minute, stop)
+	node := v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "machine1"}}
+	scache.AddNode(&node)
+	algo := core.NewGenericScheduler(
+		scache,
+		nil,
+		map[string]algorithm.FitPredicate{},
+		algorithm.EmptyMetadataProducer,
+		[]algorithm.PriorityConfig{},
+		algorithm.EmptyMetadataProducer,
+		[]algorithm.SchedulerExtender{},
+		core.NodeSampling{},
+		core.PredicateEvaluation{})
+	extender := &conflictingGroupExtender{cache: scache}
+	sched := &Scheduler{
+		config: &Config{
+			SchedulerCache: scache,
+			NodeLister:     schedulertesting.FakeNodeLister([]*v1.Node{&node}),
+			Algorithm:      algo,
+			GroupExtenders: []algorithm.GroupExtender{extender},
+		},
+		snapshot: schedulercache.NewSnapshot(scache),
+	}
+
+	pod := podWithID("worker-0", "")
+	group := &schedulerapi.SchedulingGroup{
+		Group: "foo/group",
+		Resources: []*schedulerapi.ResourceObject{{
+			Role:            "worker",
+			Min:             1,
+			Max:             1,
+			PendingPods:     map[string]*v1.Pod{pod.Name: pod},
+			PendingPodCount: 1,
+		}},
+		Status: &schedulerapi.SchedulerGroupState{PodsToBind: map[string]*v1.Pod{}},
+	}
+
+	placement, err := sched.onSnapshot()
+	if err != nil {
+		t.Fatalf("onSnapshot failed: %v", err)
+	}
+	if placement.config.SchedulerCache != sched.snapshot {
+		t.Fatalf("expected the group to be placed on the snapshot")
+	}
+	if err := placement.placeAndCommitGroup(group, group.Resources); err != nil {
+		t.Fatalf("placeAndCommitGroup failed: %v", err)
+	}
+	if extender.filtered != 2 {
+		t.Errorf("expected the group to be placed again after the conflict, filtered %d times", extender.filtered)
+	}
+	if placed, ok := group.Status.PodsToBind[pod.Name]; !ok || placed.Spec.NodeName != node.Name {
+		t.Errorf("expected %s to be placed on %s, got %v", pod.Name, node.Name, group.Status.PodsToBind)
+	}
+	pods, err := scache.List(labels.Everything())
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if len(pods) != 2 {
+		t.Errorf("expected the member committed next to the other pod, got %d pods in the cache", len(pods))
+	}
+}
+
+// observingGroupExtender accepts every placement and records the pods in the cache when it
+// is asked.
+type observingGroupExtender struct {
+	fakeGroupExtender
+	cache  schedulercache.Cache
+	cached int
+}
+
+func (e *observingGroupExtender) GroupFilter(group *schedulerapi.SchedulingGroup, assignments []schedulerapi.ExtenderPodAssignment) ([]schedulerapi.ExtenderPodAssignment, error) {
+	pods, err := e.cache.List(labels.Everything())
+	e.cached = len(pods)
+	return nil, err
+}
+
+// TestPlaceGroupWithoutWorkerSnapshot tests that a group placed by a scheduler without a
+// snapshot of a worker is still assumed in the cache at once, after all members are placed.
+func TestPlaceGroupWithoutWorkerSnapshot(t *testing.T) {
+	stop := make(chan struct{})
+	defer close(stop)
+	scache := schedulercache.New(10*time.Minute, stop)
+	node := v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "machine1"}}
+	scache.AddNode(&node)
+	algo := core.NewGenericScheduler(
+		scache,
+		nil,
+		map[string]algorithm.FitPredicate{},
+		algorithm.EmptyMetadataProducer,
+		[]algorithm.PriorityConfig{},
+		algorithm.EmptyMetadataProducer,
+		[]algorithm.SchedulerExtender{},
+		core.NodeSampling{},
+		core.PredicateEvaluation{})
+	extender := &observingGroupExtender{cache: scache}
+	sched := &Scheduler{config: &Config{
+		SchedulerCache: scache,
+		NodeLister:     schedulertesting.FakeNodeLister([]*v1.Node{&node}),
+		Algorithm:      algo,
+		GroupExtenders: []algorithm.GroupExtender{extender},
+	}}
+
+	role := &schedulerapi.ResourceObject{Role: "worker", Min: 2, Max: 2, PendingPods: map[string]*v1.Pod{}}
+	for _, name := range []string{"worker-0", "worker-1"} {
+		role.PendingPods[name] = podWithID(name, "")
+	}
+	role.PendingPodCount = len(role.PendingPods)
+	group := &schedulerapi.SchedulingGroup{
+		Group:     "foo/group",
+		Resources: []*schedulerapi.ResourceObject{role},
+		Status:    &schedulerapi.SchedulerGroupState{PodsToBind: map[string]*v1.Pod{}},
+	}
+
+	placement, err := sched.onSnapshot()
+	if err != nil {
+		t.Fatalf("onSnapshot failed: %v", err)
+	}
+	if placement.snapshot == nil {
+		t.Fatalf("expected the group to be placed on a snapshot of its own")
+	}
+	if err := placement.placeAndCommitGroup(group, group.Resources); err != nil {
+		t.Fatalf("placeAndCommitGroup failed: %v", err)
+	}
+	if extender.cached != 0 {
+		t.Errorf("expected no member in the cache before the group was placed, got %d", extender.cached)
+	}
+	pods, err := scache.List(labels.Everything())
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if len(pods) != 2 {
+		t.Errorf("expected the members assumed in the cache, got %d pods", len(pods))
+	}
+}
+
+func TestPlaceGroupOnSnapshotCachesPredicatesOfSnapshot(t *testing.T) {
+	stop := make(chan struct{})
+	defer close(stop)
+	scache := schedulercache.New(10*time.Minute, stop)
+	node := v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "machine1"}}
+	scache.AddNode(&node)
+	ecache := core.NewEquivalenceCache(func(pod *v1.Pod) interface{} { return nil })
+	sched := &Scheduler{
+		config: &Config{
+			SchedulerCache: scache,
+			Ecache:         ecache,
+			NodeLister:     schedulertesting.FakeNodeLister([]*v1.Node{&node}),
+		},
+		snapshot:       schedulercache.NewSnapshot(scache),
+		snapshotEcache: ecache.NewEmpty(),
+	}
+	pod := podWithID("worker-0", "")
+	sched.snapshotEcache.UpdateCachedPredicateItem(pod, node.Name, "GeneralPredicates", true, nil, 123, nil)
+
+	placement, err := sched.onSnapshot()
+	if err != nil {
+		t.Fatalf("onSnapshot failed: %v", err)
+	}
+	if placement.predicateCache() != sched.snapshotEcache {
+		t.Errorf("expected the predicates on the snapshot to be cached apart from the cache")
+	}
+	if placement.config.Ecache != ecache {
+		t.Errorf("expected the predicates on the cache to be invalidated on commit")
+	}
+	if _, _, invalid := sched.snapshotEcache.PredicateWithECache(pod, node.Name, "GeneralPredicates", 123); !invalid {
+		t.Errorf("expected the predicates cached on the snapshot to be invalidated when it is updated")
+	}
+}
+
+// nodeInfoGetter gets the nodes by name for the pod affinity predicate.
+type nodeInfoGetter []*v1.Node
+
+func (nodes nodeInfoGetter) GetNodeInfo(name string) (*v1.Node, error) {
+	for _, node := range nodes {
+		if node.Name == name {
+			return node, nil
+		}
+	}
+	return nil, fmt.Errorf("node %s not found", name)
+}
+
+// TestPlaceGroupOnSnapshotSeesAssumedMembers tests that the predicates on a snapshot list the
+// members assumed on it, so the members of a role anti-affine to itself are spread.
+func TestPlaceGroupOnSnapshotSeesAssumedMembers(t *testing.T) {
+	stop := make(chan struct{})
+	defer close(stop)
+	scache := schedulercache.New(10*time.Minute, stop)
+	nodes := []*v1.Node{
+		{ObjectMeta: metav1.ObjectMeta{Name: "machine1", Labels: map[string]string{"hostname": "machine1"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "machine2", Labels: map[string]string{"hostname": "machine2"}}},
+	}
+	for _, node := range nodes {
+		scache.AddNode(node)
+	}
+	// The members would all be placed on machine1 if they didn't see each other.
+	preferMachine1 := func(pod *v1.Pod, meta interface{}, nodeInfo *schedulercache.NodeInfo) (schedulerapi.HostPriority, error) {
+		score := 0
+		if nodeInfo.Node().Name == "machine1" {
+			score = 10
+		}
+		return schedulerapi.HostPriority{Host: nodeInfo.Node().Name, Score: score}, nil
+	}
+	newPlugins := func(pods algorithm.PodLister) (*core.Plugins, error) {
+		return &core.Plugins{
+			Predicates:            map[string]algorithm.FitPredicate{"MatchInterPodAffinity": predicates.NewPodAffinityPredicate(nodeInfoGetter(nodes), pods)},
+			PredicateMetaProducer: algorithm.EmptyMetadataProducer,
+			Prioritizers:          []algorithm.PriorityConfig{{Name: "PreferMachine1", Map: preferMachine1, Weight: 1}},
+			PriorityMetaProducer:  algorithm.EmptyMetadataProducer,
+		}, nil
+	}
+	algo, err := core.NewGenericSchedulerFromPlugins(scache, nil, newPlugins, []algorithm.SchedulerExtender{}, core.NodeSampling{}, core.PredicateEvaluation{})
+	if err != nil {
+		t.Fatalf("NewGenericSchedulerFromPlugins failed: %v", err)
+	}
+	sched := &Scheduler{
+		config: &Config{
+			SchedulerCache: scache,
+			NodeLister:     schedulertesting.FakeNodeLister(nodes),
+			Algorithm:      algo,
+		},
+		snapshot: schedulercache.NewSnapshot(scache),
+	}
+
+	antiAffinity := &v1.Affinity{
+		PodAntiAffinity: &v1.PodAntiAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: []v1.PodAffinityTerm{{
+				LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"role": "worker"}},
+				TopologyKey:   "hostname",
+			}},
+		},
+	}
+	role := &schedulerapi.ResourceObject{Role: "worker", Min: 2, Max: 2, PendingPods: map[string]*v1.Pod{}}
+	for _, name := range []string{"worker-0", "worker-1"} {
+		pod := podWithID(name, "")
+		pod.Labels = map[string]string{"role": "worker"}
+		pod.Spec.Affinity = antiAffinity
+		role.PendingPods[name] = pod
+	}
+	role.PendingPodCount = len(role.PendingPods)
+	group := &schedulerapi.SchedulingGroup{
+		Group:     "foo/group",
+		Resources: []*schedulerapi.ResourceObject{role},
+		Status:    &schedulerapi.SchedulerGroupState{PodsToBind: map[string]*v1.Pod{}},
+	}
+
+	placement, err := sched.onSnapshot()
+	if err != nil {
+		t.Fatalf("onSnapshot failed: %v", err)
+	}
+	if err := placement.placeAndCommitGroup(group, group.Resources); err != nil {
+		t.Fatalf("placeAndCommitGroup failed: %v", err)
+	}
+	placed := map[string]bool{}
+	for _, pod := range group.Status.PodsToBind {
+		placed[pod.Spec.NodeName] = true
+	}
+	if len(placed) != 2 {
+		t.Errorf("expected the members on different nodes, got %v", group.Status.PodsToBind)
+	}
+}
@@ -52,6 +52,110 @@ var (
 			Buckets:   prometheus.ExponentialBuckets(1000, 2, 15),
 		},
 	)
+	BindingRetries = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Subsystem: schedulerSubsystem,
+			Name:      "binding_retries_total",
+			Help:      "Number of bindings retried after a retriable error",
+		},
+	)
+	PlacementConflicts = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Subsystem: schedulerSubsystem,
+			Name:      "placement_conflicts_total",
+			Help:      "Number of groups placed again because a node they were placed on changed before they were committed",
+		},
+	)
+	PredicateEvaluationLatency = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Subsystem: schedulerSubsystem,
+			Name:      "predicate_evaluation_latency_microseconds",
+			Help:      "Latency of evaluating a predicate on a node",
+			Buckets:   prometheus.ExponentialBuckets(1, 2, 15),
+		},
+		[]string{"predicate"},
+	)
+	PredicateFailures = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Subsystem: schedulerSubsystem,
+			Name:      "predicate_failures_total",
+			Help:      "Number of nodes a predicate failed on",
+		},
+		[]string{"predicate"},
+	)
+	PredicateFailureReasons = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Subsystem: schedulerSubsystem,
+			Name:      "predicate_failure_reasons_total",
+			Help:      "Number of nodes a pod didn't fit on, by failure reason",
+		},
+		[]string{"reason"},
+	)
+	PriorityEvaluationLatency = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Subsystem: schedulerSubsystem,
+			Name:      "priority_evaluation_latency_microseconds",
+			Help:      "Latency of evaluating a priority, per node for the map phase and per pod for the reduce and function phases",
+			Buckets:   prometheus.ExponentialBuckets(1, 2, 20),
+		},
+		[]string{"priority", "phase"},
+	)
+	ExtenderLatency = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Subsystem: schedulerSubsystem,
+			Name:      "extender_latency_microseconds",
+			Help:      "Latency of calls to a scheduler extender",
+			Buckets:   prometheus.ExponentialBuckets(1000, 2, 15),
+		},
+		[]string{"extender", "verb"},
+	)
+	ExtenderErrors = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Subsystem: schedulerSubsystem,
+			Name:      "extender_errors_total",
+			Help:      "Number of failed calls to a scheduler extender",
+		},
+		[]string{"extender", "verb"},
+	)
+	ExtenderSkips = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Subsystem: schedulerSubsystem,
+			Name:      "extender_skips_total",
+			Help:      "Number of calls to a scheduler extender skipped while its circuit is open",
+		},
+		[]string{"extender", "verb"},
+	)
+	ExtenderRetries = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Subsystem: schedulerSubsystem,
+			Name:      "extender_retries_total",
+			Help:      "Number of calls to a scheduler extender retried after they failed",
+		},
+		[]string{"extender", "verb"},
+	)
+	ExtenderCircuitOpen = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Subsystem: schedulerSubsystem,
+			Name:      "extender_circuit_open",
+			Help:      "Whether a scheduler extender is skipped after too many failed calls in a row",
+		},
+		[]string{"extender"},
+	)
+	PolicyReloads = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Subsystem: schedulerSubsystem,
+			Name:      "policy_reloads_total",
+			Help:      "Number of attempts to reload a changed scheduler policy, by result",
+		},
+		[]string{"result"},
+	)
+	PolicyLastReloadTimestamp = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Subsystem: schedulerSubsystem,
+			Name:      "policy_last_reload_timestamp_seconds",
+			Help:      "Time the scheduler policy in use was loaded at",
+		},
+	)
 )
 
 var registerMetrics sync.Once
@@ -63,6 +167,19 @@ func Register() {
 		prometheus.MustRegister(E2eSchedulingLatency)
 		prometheus.MustRegister(SchedulingAlgorithmLatency)
 		prometheus.MustRegister(BindingLatency)
+		prometheus.MustRegister(BindingRetries)
+		prometheus.MustRegister(PlacementConflicts)
+		prometheus.MustRegister(PredicateEvaluationLatency)
+		prometheus.MustRegister(PredicateFailures)
+		prometheus.MustRegister(PredicateFailureReasons)
+		prometheus.MustRegister(PriorityEvaluationLatency)
+		prometheus.MustRegister(ExtenderLatency)
+		prometheus.MustRegister(ExtenderErrors)
+		prometheus.MustRegister(ExtenderSkips)
+		prometheus.MustRegister(ExtenderRetries)
+		prometheus.MustRegister(ExtenderCircuitOpen)
+		prometheus.MustRegister(PolicyReloads)
+		prometheus.MustRegister(PolicyLastReloadTimestamp)
 	})
 }
 
@@ -0,0 +1,79 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"fmt"
+
+	"github.com/golang/glog"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/kubernetes/pkg/api/v1"
+	"k8s.io/kubernetes/plugin/pkg/scheduler/algorithm"
+	schedulerapi "k8s.io/kubernetes/plugin/pkg/scheduler/api"
+	"k8s.io/kubernetes/plugin/pkg/scheduler/tools"
+)
+
+// Profile is a named set of predicates, priorities and extenders a group can select to be
+// scheduled with, instead of the algorithm of the config.
+type Profile struct {
+	Algorithm algorithm.ScheduleAlgorithm
+	// GetBinder returns the binder of a pod, see Config.GetBinder.
+	GetBinder func(pod *v1.Pod) Binder
+	// GroupExtenders are called with the whole group when it is placed and bound.
+	GroupExtenders []algorithm.GroupExtender
+}
+
+// profileOf returns the name of the profile the group is scheduled with: the profile selected
+// by its members, or else the default profile of its namespace. An error is returned if the
+// members disagree, the group would otherwise be placed with one profile and judged by another.
+func (sched *Scheduler) profileOf(group *schedulerapi.SchedulingGroup) (string, error) {
+	name := group.Profile
+	for _, rb := range group.Resources {
+		for _, pod := range rb.PendingPods {
+			miniGroup := tools.GetSchedulingGroup(pod)
+			if miniGroup != nil && miniGroup.Profile != name {
+				return "", fmt.Errorf("pod %s/%s selects profile %q, other members of group %s select %q",
+					pod.Namespace, pod.Name, miniGroup.Profile, group.Group, name)
+			}
+		}
+	}
+	if name == "" {
+		namespace, _, _ := cache.SplitMetaNamespaceKey(group.Group)
+		name = sched.config.NamespaceProfiles[namespace]
+	}
+	return name, nil
+}
+
+// withProfile returns the scheduler to place and bind the group with, using the algorithm and
+// the extenders of the profile of the group. The scheduler itself is returned if the group
+// doesn't select a profile.
+func (sched *Scheduler) withProfile(group *schedulerapi.SchedulingGroup) (*Scheduler, error) {
+	name, err := sched.profileOf(group)
+	if err != nil || name == "" {
+		return sched, err
+	}
+	profile, ok := sched.config.Profiles[name]
+	if !ok {
+		return sched, fmt.Errorf("profile %q of group %s is not defined", name, group.Group)
+	}
+	glog.V(4).Infof("Scheduling group %s with profile %s", group.Group, name)
+	config := *sched.config
+	config.Algorithm = profile.Algorithm
+	config.GetBinder = profile.GetBinder
+	config.GroupExtenders = profile.GroupExtenders
+	return &Scheduler{config: &config, snapshot: sched.snapshot, snapshotEcache: sched.snapshotEcache}, nil
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"fmt"
+	"testing"
+
+	"k8s.io/kubernetes/pkg/api/v1"
+	schedulerapi "k8s.io/kubernetes/plugin/pkg/scheduler/api"
+	"k8s.io/kubernetes/plugin/pkg/scheduler/tools"
+)
+
+func groupWithProfiles(profiles ...string) *schedulerapi.SchedulingGroup {
+	group := &schedulerapi.SchedulingGroup{Group: "training/gang", Profile: profiles[0]}
+	role := &schedulerapi.ResourceObject{Role: "worker", PendingPods: map[string]*v1.Pod{}}
+	for i, profile := range profiles {
+		pod := podWithID(fmt.Sprintf("worker-%d", i), "")
+		pod.Namespace = "training"
+		pod.Annotations = map[string]string{
+			tools.SchedulingGroup: fmt.Sprintf(`{"group": "training/gang", "role": "worker", "profile": %q}`, profile),
+		}
+		role.PendingPods[pod.Name] = pod
+	}
+	group.Resources = append(group.Resources, role)
+	return group
+}
+
+func TestWithProfile(t *testing.T) {
+	defaultAlgorithm := mockScheduler{machine: "default"}
+	sched := &Scheduler{config: &Config{
+		Algorithm: defaultAlgorithm,
+		Profiles: map[string]*Profile{
+			"spread":  {Algorithm: mockScheduler{machine: "spread"}},
+			"binpack": {Algorithm: mockScheduler{machine: "binpack"}},
+		},
+		NamespaceProfiles: map[string]string{"training": "binpack"},
+	}}
+
+	tests := []struct {
+		name      string
+		group     *schedulerapi.SchedulingGroup
+		noDefault bool
+		expected  string
+		expectErr bool
+	}{
+		{name: "selected profile", group: groupWithProfiles("spread", "spread"), expected: "spread"},
+		{name: "namespace profile", group: groupWithProfiles("", ""), expected: "binpack"},
+		{name: "no profile", group: groupWithProfiles(""), noDefault: true, expected: "default"},
+		{name: "members disagree", group: groupWithProfiles("spread", "binpack"), expectErr: true},
+		{name: "unknown profile", group: groupWithProfiles("gpu"), expectErr: true},
+	}
+	for _, test := range tests {
+		namespaceProfiles := sched.config.NamespaceProfiles
+		if test.noDefault {
+			sched.config.NamespaceProfiles = nil
+		}
+		profiled, err := sched.withProfile(test.group)
+		sched.config.NamespaceProfiles = namespaceProfiles
+		if test.expectErr {
+			if err == nil {
+				t.Errorf("%s: expected an error", test.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.name, err)
+			continue
+		}
+		if machine, _ := profiled.config.Algorithm.Schedule(nil, nil); machine != test.expected {
+			t.Errorf("%s: expected the algorithm of %s, got the one of %s", test.name, test.expected, machine)
+		}
+		if sched.config.Algorithm != defaultAlgorithm {
+			t.Errorf("%s: expected the algorithm of the scheduler to be kept", test.name)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"github.com/golang/glog"
+)
+
+// Reload makes the scheduler use the algorithm of a config created from a new policy. It is
+// taken over between two scheduling attempts, so that a group is placed and bound with a
+// single algorithm. Only the parts of the config derived from the policy are taken over, the
+// cache, the queue of groups and the recorder keep being used.
+func (sched *Scheduler) Reload(cfg *Config) {
+	sched.reloadLock.Lock()
+	defer sched.reloadLock.Unlock()
+	if sched.reloadedConfig != nil && sched.reloadedConfig.StopExtenders != nil {
+		// Replaced before it was taken over.
+		sched.reloadedConfig.StopExtenders()
+	}
+	sched.reloadedConfig = cfg
+}
+
+// currentConfig returns the config the scheduler uses. The config is replaced by the workers
+// scheduling groups and single pods, it is read through currentConfig outside of an attempt.
+func (sched *Scheduler) currentConfig() *Config {
+	sched.reloadLock.Lock()
+	defer sched.reloadLock.Unlock()
+	return sched.config
+}
+
+// takeOverReloadedConfig replaces the algorithm of the scheduler by the one reloaded last, if
+// any. It returns the config to use for the next scheduling attempt, which is to be released
+// by releaseConfig once the attempt ends, including the bindings it started. The extenders of
+// the config replaced are stopped once no attempt uses it anymore.
+func (sched *Scheduler) takeOverReloadedConfig() *Config {
+	sched.reloadLock.Lock()
+	defer sched.reloadLock.Unlock()
+	if reloaded := sched.reloadedConfig; reloaded != nil {
+		sched.reloadedConfig = nil
+		previous := sched.config
+		config := *previous
+		config.Algorithm = reloaded.Algorithm
+		config.Ecache = reloaded.Ecache
+		config.Binder = reloaded.Binder
+		config.GetBinder = reloaded.GetBinder
+		config.GroupExtenders = reloaded.GroupExtenders
+		config.Profiles = reloaded.Profiles
+		config.NamespaceProfiles = reloaded.NamespaceProfiles
+		config.Binding = reloaded.Binding
+		config.StatusUpdateLimiter = reloaded.StatusUpdateLimiter
+		config.StopExtenders = reloaded.StopExtenders
+		sched.config = &config
+		if sched.configUsers[previous] == 0 && previous.StopExtenders != nil {
+			previous.StopExtenders()
+		}
+		glog.V(2).Infof("Scheduling with the algorithm of the reloaded policy")
+	}
+	if sched.configUsers == nil {
+		sched.configUsers = make(map[*Config]int)
+	}
+	sched.configUsers[sched.config]++
+	return sched.config
+}
+
+// releaseConfig ends an attempt using the config returned by takeOverReloadedConfig. The
+// extenders of a config replaced meanwhile are stopped when the last attempt using it ends.
+func (sched *Scheduler) releaseConfig(config *Config) {
+	sched.reloadLock.Lock()
+	defer sched.reloadLock.Unlock()
+	sched.configUsers[config]--
+	if sched.configUsers[config] > 0 {
+		return
+	}
+	delete(sched.configUsers, config)
+	if config != sched.config && config.StopExtenders != nil {
+		config.StopExtenders()
+	}
+}
@@ -0,0 +1,82 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	"k8s.io/client-go/tools/record"
+	schedulertesting "k8s.io/kubernetes/plugin/pkg/scheduler/testing"
+)
+
+func TestReload(t *testing.T) {
+	stopped := map[string]int{}
+	stopExtenders := func(name string) func() {
+		return func() { stopped[name]++ }
+	}
+	recorder := &record.FakeRecorder{}
+	cache := &schedulertesting.FakeCache{}
+	sched := &Scheduler{config: &Config{
+		SchedulerCache: cache,
+		Recorder:       recorder,
+		Binding:        BindingPolicy{Workers: 1},
+		StopExtenders:  stopExtenders("initial"),
+	}}
+
+	// An attempt using the initial config is still running when the reloaded config is taken over.
+	initial := sched.takeOverReloadedConfig()
+	if sched.config.Binding.Workers != 1 {
+		t.Errorf("Expected the config to be kept without reload")
+	}
+
+	sched.Reload(&Config{Binding: BindingPolicy{Workers: 2}, StopExtenders: stopExtenders("replaced")})
+	sched.Reload(&Config{Binding: BindingPolicy{Workers: 4, Timeout: time.Minute}, StopExtenders: stopExtenders("reloaded")})
+	if stopped["replaced"] != 1 {
+		t.Errorf("Expected the extenders of the config replaced before it was taken over to be stopped")
+	}
+	if sched.config.Binding.Workers != 1 || stopped["initial"] != 0 {
+		t.Errorf("Expected the config not to be taken over before the next scheduling attempt")
+	}
+
+	reloaded := sched.takeOverReloadedConfig()
+	if sched.config.Binding.Workers != 4 || sched.config.Binding.Timeout != time.Minute {
+		t.Errorf("Expected the reloaded binding policy, got %+v", sched.config.Binding)
+	}
+	if sched.config.Recorder != recorder || sched.config.SchedulerCache != cache {
+		t.Errorf("Expected the recorder and the cache to be kept")
+	}
+	if stopped["initial"] != 0 {
+		t.Errorf("Expected the extenders of the config replaced to be kept while an attempt uses it")
+	}
+
+	sched.releaseConfig(initial)
+	if stopped["initial"] != 1 || stopped["reloaded"] != 0 {
+		t.Errorf("Expected only the extenders of the config replaced to be stopped, got %v", stopped)
+	}
+	sched.releaseConfig(reloaded)
+	if stopped["reloaded"] != 0 {
+		t.Errorf("Expected the extenders of the config in use to be kept, got %v", stopped)
+	}
+
+	// Without attempts using it, the config replaced is stopped at once.
+	sched.Reload(&Config{StopExtenders: stopExtenders("last")})
+	sched.releaseConfig(sched.takeOverReloadedConfig())
+	if stopped["reloaded"] != 1 || stopped["last"] != 0 {
+		t.Errorf("Expected the extenders of the idle config replaced to be stopped, got %v", stopped)
+	}
+}
@@ -17,9 +17,10 @@ limitations under the License.
 package scheduler
 
 import (
+	"fmt"
+	"sync"
 	"time"
 
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/wait"
@@ -38,8 +39,8 @@ import (
 	"github.com/golang/glog"
 	"github.com/pkg/errors"
 	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/flowcontrol"
 	"k8s.io/kubernetes/plugin/pkg/scheduler/tools"
-	"sync"
 )
 
 const (
@@ -67,6 +68,23 @@ type ConfigMapTool interface {
 // nodes that they fit on and writes bindings back to the api server.
 type Scheduler struct {
 	config *Config
+
+	// snapshot is the snapshot of the cache the worker making a scheduling attempt places the
+	// group on. The group is placed on the cache directly if nil.
+	snapshot *schedulercache.Snapshot
+	// snapshotEcache caches the predicates evaluated on snapshot, it is reset whenever the
+	// snapshot is updated. Config.Ecache caches the predicates evaluated on the cache.
+	snapshotEcache *core.EquivalenceCache
+
+	// tentative is set while a group is placed in one of several candidate domains. Pods
+	// which don't fit get no PodScheduled condition then, as the next domain may hold them.
+	tentative bool
+
+	// A config reloaded from a new policy, taken over before the next scheduling attempt.
+	reloadLock     sync.Mutex
+	reloadedConfig *Config
+	// configUsers counts the attempts using each config, guarded by reloadLock.
+	configUsers map[*Config]int
 }
 
 // StopEverything closes the scheduler config's StopEverything channel, to shut
@@ -89,6 +107,7 @@ type Configurator interface {
 
 	// Probably doesn't need to be public.  But exposed for now in case.
 	ResponsibleForGroup(group *schedulerapi.SchedulingGroup) bool
+	ResponsibleForPod(pod *v1.Pod) bool
 
 	// Needs to be exposed for things like integration tests where we want to make fake nodes.
 	GetNodeLister() corelisters.NodeLister
@@ -117,6 +136,9 @@ type Config struct {
 	NodeLister algorithm.NodeLister
 	Algorithm  algorithm.ScheduleAlgorithm
 	Binder     Binder
+	// GetBinder returns the binder of a pod, if set. It is used to bind the pods an
+	// extender binding pods is interested in through it, and the others with Binder.
+	GetBinder func(pod *v1.Pod) Binder
 	// PodConditionUpdater is used only in case of scheduling errors. If we succeed
 	// with scheduling, PodScheduled condition will be updated in apiserver in /bind
 	// handler so that binding and setting PodCondition it is atomic.
@@ -132,19 +154,56 @@ type Config struct {
 
 	PushBackSchedulingGroup func(*schedulerapi.SchedulingGroup)
 
+	// RecordSchedulingGroupBindings records in the group kept by the configurator that the pods
+	// were bound, if set. The groups returned by NextSchedulingGroup are copies of it then, whose
+	// bound roles and counts of bound members are taken over as well.
+	RecordSchedulingGroupBindings func(group *schedulerapi.SchedulingGroup, bound []*v1.Pod)
+
 	ForgetSchedulingGroup func(group string)
 
+	// DoneSchedulingGroup is called when an attempt to schedule a group returned by
+	// NextSchedulingGroup ends, if set. The group is not returned again before.
+	DoneSchedulingGroup func(*schedulerapi.SchedulingGroup)
+
+	// GroupWorkers is the number of groups scheduled at once, each on a snapshot of the cache
+	// of its own. One group is scheduled at a time if not set.
+	GroupWorkers int
+
 	// WaitForCacheSync waits for scheduler cache to populate.
 	// It returns true if it was successful, false if the controller should shutdown.
 	WaitForCacheSync func() bool
 
+	// NextPod blocks until the next pod that is not a member of a group is available. These
+	// pods are scheduled one by one apart from the groups, if set.
+	NextPod func() *v1.Pod
+
 	// Error is called if there is an error. It is passed the pod in
 	// question, and the error
 	Error func(*v1.Pod, error)
 
+	// GroupExtenders are called with the whole group when it is placed and bound.
+	GroupExtenders []algorithm.GroupExtender
+
+	// Profiles are the named algorithms a group can select instead of Algorithm.
+	Profiles map[string]*Profile
+	// NamespaceProfiles maps a namespace to the profile of its groups not selecting one.
+	NamespaceProfiles map[string]string
+
+	// Binding configures how the members of a group are bound.
+	Binding BindingPolicy
+
+	// StatusUpdateLimiter limits the rate events, pod conditions and group status are written
+	// at. They are not limited if nil.
+	StatusUpdateLimiter flowcontrol.RateLimiter
+
 	// Recorder is the EventRecorder to use
 	Recorder record.EventRecorder
 
+	// StopExtenders releases the connections of the extenders of the config, if set. It is
+	// called once the config is replaced by one reloaded from a new policy and the last
+	// scheduling attempt using it ended.
+	StopExtenders func()
+
 	// Close this to shut down the scheduler.
 	StopEverything chan struct{}
 }
@@ -174,19 +233,28 @@ func (sched *Scheduler) Run() {
 		return
 	}
 
-	go wait.Until(sched.scheduleOne, 0, sched.config.StopEverything)
+	if sched.config.NextSchedulingGroup != nil {
+		sched.runGroupWorkers()
+	}
+	if sched.config.NextPod != nil {
+		go wait.Until(sched.schedulePod, 0, sched.config.StopEverything)
+	}
 }
 
 // Config return scheduler's config pointer. It is exposed for testing purposes.
 func (sched *Scheduler) Config() *Config {
-	return sched.config
+	return sched.currentConfig()
 }
 
-// schedule implements the scheduling algorithm and returns the suggested host.
-func (sched *Scheduler) schedule(pod *v1.Pod) (string, error) {
-	host, err := sched.config.Algorithm.Schedule(pod, sched.config.NodeLister)
+// schedule implements the scheduling algorithm and returns the suggested host among the
+// nodes listed by nodeLister.
+func (sched *Scheduler) schedule(pod *v1.Pod, nodeLister algorithm.NodeLister) (string, error) {
+	host, err := sched.config.Algorithm.Schedule(pod, nodeLister)
 	if err != nil {
 		glog.V(1).Infof("Failed to schedule pod: %v/%v", pod.Namespace, pod.Name)
+		if sched.tentative {
+			return "", err
+		}
 		copied, cerr := api.Scheme.Copy(pod)
 		if cerr != nil {
 			runtime.HandleError(err)
@@ -194,8 +262,8 @@ func (sched *Scheduler) schedule(pod *v1.Pod) (string, error) {
 		}
 		pod = copied.(*v1.Pod)
 		//sched.config.Error(pod, err)
-		//sched.config.Recorder.Eventf(pod, v1.EventTypeWarning, "FailedScheduling", "%v", err)
-		sched.config.PodConditionUpdater.Update(pod, &v1.PodCondition{
+		// The failure is recorded as an event for the group in scheduleOne.
+		sched.updatePodCondition(pod, &v1.PodCondition{
 			Type:    v1.PodScheduled,
 			Status:  v1.ConditionFalse,
 			Reason:  v1.PodReasonUnschedulable,
@@ -223,8 +291,7 @@ func (sched *Scheduler) assume(assumed *v1.Pod, host string) error {
 		// to a node and if so will not add it back to the unscheduled pods queue
 		// (otherwise this would cause an infinite loop).
 		//sched.config.Error(assumed, err)
-		sched.config.Recorder.Eventf(assumed, v1.EventTypeWarning, "FailedScheduling", "AssumePod failed: %v", err)
-		sched.config.PodConditionUpdater.Update(assumed, &v1.PodCondition{
+		sched.updatePodCondition(assumed, &v1.PodCondition{
 			Type:    v1.PodScheduled,
 			Status:  v1.ConditionFalse,
 			Reason:  "SchedulerError",
@@ -236,47 +303,55 @@ func (sched *Scheduler) assume(assumed *v1.Pod, host string) error {
 	// Optimistically assume that the binding will succeed, so we need to invalidate affected
 	// predicates in equivalence cache.
 	// If the binding fails, these invalidated item will not break anything.
-	if sched.config.Ecache != nil {
-		sched.config.Ecache.InvalidateCachedPredicateItemForPodAdd(assumed, host)
+	if ecache := sched.predicateCache(); ecache != nil {
+		ecache.InvalidateCachedPredicateItemForPodAdd(assumed, host)
 	}
 	return nil
 }
 
-// bind binds a pod to a given node defined in a binding object.  We expect this to run asynchronously, so we
-// handle binding metrics internally.
-func (sched *Scheduler) bind(assumed *v1.Pod, b *v1.Binding) error {
-	bindingStart := time.Now()
-	// If binding succeeded then PodScheduled condition will be updated in apiserver so that
-	// it's atomic with setting host.
-	err := sched.config.Binder.Bind(b)
-	if err := sched.config.SchedulerCache.FinishBinding(assumed); err != nil {
-		glog.Errorf("scheduler cache FinishBinding failed: %v", err)
+// forget removes the assumed pods from the cache again at once, so that the next members of
+// their group see the nodes as they were before the pods were assumed there.
+func (sched *Scheduler) forget(assumed ...*v1.Pod) error {
+	if len(assumed) == 0 {
+		return nil
 	}
-	if err != nil {
-		glog.V(1).Infof("Failed to bind pod: %v/%v", assumed.Namespace, assumed.Name)
-		if err := sched.config.SchedulerCache.ForgetPod(assumed); err != nil {
-			glog.Errorf("scheduler cache ForgetPod failed: %v", err)
-		}
-		//sched.config.Error(assumed, err)
-		sched.config.Recorder.Eventf(assumed, v1.EventTypeWarning, "FailedScheduling", "Binding rejected: %v", err)
-		sched.config.PodConditionUpdater.Update(assumed, &v1.PodCondition{
-			Type:   v1.PodScheduled,
-			Status: v1.ConditionFalse,
-			Reason: "BindingRejected",
-		})
+	if err := sched.config.SchedulerCache.ForgetPods(assumed); err != nil {
 		return err
 	}
-
-	metrics.BindingLatency.Observe(metrics.SinceInMicroseconds(bindingStart))
-	sched.config.Recorder.Eventf(assumed, v1.EventTypeNormal, "Scheduled", "Successfully assigned %v to %v", assumed.Name, b.Target.Name)
+	if ecache := sched.predicateCache(); ecache != nil {
+		for _, pod := range assumed {
+			ecache.InvalidateCachedPredicateItemForPodDelete(pod, pod.Spec.NodeName)
+		}
+	}
 	return nil
 }
 
-// scheduleOne does the entire scheduling workflow for a single group.  It is serialized on the scheduling algorithm's host fitting.
-func (sched *Scheduler) scheduleOne() {
+// predicateCache returns the equivalence cache of the predicates evaluated on the cache the
+// group is placed on: the one of the snapshot if there is one.
+func (sched *Scheduler) predicateCache() *core.EquivalenceCache {
+	if sched.snapshot != nil {
+		return sched.snapshotEcache
+	}
+	return sched.config.Ecache
+}
 
-	group := sched.config.NextSchedulingGroup()
+// scheduleOne does the entire scheduling workflow for a single group, placing it on the
+// snapshot of the worker. Groups are scheduled concurrently by the workers.
+func (sched *Scheduler) scheduleOne(snapshot *schedulercache.Snapshot, snapshotEcache *core.EquivalenceCache) {
+
+	group := sched.currentConfig().NextSchedulingGroup()
+	// The attempt sticks to one config, the pods are scheduled concurrently.
+	config := sched.takeOverReloadedConfig()
+	defer sched.releaseConfig(config)
+	attempt := &Scheduler{config: config, snapshot: snapshot, snapshotEcache: snapshotEcache}
+	attempt.scheduleSchedulingGroup(group)
+	if attempt.config.DoneSchedulingGroup != nil {
+		attempt.config.DoneSchedulingGroup(group)
+	}
+}
 
+// scheduleSchedulingGroup schedules the group if all of its members are pending.
+func (sched *Scheduler) scheduleSchedulingGroup(group *schedulerapi.SchedulingGroup) {
 	glog.Infof("Successfully get group %v", group)
 
 	if !sched.readyToScheduler(group) {
@@ -287,64 +362,88 @@ func (sched *Scheduler) scheduleOne() {
 		return
 	}
 
-	for _, rb := range group.Resources {
-		cur := 0
-		var err error
-		for _, pod := range rb.PendingPods {
-			err = sched.schedulerPod(pod)
-			if err != nil {
-				break
-			}
-			group.Status.PodsToBind[pod.Name] = pod
-			cur++
-			if cur == rb.Min {
-				break
-			}
-		}
-		if cur < rb.Min {
-			if err != nil {
-				sched.updateConfigMap(group.Group, Cause, err.Error())
-			}
-			glog.Errorf("Failed to schedule group %s/%s, err: %v", group.Group, rb.Role, err)
-			sched.releaseResources(group)
-			sched.config.PushBackSchedulingGroup(group)
-			return
-		}
+	err := checkSchedulerNames(group)
+	profiled := sched
+	if err == nil {
+		profiled, err = sched.withProfile(group)
+	}
+	var placement *Scheduler
+	if err == nil {
+		placement, err = profiled.onSnapshot()
+	}
+	if err != nil {
+		glog.Errorf("Failed to schedule group %s: %v", group.Group, err)
+		sched.recordPlacementFailure(group, err)
+		sched.updateConfigMap(group.Group, Cause, err.Error())
+		sched.config.PushBackSchedulingGroup(group)
+		return
 	}
+	placement.scheduleGroup(group)
+}
 
-	pods := tools.SortOtherPendingPods(group)
+// scheduleGroup places and binds the members of a group ready to be scheduled.
+func (sched *Scheduler) scheduleGroup(group *schedulerapi.SchedulingGroup) {
+	roles, err := tools.SortGroupResources(group)
+	if err != nil {
+		glog.Errorf("Failed to order roles of group %s: %v", group.Group, err)
+		sched.recordPlacementFailure(group, err)
+		sched.updateConfigMap(group.Group, Cause, err.Error())
+		sched.config.PushBackSchedulingGroup(group)
+		return
+	}
 
-	for _, pod := range pods {
-		err := sched.schedulerPod(pod)
-		if err != nil {
-			break
+	deferred := sched.deferredRoles(group, roles)
+	placing := make([]*schedulerapi.ResourceObject, 0, len(roles))
+	for _, rb := range roles {
+		if rb.Bound || deferred[rb.Role] {
+			continue
 		}
-		group.Status.PodsToBind[pod.Name] = pod
+		placing = append(placing, rb)
 	}
 
-	// bind the pod to its host asynchronously (we can do this b/c of the assumption step above).
+	sched.prioritizeGroup(group)
+	err = sched.placeAndCommitGroup(group, placing)
+	if err != nil {
+		sched.recordPlacementFailure(group, err)
+		sched.updateConfigMap(group.Group, Cause, err.Error())
+		glog.Errorf("Failed to schedule group %s, err: %v", group.Group, err)
+		sched.releaseResources(group)
+		sched.config.PushBackSchedulingGroup(group)
+		return
+	}
 
-	var waitGroup sync.WaitGroup
+	// bind the pods to their hosts asynchronously (we can do this b/c of the assumption step above).
+	binding := make([]*v1.Pod, 0, len(group.Status.PodsToBind))
 	for _, pod := range group.Status.PodsToBind {
-		waitGroup.Add(1)
-		go func(pod *v1.Pod) {
-			defer waitGroup.Done()
-			nodeName := pod.Spec.NodeName
-			pod.Spec.NodeName = ""
-			glog.Infof("Binding pod %s/%s to node %s", pod.Namespace, pod.Name, nodeName)
-			err := sched.bind(pod, &v1.Binding{
-				ObjectMeta: metav1.ObjectMeta{Namespace: pod.Namespace, Name: pod.Name, UID: pod.UID},
-				Target: v1.ObjectReference{
-					Kind: "Node",
-					Name: nodeName,
-				},
-			})
-			if err != nil {
-				glog.Errorf("Internal error binding pod: (%v)", err)
-			}
-		}(pod)
+		binding = append(binding, pod)
+	}
+	results := sched.bindGroup(group, binding)
+	// The members of the group are expired together if some are never confirmed.
+	if err := sched.config.SchedulerCache.FinishBindings(binding); err != nil {
+		glog.Errorf("scheduler cache FinishBindings failed: %v", err)
+	}
+
+	sched.recordBindingOutcome(group, results)
+	if failed := sched.recordBindings(group, placing, results); failed > 0 {
+		err := fmt.Errorf("binding of %d of %d pods failed", failed, len(results))
+		glog.Errorf("Failed to bind group %s: %v", group.Group, err)
+		sched.updateConfigMap(group.Group, Cause, err.Error())
+		group.Status.State = schedulerapi.Failed
+		for key := range group.Status.PodsToBind {
+			delete(group.Status.PodsToBind, key)
+		}
+		sched.config.PushBackSchedulingGroup(group)
+		return
+	}
+
+	if len(deferred) > 0 {
+		glog.Infof("Group %s has %d roles waiting for their dependencies to start", group.Group, len(deferred))
+		for key := range group.Status.PodsToBind {
+			delete(group.Status.PodsToBind, key)
+		}
+		sched.config.PushBackSchedulingGroup(group)
+		return
 	}
-	waitGroup.Wait()
 
 	sched.updateConfigMap(group.Group, Scheduled, "true")
 
@@ -363,20 +462,25 @@ func (sched *Scheduler) updateConfigMap(key string, tag string, msg string) {
 		glog.V(4).Infof("failed to get configmap %s/%s.", ns, name)
 		return
 	}
+	if current, ok := configMap.Data[tag]; ok && current == msg {
+		return
+	}
 	if configMap.Data != nil {
 		configMap.Data[tag] = msg
 	} else {
 		configMap.Data = map[string]string{tag: msg}
 	}
+	sched.acceptStatusUpdate()
 	err = sched.config.ConfigMapTool.Update(configMap)
 	if err != nil {
 		glog.Warningf("failed to update configmap %s/%s.", ns, name)
 	}
 }
 
-func (sched *Scheduler) schedulerPod(pod *v1.Pod) error {
+// schedulerPod finds a node among the nodes listed by nodeLister for the pod of the group and
+// assumes it there. The nodes scored for the group are preferred.
+func (sched *Scheduler) schedulerPod(group *schedulerapi.SchedulingGroup, pod *v1.Pod, nodeLister algorithm.NodeLister) error {
 	if pod.DeletionTimestamp != nil {
-		sched.config.Recorder.Eventf(pod, v1.EventTypeWarning, "FailedScheduling", "skip schedule deleting pod: %v/%v", pod.Namespace, pod.Name)
 		glog.V(3).Infof("Skip schedule deleting pod: %v/%v", pod.Namespace, pod.Name)
 		return errors.New("Skip schedule deleting pod.")
 	}
@@ -385,7 +489,7 @@ func (sched *Scheduler) schedulerPod(pod *v1.Pod) error {
 
 	// Synchronously attempt to find a fit for the pod.
 	start := time.Now()
-	suggestedHost, err := sched.schedule(pod)
+	suggestedHost, err := sched.schedule(tools.WithGroupNodeScores(pod, group.Status.NodeScores), nodeLister)
 	metrics.SchedulingAlgorithmLatency.Observe(metrics.SinceInMicroseconds(start))
 	if err != nil {
 		return err
@@ -395,7 +499,9 @@ func (sched *Scheduler) schedulerPod(pod *v1.Pod) error {
 	// This allows us to keep scheduling without waiting on binding to occur.
 	assumedPod := *pod
 	// assume modifies `assumedPod` by setting NodeName=suggestedHost
-	sched.assume(&assumedPod, suggestedHost)
+	if err := sched.assume(&assumedPod, suggestedHost); err != nil {
+		return err
+	}
 
 	pod.Spec.NodeName = suggestedHost
 	return nil
@@ -407,19 +513,175 @@ func (sched *Scheduler) readyToScheduler(group *schedulerapi.SchedulingGroup) bo
 		return false
 	}
 	for _, rb := range group.Resources {
-		if rb.PendingPodCount != rb.Max {
+		// Pods of a role bound in a previous attempt are no longer pending.
+		if rb.Bound {
+			continue
+		}
+		if rb.PendingPodCount+rb.BoundPodCount != rb.Max {
 			result = false
 		}
 	}
 	return result
 }
 
+// checkSchedulerNames returns an error if a member of the group is to be scheduled by another
+// scheduler. The group can't be placed then, neither scheduler places all of its members.
+func checkSchedulerNames(group *schedulerapi.SchedulingGroup) error {
+	for _, rb := range group.Resources {
+		for _, pod := range rb.PendingPods {
+			if pod.Spec.SchedulerName != group.SchedulerName {
+				return fmt.Errorf("pod %s/%s is to be scheduled by %q, other members of group %s by %q",
+					pod.Namespace, pod.Name, pod.Spec.SchedulerName, group.Group, group.SchedulerName)
+			}
+		}
+	}
+	return nil
+}
+
 func (sched *Scheduler) releaseResources(group *schedulerapi.SchedulingGroup) {
+	pods := make([]*v1.Pod, 0, len(group.Status.PodsToBind))
 	for _, pod := range group.Status.PodsToBind {
-		error := sched.config.SchedulerCache.ForgetPod(pod)
+		pods = append(pods, pod)
+	}
+	if err := sched.forget(pods...); err != nil {
+		glog.Warningf("Failed to forget pods of group %s: %v", group.Group, err)
+	}
+	for _, pod := range pods {
+		delete(group.Status.PodsToBind, pod.Name)
+		// The pod is pending again, it must not stick to the node of this attempt.
+		pod.Spec.NodeName = ""
+	}
+}
+
+// placeRoles places Min members of each role, then as many of the remaining pending pods
+// as fit, on the nodes listed by nodeLister. If expand is not nil, it is called when a pod
+// doesn't fit to make more nodes available; the pod is retried as long as it returns true.
+// On error the pods placed so far are left assumed and in PodsToBind.
+func (sched *Scheduler) placeRoles(group *schedulerapi.SchedulingGroup, roles []*schedulerapi.ResourceObject, nodeLister algorithm.NodeLister, expand func() bool) error {
+	for _, rb := range roles {
+		var err error
+		if rb.Accelerator == nil || len(rb.Accelerator.Types) == 0 {
+			err = sched.placeRole(group, rb, nodeLister, expand)
+		} else {
+			// Try the accepted accelerator types in order of preference, all members on one type.
+			for _, acceleratorType := range rb.Accelerator.Types {
+				acceleratorLister := &domainNodeLister{
+					NodeLister: nodeLister,
+					key:        rb.Accelerator.Label,
+					domains:    sets.NewString(acceleratorType),
+				}
+				if err = sched.placeRole(group, rb, acceleratorLister, expand); err == nil {
+					break
+				}
+				glog.V(4).Infof("Role %s of group %s doesn't fit on accelerator %s: %v", rb.Role, group.Group, acceleratorType, err)
+			}
+		}
+		if err != nil {
+			return fmt.Errorf("role %s: %v", rb.Role, err)
+		}
+	}
+
+	pods := tools.SortOtherPendingPods(group, roles)
+
+	for _, pod := range pods {
+		err := sched.schedulerPod(group, pod, nodeLister)
+		if err != nil {
+			break
+		}
+		group.Status.PodsToBind[pod.Name] = pod
+	}
+	return nil
+}
+
+// placeRole places Min members of the role, less the members bound in previous attempts, on
+// the nodes listed by nodeLister, calling expand as placeRoles does. If the role doesn't fit,
+// the members placed so far are forgotten again.
+func (sched *Scheduler) placeRole(group *schedulerapi.SchedulingGroup, rb *schedulerapi.ResourceObject, nodeLister algorithm.NodeLister, expand func() bool) error {
+	min := rb.Min - rb.BoundPodCount
+	if min < 0 {
+		min = 0
+	}
+	placed := make([]*v1.Pod, 0, min)
+	var err error
+	for _, pod := range rb.PendingPods {
+		if len(placed) == min {
+			break
+		}
+		for {
+			err = sched.schedulerPod(group, pod, nodeLister)
+			if err == nil || expand == nil || !expand() {
+				break
+			}
+		}
+		if err != nil {
+			break
+		}
+		group.Status.PodsToBind[pod.Name] = pod
+		placed = append(placed, pod)
+	}
+	if len(placed) == min {
+		return nil
+	}
+	if err == nil {
+		err = fmt.Errorf("only %d of %d pods are pending", len(placed), min)
+	}
+	if forgetErr := sched.forget(placed...); forgetErr != nil {
+		glog.Warningf("Failed to forget pods of role %s of group %s: %v", rb.Role, group.Group, forgetErr)
+	}
+	for _, pod := range placed {
 		delete(group.Status.PodsToBind, pod.Name)
-		if error != nil {
-			glog.Warningf("Failed to forget pod %s%s.", pod.Namespace, pod.Name)
+		pod.Spec.NodeName = ""
+	}
+	return err
+}
+
+// deferredRoles returns the roles which can't be placed in this attempt, because a role they
+// depend on has to be started first, or is deferred itself. roles must be in placement order.
+func (sched *Scheduler) deferredRoles(group *schedulerapi.SchedulingGroup, roles []*schedulerapi.ResourceObject) map[string]bool {
+	deferred := make(map[string]bool)
+	byName := make(map[string]*schedulerapi.ResourceObject, len(roles))
+	for _, rb := range roles {
+		byName[rb.Role] = rb
+	}
+	var members []tools.GroupMember
+	listed := false
+	for _, rb := range roles {
+		if rb.Bound {
+			continue
+		}
+		for _, dep := range rb.DependsOn {
+			if deferred[dep.Role] {
+				deferred[rb.Role] = true
+				break
+			}
+			if dep.Type != schedulerapi.DependencyStarted {
+				continue
+			}
+			target := byName[dep.Role]
+			if !target.Bound {
+				deferred[rb.Role] = true
+				break
+			}
+			if !listed {
+				nodeNameToInfo := make(map[string]*schedulercache.NodeInfo)
+				if err := sched.config.SchedulerCache.UpdateNodeNameToInfoMap(nodeNameToInfo); err != nil {
+					glog.Errorf("Failed to get the cached nodes: %v", err)
+				}
+				members = tools.GetGroupMembers(group.Group, nodeNameToInfo)
+				listed = true
+			}
+			running := 0
+			for _, member := range members {
+				if member.Role == dep.Role && member.Pod.Status.Phase == v1.PodRunning {
+					running++
+				}
+			}
+			if running < target.Min {
+				glog.V(3).Infof("Role %s of group %s waits for %d/%d running pods of role %s", rb.Role, group.Group, running, target.Min, dep.Role)
+				deferred[rb.Role] = true
+				break
+			}
 		}
 	}
+	return deferred
 }
@@ -17,6 +17,7 @@ limitations under the License.
 package scheduler
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"reflect"
@@ -36,9 +37,11 @@ import (
 	"k8s.io/kubernetes/pkg/api/v1"
 	"k8s.io/kubernetes/plugin/pkg/scheduler/algorithm"
 	"k8s.io/kubernetes/plugin/pkg/scheduler/algorithm/predicates"
+	schedulerapi "k8s.io/kubernetes/plugin/pkg/scheduler/api"
 	"k8s.io/kubernetes/plugin/pkg/scheduler/core"
 	"k8s.io/kubernetes/plugin/pkg/scheduler/schedulercache"
 	schedulertesting "k8s.io/kubernetes/plugin/pkg/scheduler/testing"
+	"k8s.io/kubernetes/plugin/pkg/scheduler/tools"
 )
 
 type fakeBinder struct {
@@ -195,7 +198,7 @@ func TestScheduler(t *testing.T) {
 			}
 			close(called)
 		})
-		s.scheduleOne()
+		s.schedulePod()
 		<-called
 		if e, a := item.expectAssumedPod, gotAssumedPod; !reflect.DeepEqual(e, a) {
 			t.Errorf("%v: assumed pod: wanted %v, got %v", i, e, a)
@@ -259,7 +262,7 @@ func TestSchedulerNoPhantomPodAfterExpire(t *testing.T) {
 	// We use conflicted pod ports to incur fit predicate failure if first pod not removed.
 	secondPod := podWithPort("bar", "", 8080)
 	queuedPodStore.Add(secondPod)
-	scheduler.scheduleOne()
+	scheduler.schedulePod()
 	select {
 	case b := <-bindingChan:
 		expectBinding := &v1.Binding{
@@ -292,7 +295,7 @@ func TestSchedulerNoPhantomPodAfterDelete(t *testing.T) {
 	// queuedPodStore: [bar:8080]
 	// cache: [(assumed)foo:8080]
 
-	scheduler.scheduleOne()
+	scheduler.schedulePod()
 	select {
 	case err := <-errChan:
 		expectErr := &core.FitError{
@@ -319,7 +322,7 @@ func TestSchedulerNoPhantomPodAfterDelete(t *testing.T) {
 	}
 
 	queuedPodStore.Add(secondPod)
-	scheduler.scheduleOne()
+	scheduler.schedulePod()
 	select {
 	case b := <-bindingChan:
 		expectBinding := &v1.Binding{
@@ -404,7 +407,7 @@ func setupTestSchedulerWithOnePodOnNode(t *testing.T, queuedPodStore *clientcach
 	// queuedPodStore: [foo:8080]
 	// cache: []
 
-	scheduler.scheduleOne()
+	scheduler.schedulePod()
 	// queuedPodStore: []
 	// cache: [(assumed)foo:8080]
 
@@ -476,7 +479,7 @@ func TestSchedulerFailedSchedulingReasons(t *testing.T) {
 	scheduler, _, errChan := setupTestScheduler(queuedPodStore, scache, nodeLister, predicateMap)
 
 	queuedPodStore.Add(podWithTooBigResourceRequests)
-	scheduler.scheduleOne()
+	scheduler.schedulePod()
 	select {
 	case err := <-errChan:
 		expectErr := &core.FitError{
@@ -504,7 +507,9 @@ func setupTestScheduler(queuedPodStore *clientcache.FIFO, scache schedulercache.
 		algorithm.EmptyMetadataProducer,
 		[]algorithm.PriorityConfig{},
 		algorithm.EmptyMetadataProducer,
-		[]algorithm.SchedulerExtender{})
+		[]algorithm.SchedulerExtender{},
+		core.NodeSampling{},
+		core.PredicateEvaluation{})
 	bindingChan := make(chan *v1.Binding, 1)
 	errChan := make(chan error, 1)
 	configurator := &FakeConfigurator{
@@ -540,7 +545,9 @@ func setupTestSchedulerLongBindingWithRetry(queuedPodStore *clientcache.FIFO, sc
 		algorithm.EmptyMetadataProducer,
 		[]algorithm.PriorityConfig{},
 		algorithm.EmptyMetadataProducer,
-		[]algorithm.SchedulerExtender{})
+		[]algorithm.SchedulerExtender{},
+		core.NodeSampling{},
+		core.PredicateEvaluation{})
 	bindingChan := make(chan *v1.Binding, 2)
 	configurator := &FakeConfigurator{
 		Config: &Config{
@@ -571,3 +578,91 @@ func setupTestSchedulerLongBindingWithRetry(queuedPodStore *clientcache.FIFO, sc
 
 	return sched, bindingChan
 }
+
+func TestCheckSchedulerNames(t *testing.T) {
+	member := func(name, schedulerName string) *v1.Pod {
+		pod := podWithID(name, "")
+		pod.Spec.SchedulerName = schedulerName
+		return pod
+	}
+	tests := []struct {
+		name      string
+		pods      []*v1.Pod
+		expectErr bool
+	}{
+		{name: "members agree", pods: []*v1.Pod{member("worker-0", "gang-scheduler"), member("worker-1", "gang-scheduler")}},
+		{name: "members disagree", pods: []*v1.Pod{member("worker-0", "gang-scheduler"), member("worker-1", v1.DefaultSchedulerName)}, expectErr: true},
+	}
+	for _, test := range tests {
+		role := &schedulerapi.ResourceObject{Role: "worker", PendingPods: map[string]*v1.Pod{}}
+		for _, pod := range test.pods {
+			role.PendingPods[pod.Name] = pod
+		}
+		group := &schedulerapi.SchedulingGroup{Group: "foo/gang", SchedulerName: "gang-scheduler", Resources: []*schedulerapi.ResourceObject{role}}
+		if err := checkSchedulerNames(group); (err != nil) != test.expectErr {
+			t.Errorf("%s: expected error %v, got %v", test.name, test.expectErr, err)
+		}
+	}
+}
+
+func TestDeferredRoles(t *testing.T) {
+	member := func(name, role, node string, phase v1.PodPhase) *v1.Pod {
+		data, _ := json.Marshal(schedulerapi.MiniGroup{Group: "foo/gang", Role: role})
+		pod := podWithID(name, node)
+		pod.Annotations = map[string]string{tools.SchedulingGroup: string(data)}
+		pod.Status.Phase = phase
+		return pod
+	}
+	started := []schedulerapi.RoleDependency{{Role: "ps", Type: schedulerapi.DependencyStarted}}
+	scheduled := []schedulerapi.RoleDependency{{Role: "worker", Type: schedulerapi.DependencyScheduled}}
+	tests := []struct {
+		name     string
+		psBound  bool
+		pods     []*v1.Pod
+		expected map[string]bool
+	}{
+		{
+			name:     "dependency not bound",
+			expected: map[string]bool{"worker": true, "evaluator": true},
+		},
+		{
+			name:     "dependency bound but not started",
+			psBound:  true,
+			pods:     []*v1.Pod{member("ps-0", "ps", "machine1", v1.PodRunning), member("ps-1", "ps", "machine1", v1.PodPending)},
+			expected: map[string]bool{"worker": true, "evaluator": true},
+		},
+		{
+			name:     "dependency started",
+			psBound:  true,
+			pods:     []*v1.Pod{member("ps-0", "ps", "machine1", v1.PodRunning), member("ps-1", "ps", "machine1", v1.PodRunning)},
+			expected: map[string]bool{},
+		},
+		{
+			name:     "other group started",
+			psBound:  true,
+			pods:     []*v1.Pod{member("ps-0", "ps", "machine1", v1.PodRunning), podWithID("ps-1", "machine1")},
+			expected: map[string]bool{"worker": true, "evaluator": true},
+		},
+	}
+	for _, test := range tests {
+		stop := make(chan struct{})
+		scache := schedulercache.New(10*time.Minute, stop)
+		scache.AddNode(&v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "machine1"}})
+		for _, pod := range test.pods {
+			if err := scache.AddPod(pod); err != nil {
+				t.Fatalf("%s: AddPod failed: %v", test.name, err)
+			}
+		}
+		sched := &Scheduler{config: &Config{SchedulerCache: scache}}
+		roles := []*schedulerapi.ResourceObject{
+			{Role: "ps", Min: 2, Bound: test.psBound},
+			{Role: "worker", Min: 1, DependsOn: started},
+			{Role: "evaluator", Min: 1, DependsOn: scheduled},
+		}
+		group := &schedulerapi.SchedulingGroup{Group: "foo/gang", Resources: roles}
+		if deferred := sched.deferredRoles(group, roles); !reflect.DeepEqual(deferred, test.expected) {
+			t.Errorf("%s: expected %v deferred, got %v", test.name, test.expected, deferred)
+		}
+		close(stop)
+	}
+}
@@ -54,6 +54,8 @@ type schedulerCache struct {
 	// a map from pod key to podState.
 	podStates map[string]*podState
 	nodes     map[string]*NodeInfo
+	// the last unit of assumed pods handed out.
+	lastUnit int64
 }
 
 type podState struct {
@@ -62,6 +64,9 @@ type podState struct {
 	deadline *time.Time
 	// Used to block cache from expiring assumedPod if binding still runs
 	bindingFinished bool
+	// The assumed pods of a unit, e.g. the members of a gang, are expired together.
+	// Zero if the pod is expired on its own.
+	unit int64
 }
 
 func newSchedulerCache(ttl, period time.Duration, stop <-chan struct{}) *schedulerCache {
@@ -180,6 +185,120 @@ func (cache *schedulerCache) ForgetPod(pod *v1.Pod) error {
 	return nil
 }
 
+func (cache *schedulerCache) AssumePods(pods []*v1.Pod) error {
+	keys, err := getPodKeys(pods)
+	if err != nil {
+		return err
+	}
+
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	return cache.assumePods(keys, pods)
+}
+
+func (cache *schedulerCache) CommitPods(pods []*v1.Pod, generations map[string]int64) error {
+	keys, err := getPodKeys(pods)
+	if err != nil {
+		return err
+	}
+
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	// Check all nodes before assuming any pod, each pod assumed changes its node.
+	for _, pod := range pods {
+		generation, ok := generations[pod.Spec.NodeName]
+		n, found := cache.nodes[pod.Spec.NodeName]
+		if !ok || !found || n.generation != generation {
+			return &ConflictError{Node: pod.Spec.NodeName}
+		}
+	}
+	return cache.assumePods(keys, pods)
+}
+
+// Assumes that lock is already acquired.
+func (cache *schedulerCache) assumePods(keys []string, pods []*v1.Pod) error {
+	// Check all pods before assuming any of them.
+	assuming := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		if _, ok := cache.podStates[key]; ok || assuming[key] {
+			return fmt.Errorf("pod %v state wasn't initial but get assumed", key)
+		}
+		assuming[key] = true
+	}
+
+	cache.lastUnit++
+	for i, pod := range pods {
+		cache.addPod(pod)
+		cache.podStates[keys[i]] = &podState{
+			pod:  pod,
+			unit: cache.lastUnit,
+		}
+		cache.assumedPods[keys[i]] = true
+	}
+	return nil
+}
+
+func (cache *schedulerCache) FinishBindings(pods []*v1.Pod) error {
+	return cache.finishBindings(pods, time.Now())
+}
+
+// finishBindings exists to make tests determinitistic by injecting now as an argument
+func (cache *schedulerCache) finishBindings(pods []*v1.Pod, now time.Time) error {
+	keys, err := getPodKeys(pods)
+	if err != nil {
+		return err
+	}
+
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	glog.V(5).Infof("Finished binding for %d pods. Can be expired.", len(keys))
+	// Pods committed together keep their unit, pods assumed one by one become one.
+	cache.lastUnit++
+	dl := now.Add(cache.ttl)
+	for _, key := range keys {
+		currState, ok := cache.podStates[key]
+		if ok && cache.assumedPods[key] {
+			currState.bindingFinished = true
+			currState.deadline = &dl
+			if currState.unit == 0 {
+				currState.unit = cache.lastUnit
+			}
+		}
+	}
+	return nil
+}
+
+func (cache *schedulerCache) ForgetPods(pods []*v1.Pod) error {
+	keys, err := getPodKeys(pods)
+	if err != nil {
+		return err
+	}
+
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	// Check all pods before forgetting any of them.
+	for i, key := range keys {
+		currState, ok := cache.podStates[key]
+		if ok && currState.pod.Spec.NodeName != pods[i].Spec.NodeName {
+			return fmt.Errorf("pod %v state was assumed on a different node", key)
+		}
+		// Only assumed pod can be forgotten.
+		if !ok || !cache.assumedPods[key] {
+			return fmt.Errorf("pod %v state wasn't assumed but get forgotten", key)
+		}
+	}
+	for i, key := range keys {
+		if err := cache.removePod(pods[i]); err != nil {
+			return err
+		}
+		delete(cache.assumedPods, key)
+		delete(cache.podStates, key)
+	}
+	return nil
+}
+
 // Assumes that lock is already acquired.
 func (cache *schedulerCache) addPod(pod *v1.Pod) {
 	n, ok := cache.nodes[pod.Spec.NodeName]
@@ -356,12 +475,36 @@ func (cache *schedulerCache) cleanupAssumedPods(now time.Time) {
 	cache.mu.Lock()
 	defer cache.mu.Unlock()
 
+	// A unit is expired once the binding of all its assumed pods finished and all their
+	// deadlines passed.
+	expiredUnits := make(map[int64]bool)
+	for key := range cache.assumedPods {
+		ps, ok := cache.podStates[key]
+		if !ok {
+			panic("Key found in assumed set but not in podStates. Potentially a logical error.")
+		}
+		if ps.unit == 0 {
+			continue
+		}
+		expired, seen := expiredUnits[ps.unit]
+		expiredUnits[ps.unit] = (expired || !seen) && ps.bindingFinished && now.After(*ps.deadline)
+	}
+
 	// The size of assumedPods should be small
 	for key := range cache.assumedPods {
 		ps, ok := cache.podStates[key]
 		if !ok {
 			panic("Key found in assumed set but not in podStates. Potentially a logical error.")
 		}
+		if ps.unit != 0 {
+			if expiredUnits[ps.unit] {
+				glog.Warningf("Pod %s/%s expired with its unit", ps.pod.Namespace, ps.pod.Name)
+				if err := cache.expirePod(key, ps); err != nil {
+					glog.Errorf("ExpirePod failed for %s: %v", key, err)
+				}
+			}
+			continue
+		}
 		if !ps.bindingFinished {
 			glog.Warningf("Couldn't expire cache for pod %v/%v. Binding is still in progress.",
 				ps.pod.Namespace, ps.pod.Name)
@@ -507,6 +507,152 @@ func TestForgetPod(t *testing.T) {
 	}
 }
 
+// commitPods commits the pods as if they were placed on a snapshot of the current nodes.
+func commitPods(cache *schedulerCache, pods []*v1.Pod) error {
+	generations := map[string]int64{}
+	for _, pod := range pods {
+		if n, ok := cache.nodes[pod.Spec.NodeName]; ok {
+			generations[pod.Spec.NodeName] = n.generation
+		}
+	}
+	return cache.CommitPods(pods, generations)
+}
+
+// TestCommitPodsIsAtomic tests that a batch of pods is not assumed at all if one of them
+// is already known to the cache.
+func TestCommitPodsIsAtomic(t *testing.T) {
+	nodeName := "node"
+	pods := []*v1.Pod{
+		makeBasePod(nodeName, "test-1", "100m", "500", nil),
+		makeBasePod(nodeName, "test-2", "100m", "500", nil),
+	}
+	cache := newSchedulerCache(10*time.Second, time.Second, nil)
+	if err := cache.AssumePod(pods[1]); err != nil {
+		t.Fatalf("AssumePod failed: %v", err)
+	}
+	if err := commitPods(cache, pods); err == nil {
+		t.Errorf("expected CommitPods to fail on an already assumed pod")
+	}
+	if _, ok := cache.podStates[fmt.Sprintf("%s/%s", pods[0].Namespace, pods[0].Name)]; ok {
+		t.Errorf("expected pod %s not to be assumed", pods[0].Name)
+	}
+	if n := cache.nodes[nodeName]; len(n.pods) != 1 {
+		t.Errorf("expected only pod %s on the node, get=%s", pods[1].Name, n)
+	}
+
+	if err := commitPods(cache, []*v1.Pod{pods[0], pods[0]}); err == nil {
+		t.Errorf("expected CommitPods to fail on a duplicated pod")
+	}
+}
+
+// TestAssumePodsIsAtomic tests that a batch of pods is not assumed at all if one of them
+// is already known to the cache, and that the pods assumed together expire together.
+func TestAssumePodsIsAtomic(t *testing.T) {
+	nodeName := "node"
+	pods := []*v1.Pod{
+		makeBasePod(nodeName, "test-1", "100m", "500", nil),
+		makeBasePod(nodeName, "test-2", "100m", "500", nil),
+	}
+	cache := newSchedulerCache(10*time.Second, time.Second, nil)
+	if err := cache.AssumePod(pods[1]); err != nil {
+		t.Fatalf("AssumePod failed: %v", err)
+	}
+	if err := cache.AssumePods(pods); err == nil {
+		t.Errorf("expected AssumePods to fail on an already assumed pod")
+	}
+	if n := cache.nodes[nodeName]; len(n.pods) != 1 {
+		t.Errorf("expected only pod %s on the node, get=%s", pods[1].Name, n)
+	}
+
+	if err := cache.ForgetPod(pods[1]); err != nil {
+		t.Fatalf("ForgetPod failed: %v", err)
+	}
+	if err := cache.AssumePods(pods); err != nil {
+		t.Fatalf("AssumePods failed: %v", err)
+	}
+	if n := cache.nodes[nodeName]; len(n.pods) != 2 {
+		t.Errorf("expected both pods on the node, get=%s", n)
+	}
+	first := cache.podStates[fmt.Sprintf("%s/%s", pods[0].Namespace, pods[0].Name)]
+	second := cache.podStates[fmt.Sprintf("%s/%s", pods[1].Namespace, pods[1].Name)]
+	if first.unit == 0 || first.unit != second.unit {
+		t.Errorf("expected the pods to be assumed as a unit, got units %d and %d", first.unit, second.unit)
+	}
+}
+
+// TestForgetPodsIsAtomic tests that a batch of pods is not forgotten at all if one of them
+// wasn't assumed.
+func TestForgetPodsIsAtomic(t *testing.T) {
+	nodeName := "node"
+	pods := []*v1.Pod{
+		makeBasePod(nodeName, "test-1", "100m", "500", nil),
+		makeBasePod(nodeName, "test-2", "100m", "500", nil),
+	}
+	cache := newSchedulerCache(10*time.Second, time.Second, nil)
+	if err := cache.AssumePod(pods[0]); err != nil {
+		t.Fatalf("AssumePod failed: %v", err)
+	}
+	if err := cache.ForgetPods(pods); err == nil {
+		t.Errorf("expected ForgetPods to fail on a pod which wasn't assumed")
+	}
+	if n := cache.nodes[nodeName]; n == nil || len(n.pods) != 1 {
+		t.Errorf("expected pod %s to stay on the node, get=%s", pods[0].Name, n)
+	}
+
+	if err := commitPods(cache, pods[1:]); err != nil {
+		t.Fatalf("CommitPods failed: %v", err)
+	}
+	if err := cache.ForgetPods(pods); err != nil {
+		t.Fatalf("ForgetPods failed: %v", err)
+	}
+	if n := cache.nodes[nodeName]; n != nil {
+		t.Errorf("expecting pods deleted and nil node info, get=%s", n)
+	}
+}
+
+// TestExpirePodsTogether tests that the pods whose bindings are finished together expire
+// together, and only once the binding of all of them is finished.
+func TestExpirePodsTogether(t *testing.T) {
+	nodeName := "node"
+	pods := []*v1.Pod{
+		makeBasePod(nodeName, "test-1", "100m", "500", nil),
+		makeBasePod(nodeName, "test-2", "100m", "500", nil),
+	}
+	now := time.Now()
+	ttl := 10 * time.Second
+	cache := newSchedulerCache(ttl, time.Second, nil)
+	if err := cache.AddNode(&v1.Node{ObjectMeta: metav1.ObjectMeta{Name: nodeName}}); err != nil {
+		t.Fatalf("AddNode failed: %v", err)
+	}
+	if err := commitPods(cache, pods); err != nil {
+		t.Fatalf("CommitPods failed: %v", err)
+	}
+	if err := cache.finishBindings(pods[:1], now); err != nil {
+		t.Fatalf("finishBindings failed: %v", err)
+	}
+	// The second pod is still binding, so its unit must not expire.
+	cache.cleanupAssumedPods(now.Add(2 * ttl))
+	if n := cache.nodes[nodeName]; n == nil || len(n.pods) != 2 {
+		t.Fatalf("expected both pods to stay on the node, get=%s", n)
+	}
+
+	if err := cache.finishBindings(pods, now); err != nil {
+		t.Fatalf("finishBindings failed: %v", err)
+	}
+	// The first pod is confirmed, only the other one expires.
+	if err := cache.AddPod(pods[0]); err != nil {
+		t.Fatalf("AddPod failed: %v", err)
+	}
+	cache.cleanupAssumedPods(now.Add(ttl / 2))
+	if n := cache.nodes[nodeName]; n == nil || len(n.pods) != 2 {
+		t.Fatalf("expected both pods to stay on the node before the deadline, get=%s", n)
+	}
+	cache.cleanupAssumedPods(now.Add(2 * ttl))
+	if n := cache.nodes[nodeName]; n == nil || len(n.pods) != 1 || n.pods[0].Name != pods[0].Name {
+		t.Errorf("expected only the confirmed pod %s on the node, get=%s", pods[0].Name, n)
+	}
+}
+
 // addResource adds ResourceList into Resource.
 func addResource(r *Resource, rl v1.ResourceList) {
 	if r == nil {
@@ -67,6 +67,23 @@ type Cache interface {
 	// ForgetPod removes an assumed pod from cache.
 	ForgetPod(pod *v1.Pod) error
 
+	// AssumePods assumes the pods of a gang at once: all of them, or none if one of them
+	// can't be assumed. The pods are expired together as a unit.
+	AssumePods(pods []*v1.Pod) error
+
+	// CommitPods assumes the pods of a gang placed on a snapshot like AssumePods, if the nodes
+	// they are assumed on still have the given generations, i.e. didn't change since.
+	// Otherwise none of them is assumed and a *ConflictError is returned.
+	CommitPods(pods []*v1.Pod, generations map[string]int64) error
+
+	// FinishBindings signals that cache for the assumed pods can be expired, which happens
+	// to all of them together as a unit.
+	FinishBindings(pods []*v1.Pod) error
+
+	// ForgetPods removes the assumed pods from cache at once. Either all pods are forgotten
+	// or, if one of them wasn't assumed, none of them.
+	ForgetPods(pods []*v1.Pod) error
+
 	// AddPod either confirms a pod if it's assumed, or adds it back if it's expired.
 	// If added back, the pod's information would be added again.
 	AddPod(pod *v1.Pod) error
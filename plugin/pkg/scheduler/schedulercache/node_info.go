@@ -18,6 +18,7 @@ package schedulercache
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/golang/glog"
 
@@ -30,6 +31,9 @@ import (
 
 var emptyResource = Resource{}
 
+// hugePagesPrefix is the prefix of the huge page resources, e.g. hugepages-2Mi.
+const hugePagesPrefix = "hugepages-"
+
 // NodeInfo is node level aggregated information.
 type NodeInfo struct {
 	// Overall node information.
@@ -72,6 +76,18 @@ type Resource struct {
 	StorageScratch     int64
 	StorageOverlay     int64
 	OpaqueIntResources map[v1.ResourceName]int64
+	// ExtendedResources are the resources named by IsExtendedResourceName.
+	ExtendedResources map[v1.ResourceName]int64
+}
+
+// IsExtendedResourceName returns true for the resources counted by name in ExtendedResources:
+// huge pages and the resources with a fully-qualified name outside the kubernetes.io domain,
+// e.g. vendor.com/rdma. Opaque integer resources are kept in OpaqueIntResources.
+func IsExtendedResourceName(name v1.ResourceName) bool {
+	if strings.HasPrefix(string(name), hugePagesPrefix) {
+		return true
+	}
+	return strings.Contains(string(name), "/") && !strings.Contains(string(name), "kubernetes.io/")
 }
 
 func (r *Resource) ResourceList() v1.ResourceList {
@@ -85,6 +101,13 @@ func (r *Resource) ResourceList() v1.ResourceList {
 	for rName, rQuant := range r.OpaqueIntResources {
 		result[rName] = *resource.NewQuantity(rQuant, resource.DecimalSI)
 	}
+	for rName, rQuant := range r.ExtendedResources {
+		if strings.HasPrefix(string(rName), hugePagesPrefix) {
+			result[rName] = *resource.NewQuantity(rQuant, resource.BinarySI)
+		} else {
+			result[rName] = *resource.NewQuantity(rQuant, resource.DecimalSI)
+		}
+	}
 	return result
 }
 
@@ -102,6 +125,12 @@ func (r *Resource) Clone() *Resource {
 			res.OpaqueIntResources[k] = v
 		}
 	}
+	if r.ExtendedResources != nil {
+		res.ExtendedResources = make(map[v1.ResourceName]int64)
+		for k, v := range r.ExtendedResources {
+			res.ExtendedResources[k] = v
+		}
+	}
 	return res
 }
 
@@ -117,6 +146,18 @@ func (r *Resource) SetOpaque(name v1.ResourceName, quantity int64) {
 	r.OpaqueIntResources[name] = quantity
 }
 
+func (r *Resource) AddExtended(name v1.ResourceName, quantity int64) {
+	r.SetExtended(name, r.ExtendedResources[name]+quantity)
+}
+
+func (r *Resource) SetExtended(name v1.ResourceName, quantity int64) {
+	// Lazily allocate extended resource map.
+	if r.ExtendedResources == nil {
+		r.ExtendedResources = map[v1.ResourceName]int64{}
+	}
+	r.ExtendedResources[name] = quantity
+}
+
 // NewNodeInfo returns a ready to use empty NodeInfo object.
 // If any pods are given in arguments, their information will be aggregated in
 // the returned object.
@@ -276,6 +317,9 @@ func (n *NodeInfo) addPod(pod *v1.Pod) {
 	for rName, rQuant := range res.OpaqueIntResources {
 		n.requestedResource.OpaqueIntResources[rName] += rQuant
 	}
+	for rName, rQuant := range res.ExtendedResources {
+		n.requestedResource.AddExtended(rName, rQuant)
+	}
 	n.nonzeroRequest.MilliCPU += non0_cpu
 	n.nonzeroRequest.Memory += non0_mem
 	n.pods = append(n.pods, pod)
@@ -333,6 +377,9 @@ func (n *NodeInfo) removePod(pod *v1.Pod) error {
 			for rName, rQuant := range res.OpaqueIntResources {
 				n.requestedResource.OpaqueIntResources[rName] -= rQuant
 			}
+			for rName, rQuant := range res.ExtendedResources {
+				n.requestedResource.AddExtended(rName, -rQuant)
+			}
 			n.nonzeroRequest.MilliCPU -= non0_cpu
 			n.nonzeroRequest.Memory -= non0_mem
 
@@ -365,6 +412,8 @@ func calculateResource(pod *v1.Pod) (res Resource, non0_cpu int64, non0_mem int6
 			default:
 				if v1helper.IsOpaqueIntResourceName(rName) {
 					res.AddOpaque(rName, rQuant.Value())
+				} else if IsExtendedResourceName(rName) {
+					res.AddExtended(rName, rQuant.Value())
 				}
 			}
 		}
@@ -421,6 +470,8 @@ func (n *NodeInfo) SetNode(node *v1.Node) error {
 		default:
 			if v1helper.IsOpaqueIntResourceName(rName) {
 				n.allocatableResource.SetOpaque(rName, rQuant.Value())
+			} else if IsExtendedResourceName(rName) {
+				n.allocatableResource.SetExtended(rName, rQuant.Value())
 			}
 		}
 	}
@@ -460,3 +511,16 @@ func (n *NodeInfo) RemoveNode(node *v1.Node) error {
 func getPodKey(pod *v1.Pod) (string, error) {
 	return clientcache.MetaNamespaceKeyFunc(pod)
 }
+
+// getPodKeys returns the keys of the pods, in the same order.
+func getPodKeys(pods []*v1.Pod) ([]string, error) {
+	keys := make([]string, 0, len(pods))
+	for _, pod := range pods {
+		key, err := getPodKey(pod)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
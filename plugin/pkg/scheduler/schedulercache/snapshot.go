@@ -0,0 +1,261 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schedulercache
+
+import (
+	"fmt"
+	"sort"
+
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/kubernetes/pkg/api/v1"
+)
+
+// ConflictError is returned by CommitPods if a node changed since the pods were placed on it.
+type ConflictError struct {
+	Node string
+}
+
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("node %q changed since the pods were placed on it", e.Node)
+}
+
+// Snapshot is a copy of the nodes of a cache which pods are assumed on without changing the
+// cache. A worker places the members of a group on its own snapshot, then commits them to the
+// cache at once. The commit fails if a node they are placed on changed in the cache meanwhile,
+// e.g. because another worker committed pods to it.
+//
+// Pod and node events and the bindings are passed on to the cache, they show in the snapshot
+// once it is updated. Pods which are not assumed on the snapshot are forgotten in the cache.
+// A snapshot is not safe for concurrent use.
+type Snapshot struct {
+	cache Cache
+	// nodes are the nodes of the cache when the snapshot was updated, with the pods assumed
+	// on the snapshot. A node is replaced rather than changed, so the nodes handed out by
+	// UpdateNodeNameToInfoMap stay as they are.
+	nodes map[string]*NodeInfo
+	// generations are the generations of the nodes in the cache when the snapshot was updated.
+	generations map[string]int64
+	// assumed are the pods assumed on the snapshot and not committed yet, by key.
+	assumed map[string]*v1.Pod
+	// changed are the nodes which pods were assumed on or forgotten from since the update.
+	changed map[string]bool
+}
+
+// NewSnapshot returns an empty snapshot of the cache, it is filled by Update.
+func NewSnapshot(cache Cache) *Snapshot {
+	return &Snapshot{
+		cache:       cache,
+		nodes:       make(map[string]*NodeInfo),
+		generations: make(map[string]int64),
+		assumed:     make(map[string]*v1.Pod),
+		changed:     make(map[string]bool),
+	}
+}
+
+// Update discards the pods assumed on the snapshot and takes over the nodes which changed in
+// the cache since the last update.
+func (s *Snapshot) Update() error {
+	// The generations of changed nodes may match the cache by chance, they are copied again.
+	for name := range s.changed {
+		delete(s.nodes, name)
+	}
+	s.changed = make(map[string]bool)
+	s.assumed = make(map[string]*v1.Pod)
+	if err := s.cache.UpdateNodeNameToInfoMap(s.nodes); err != nil {
+		return err
+	}
+	s.generations = make(map[string]int64, len(s.nodes))
+	for name, n := range s.nodes {
+		s.generations[name] = n.generation
+	}
+	return nil
+}
+
+// Commit assumes the pods assumed on the snapshot in the cache at once. It returns a
+// *ConflictError if a node they are assumed on changed in the cache since the update; the
+// snapshot is to be updated and the pods are to be placed again then.
+func (s *Snapshot) Commit() error {
+	if len(s.assumed) == 0 {
+		return nil
+	}
+	keys := make([]string, 0, len(s.assumed))
+	for key := range s.assumed {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	pods := make([]*v1.Pod, 0, len(keys))
+	generations := make(map[string]int64)
+	for _, key := range keys {
+		pod := s.assumed[key]
+		pods = append(pods, pod)
+		if generation, ok := s.generations[pod.Spec.NodeName]; ok {
+			generations[pod.Spec.NodeName] = generation
+		}
+	}
+	if err := s.cache.CommitPods(pods, generations); err != nil {
+		return err
+	}
+	s.assumed = make(map[string]*v1.Pod)
+	return nil
+}
+
+// change returns a copy of the node to change, which replaces the node in the snapshot.
+func (s *Snapshot) change(name string) *NodeInfo {
+	n, ok := s.nodes[name]
+	if ok {
+		n = n.Clone()
+	} else {
+		n = NewNodeInfo()
+	}
+	s.nodes[name] = n
+	s.changed[name] = true
+	return n
+}
+
+// remove removes the pod assumed on the snapshot.
+func (s *Snapshot) remove(key string, pod *v1.Pod) error {
+	if err := s.change(pod.Spec.NodeName).removePod(pod); err != nil {
+		return err
+	}
+	if n := s.nodes[pod.Spec.NodeName]; len(n.pods) == 0 && n.node == nil {
+		delete(s.nodes, pod.Spec.NodeName)
+	}
+	delete(s.assumed, key)
+	return nil
+}
+
+func (s *Snapshot) AssumePod(pod *v1.Pod) error {
+	return s.AssumePods([]*v1.Pod{pod})
+}
+
+func (s *Snapshot) FinishBinding(pod *v1.Pod) error {
+	return s.cache.FinishBinding(pod)
+}
+
+func (s *Snapshot) ForgetPod(pod *v1.Pod) error {
+	return s.ForgetPods([]*v1.Pod{pod})
+}
+
+// AssumePods assumes the pods on the snapshot, either all of them or none.
+func (s *Snapshot) AssumePods(pods []*v1.Pod) error {
+	keys, err := getPodKeys(pods)
+	if err != nil {
+		return err
+	}
+	// Check all pods before assuming any of them.
+	assuming := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		if _, ok := s.assumed[key]; ok || assuming[key] {
+			return fmt.Errorf("pod %v state wasn't initial but get assumed", key)
+		}
+		assuming[key] = true
+	}
+	for i, pod := range pods {
+		s.change(pod.Spec.NodeName).addPod(pod)
+		s.assumed[keys[i]] = pod
+	}
+	return nil
+}
+
+func (s *Snapshot) CommitPods(pods []*v1.Pod, generations map[string]int64) error {
+	return s.cache.CommitPods(pods, generations)
+}
+
+func (s *Snapshot) FinishBindings(pods []*v1.Pod) error {
+	return s.cache.FinishBindings(pods)
+}
+
+func (s *Snapshot) ForgetPods(pods []*v1.Pod) error {
+	keys, err := getPodKeys(pods)
+	if err != nil {
+		return err
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+	// Pods committed already are forgotten in the cache.
+	if _, ok := s.assumed[keys[0]]; !ok {
+		return s.cache.ForgetPods(pods)
+	}
+	// Check all pods before forgetting any of them.
+	for i, key := range keys {
+		assumed, ok := s.assumed[key]
+		if !ok {
+			return fmt.Errorf("pod %v state wasn't assumed but get forgotten", key)
+		}
+		if assumed.Spec.NodeName != pods[i].Spec.NodeName {
+			return fmt.Errorf("pod %v state was assumed on a different node", key)
+		}
+	}
+	for i, key := range keys {
+		if err := s.remove(key, pods[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Snapshot) AddPod(pod *v1.Pod) error {
+	return s.cache.AddPod(pod)
+}
+
+func (s *Snapshot) UpdatePod(oldPod, newPod *v1.Pod) error {
+	return s.cache.UpdatePod(oldPod, newPod)
+}
+
+func (s *Snapshot) RemovePod(pod *v1.Pod) error {
+	return s.cache.RemovePod(pod)
+}
+
+func (s *Snapshot) AddNode(node *v1.Node) error {
+	return s.cache.AddNode(node)
+}
+
+func (s *Snapshot) UpdateNode(oldNode, newNode *v1.Node) error {
+	return s.cache.UpdateNode(oldNode, newNode)
+}
+
+func (s *Snapshot) RemoveNode(node *v1.Node) error {
+	return s.cache.RemoveNode(node)
+}
+
+// UpdateNodeNameToInfoMap hands out the nodes of the snapshot themselves, they aren't changed.
+func (s *Snapshot) UpdateNodeNameToInfoMap(nodeNameToInfo map[string]*NodeInfo) error {
+	for name, n := range s.nodes {
+		if nodeNameToInfo[name] != n {
+			nodeNameToInfo[name] = n
+		}
+	}
+	for name := range nodeNameToInfo {
+		if _, ok := s.nodes[name]; !ok {
+			delete(nodeNameToInfo, name)
+		}
+	}
+	return nil
+}
+
+func (s *Snapshot) List(selector labels.Selector) ([]*v1.Pod, error) {
+	var pods []*v1.Pod
+	for _, n := range s.nodes {
+		for _, pod := range n.pods {
+			if selector.Matches(labels.Set(pod.Labels)) {
+				pods = append(pods, pod)
+			}
+		}
+	}
+	return pods, nil
+}
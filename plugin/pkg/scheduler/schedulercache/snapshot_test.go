@@ -0,0 +1,129 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schedulercache
+
+import (
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/kubernetes/pkg/api/v1"
+)
+
+func countPods(t *testing.T, cache Cache) int {
+	pods, err := cache.List(labels.Everything())
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	return len(pods)
+}
+
+// TestSnapshotCommit tests that pods assumed on a snapshot show in the cache once committed,
+// and that a commit fails if a node changed in the cache since the snapshot was updated.
+func TestSnapshotCommit(t *testing.T) {
+	cache := newSchedulerCache(10*time.Second, time.Second, nil)
+	for _, name := range []string{"node-1", "node-2"} {
+		if err := cache.AddNode(&v1.Node{ObjectMeta: metav1.ObjectMeta{Name: name}}); err != nil {
+			t.Fatalf("AddNode failed: %v", err)
+		}
+	}
+
+	first, second := NewSnapshot(cache), NewSnapshot(cache)
+	for _, snapshot := range []*Snapshot{first, second} {
+		if err := snapshot.Update(); err != nil {
+			t.Fatalf("Update failed: %v", err)
+		}
+	}
+	nodes := map[string]*NodeInfo{}
+	if err := first.UpdateNodeNameToInfoMap(nodes); err != nil {
+		t.Fatalf("UpdateNodeNameToInfoMap failed: %v", err)
+	}
+	handedOut := nodes["node-1"]
+
+	for _, name := range []string{"test-1", "test-2"} {
+		if err := first.AssumePod(makeBasePod("node-1", name, "100m", "500", nil)); err != nil {
+			t.Fatalf("AssumePod failed: %v", err)
+		}
+	}
+	if n := countPods(t, first); n != 2 {
+		t.Errorf("expected 2 pods on the snapshot, got %d", n)
+	}
+	if n := countPods(t, cache); n != 0 {
+		t.Errorf("expected no pods in the cache before the commit, got %d", n)
+	}
+	if len(handedOut.Pods()) != 0 {
+		t.Errorf("expected the node handed out before to stay unchanged, got %s", handedOut)
+	}
+	if err := first.UpdateNodeNameToInfoMap(nodes); err != nil {
+		t.Fatalf("UpdateNodeNameToInfoMap failed: %v", err)
+	}
+	if len(nodes["node-1"].Pods()) != 2 {
+		t.Errorf("expected the pods assumed on node-1, got %s", nodes["node-1"])
+	}
+
+	// A pod forgotten on the snapshot is not committed.
+	if err := first.ForgetPod(makeBasePod("node-1", "test-2", "100m", "500", nil)); err != nil {
+		t.Fatalf("ForgetPod failed: %v", err)
+	}
+	if err := first.Commit(); err != nil {
+		t.Fatalf("Commit failed: %v", err)
+	}
+	if n := countPods(t, cache); n != 1 {
+		t.Errorf("expected 1 pod in the cache after the commit, got %d", n)
+	}
+
+	// The second snapshot was updated before node-1 changed.
+	if err := second.AssumePod(makeBasePod("node-2", "test-3", "100m", "500", nil)); err != nil {
+		t.Fatalf("AssumePod failed: %v", err)
+	}
+	if err := second.AssumePod(makeBasePod("node-1", "test-4", "100m", "500", nil)); err != nil {
+		t.Fatalf("AssumePod failed: %v", err)
+	}
+	err := second.Commit()
+	if conflict, ok := err.(*ConflictError); !ok || conflict.Node != "node-1" {
+		t.Fatalf("expected a conflict on node-1, got %v", err)
+	}
+	if n := countPods(t, cache); n != 1 {
+		t.Errorf("expected no pod committed on a conflict, got %d pods in the cache", n)
+	}
+
+	// Placed again on an updated snapshot, the pods are committed.
+	if err := second.Update(); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	if n := countPods(t, second); n != 1 {
+		t.Errorf("expected the pods assumed before to be discarded by Update, got %d pods", n)
+	}
+	if err := second.AssumePod(makeBasePod("node-1", "test-4", "100m", "500", nil)); err != nil {
+		t.Fatalf("AssumePod failed: %v", err)
+	}
+	if err := second.Commit(); err != nil {
+		t.Fatalf("Commit failed: %v", err)
+	}
+	if n := countPods(t, cache); n != 2 {
+		t.Errorf("expected 2 pods in the cache after the commit, got %d", n)
+	}
+
+	// Pods committed are forgotten in the cache.
+	if err := second.ForgetPod(makeBasePod("node-1", "test-4", "100m", "500", nil)); err != nil {
+		t.Fatalf("ForgetPod failed: %v", err)
+	}
+	if n := countPods(t, cache); n != 1 {
+		t.Errorf("expected the committed pod to be forgotten in the cache, got %d pods", n)
+	}
+}
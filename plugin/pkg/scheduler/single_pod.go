@@ -0,0 +1,90 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"time"
+
+	"github.com/golang/glog"
+	"k8s.io/kubernetes/pkg/api/v1"
+	"k8s.io/kubernetes/plugin/pkg/scheduler/metrics"
+)
+
+// schedulePod does the scheduling workflow for a pod that is not a member of a group. The pod
+// is placed and bound on its own, without waiting for other members, and retried with a
+// backoff by the Error function of the config if it can't be. It shares the cache and the
+// algorithm with the groups.
+func (sched *Scheduler) schedulePod() {
+	pod := sched.currentConfig().NextPod()
+	config := sched.takeOverReloadedConfig()
+	attempt := &Scheduler{config: config}
+	attempt.scheduleSinglePod(pod, func() { sched.releaseConfig(config) })
+}
+
+// scheduleSinglePod places the pod and binds it in the background. done is called when the
+// attempt ends, after the binding if the pod was placed.
+func (sched *Scheduler) scheduleSinglePod(pod *v1.Pod, done func()) {
+	binding := false
+	defer func() {
+		if !binding {
+			done()
+		}
+	}()
+
+	if pod.DeletionTimestamp != nil {
+		sched.config.Recorder.Eventf(pod, v1.EventTypeWarning, "FailedScheduling", "skip schedule deleting pod: %v/%v", pod.Namespace, pod.Name)
+		glog.V(3).Infof("Skip schedule deleting pod: %v/%v", pod.Namespace, pod.Name)
+		return
+	}
+	glog.V(3).Infof("Attempting to schedule pod: %v/%v", pod.Namespace, pod.Name)
+
+	start := time.Now()
+	host, err := sched.schedule(pod, sched.config.NodeLister)
+	if err != nil {
+		sched.acceptStatusUpdate()
+		sched.config.Recorder.Eventf(pod, v1.EventTypeWarning, "FailedScheduling", "%v", err)
+		sched.config.Error(pod, err)
+		return
+	}
+	metrics.SchedulingAlgorithmLatency.Observe(metrics.SinceInMicroseconds(start))
+
+	// Tell the cache to assume that the pod now is running on the host, even though it hasn't
+	// been bound yet, so that the next pods are scheduled without waiting for the binding.
+	assumed := *pod
+	if err := sched.assume(&assumed, host); err != nil {
+		sched.config.Error(pod, err)
+		return
+	}
+
+	binding = true
+	go func() {
+		defer done()
+		result := sched.bind(&assumed, time.Now().Add(sched.config.Binding.timeout()))
+		if err := sched.config.SchedulerCache.FinishBinding(&assumed); err != nil {
+			glog.Errorf("scheduler cache FinishBinding failed: %v", err)
+		}
+		if result.err != nil {
+			sched.acceptStatusUpdate()
+			sched.config.Recorder.Eventf(pod, v1.EventTypeWarning, "FailedScheduling", "Binding rejected: %v", result.err)
+			sched.config.Error(&assumed, result.err)
+			return
+		}
+		metrics.E2eSchedulingLatency.Observe(metrics.SinceInMicroseconds(start))
+		sched.acceptStatusUpdate()
+		sched.config.Recorder.Eventf(pod, v1.EventTypeNormal, "Scheduled", "Successfully assigned %v to %v", pod.Name, host)
+	}()
+}
@@ -0,0 +1,104 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"fmt"
+
+	"github.com/golang/glog"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/kubernetes/pkg/api/v1"
+	podutil "k8s.io/kubernetes/pkg/api/v1/pod"
+	schedulerapi "k8s.io/kubernetes/plugin/pkg/scheduler/api"
+)
+
+const (
+	// DefaultStatusUpdateQPS is the rate events, pod conditions and group status are written at
+	// if not configured.
+	DefaultStatusUpdateQPS = 20
+	// DefaultStatusUpdateBurst is the number of status writes allowed at once if not configured.
+	DefaultStatusUpdateBurst = 50
+)
+
+// acceptStatusUpdate blocks until the rate limiter of the status writes allows one more.
+func (sched *Scheduler) acceptStatusUpdate() {
+	if sched.config.StatusUpdateLimiter != nil {
+		sched.config.StatusUpdateLimiter.Accept()
+	}
+}
+
+// updatePodCondition writes the condition of the pod, unless the pod has the condition with
+// the same reason and message already.
+func (sched *Scheduler) updatePodCondition(pod *v1.Pod, condition *v1.PodCondition) {
+	_, current := podutil.GetPodCondition(&pod.Status, condition.Type)
+	if current != nil && current.Status == condition.Status && current.Reason == condition.Reason && current.Message == condition.Message {
+		return
+	}
+	sched.acceptStatusUpdate()
+	if err := sched.config.PodConditionUpdater.Update(pod, condition); err != nil {
+		glog.Warningf("Failed to update condition of pod %s/%s: %v", pod.Namespace, pod.Name, err)
+	}
+}
+
+// recordGroupEvent records one event for all pods of a group, on the first of them by name.
+func (sched *Scheduler) recordGroupEvent(group *schedulerapi.SchedulingGroup, pods []*v1.Pod, eventtype, reason, messageFmt string, args ...interface{}) {
+	var representative *v1.Pod
+	for _, pod := range pods {
+		if representative == nil || pod.Name < representative.Name {
+			representative = pod
+		}
+	}
+	if representative == nil {
+		glog.V(4).Infof("Group %s has no pod to record event %s on", group.Group, reason)
+		return
+	}
+	sched.acceptStatusUpdate()
+	sched.config.Recorder.Eventf(representative, eventtype, reason, "Group %s: %s", group.Group, fmt.Sprintf(messageFmt, args...))
+}
+
+// recordPlacementFailure records why the group couldn't be placed in this attempt.
+func (sched *Scheduler) recordPlacementFailure(group *schedulerapi.SchedulingGroup, err error) {
+	pods := []*v1.Pod{}
+	for _, rb := range group.Resources {
+		for _, pod := range rb.PendingPods {
+			pods = append(pods, pod)
+		}
+	}
+	sched.recordGroupEvent(group, pods, v1.EventTypeWarning, "FailedScheduling", "%v", err)
+}
+
+// recordBindingOutcome records how many members of the group were bound in this attempt and
+// why the others were not.
+func (sched *Scheduler) recordBindingOutcome(group *schedulerapi.SchedulingGroup, results []bindingResult) {
+	pods := make([]*v1.Pod, 0, len(results))
+	nodes := sets.NewString()
+	var failed []bindingResult
+	for _, result := range results {
+		pods = append(pods, result.pod)
+		if result.err != nil {
+			failed = append(failed, result)
+			continue
+		}
+		nodes.Insert(result.node)
+	}
+	if len(failed) > 0 {
+		sched.recordGroupEvent(group, pods, v1.EventTypeWarning, "FailedScheduling", "Binding rejected for %d of %d pods, %s: %v",
+			len(failed), len(results), failed[0].pod.Name, failed[0].err)
+		return
+	}
+	sched.recordGroupEvent(group, pods, v1.EventTypeNormal, "Scheduled", "Successfully assigned %d pods to %d nodes", len(results), nodes.Len())
+}
@@ -0,0 +1,133 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/kubernetes/pkg/api/v1"
+	schedulerapi "k8s.io/kubernetes/plugin/pkg/scheduler/api"
+)
+
+type countingPodConditionUpdater struct {
+	updates int
+}
+
+func (c *countingPodConditionUpdater) Update(pod *v1.Pod, podCondition *v1.PodCondition) error {
+	c.updates++
+	return nil
+}
+
+func TestUpdatePodConditionOnlyWhenChanged(t *testing.T) {
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "bar"},
+		Status: v1.PodStatus{
+			Conditions: []v1.PodCondition{{
+				Type:    v1.PodScheduled,
+				Status:  v1.ConditionFalse,
+				Reason:  v1.PodReasonUnschedulable,
+				Message: "No nodes are available that match all of the following predicates:: Insufficient cpu (2).",
+			}},
+		},
+	}
+	tests := []struct {
+		name      string
+		condition v1.PodCondition
+		updated   bool
+	}{
+		{
+			name:      "same message",
+			condition: pod.Status.Conditions[0],
+		},
+		{
+			name: "message changed",
+			condition: v1.PodCondition{
+				Type:    v1.PodScheduled,
+				Status:  v1.ConditionFalse,
+				Reason:  v1.PodReasonUnschedulable,
+				Message: "No nodes are available that match all of the following predicates:: Insufficient memory (2).",
+			},
+			updated: true,
+		},
+		{
+			name: "reason changed",
+			condition: v1.PodCondition{
+				Type:    v1.PodScheduled,
+				Status:  v1.ConditionFalse,
+				Reason:  "BindingRejected",
+				Message: pod.Status.Conditions[0].Message,
+			},
+			updated: true,
+		},
+	}
+
+	for _, test := range tests {
+		updater := &countingPodConditionUpdater{}
+		sched := &Scheduler{config: &Config{PodConditionUpdater: updater}}
+		condition := test.condition
+		sched.updatePodCondition(pod, &condition)
+		if updated := updater.updates > 0; updated != test.updated {
+			t.Errorf("%s: expected updated %v, got %d updates", test.name, test.updated, updater.updates)
+		}
+	}
+}
+
+func TestRecordBindingOutcome(t *testing.T) {
+	newPod := func(name string) *v1.Pod {
+		return &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "bar"}}
+	}
+	group := &schedulerapi.SchedulingGroup{Group: "bar/group"}
+	tests := []struct {
+		name    string
+		results []bindingResult
+		event   string
+	}{
+		{
+			name: "all bound",
+			results: []bindingResult{
+				{pod: newPod("worker-1"), node: "machine2"},
+				{pod: newPod("worker-0"), node: "machine1"},
+				{pod: newPod("ps-0"), node: "machine1"},
+			},
+			event: "Normal Scheduled Group bar/group: Successfully assigned 3 pods to 2 nodes",
+		},
+		{
+			name: "some rejected",
+			results: []bindingResult{
+				{pod: newPod("worker-1"), node: "machine2", err: errors.New("forbidden")},
+				{pod: newPod("worker-0"), node: "machine1"},
+			},
+			event: "Warning FailedScheduling Group bar/group: Binding rejected for 1 of 2 pods, worker-1: forbidden",
+		},
+	}
+
+	for _, test := range tests {
+		recorder := record.NewFakeRecorder(10)
+		sched := &Scheduler{config: &Config{Recorder: recorder}}
+		sched.recordBindingOutcome(group, test.results)
+		if len(recorder.Events) != 1 {
+			t.Fatalf("%s: expected one event for the group, got %d", test.name, len(recorder.Events))
+		}
+		if event := <-recorder.Events; !strings.HasPrefix(event, test.event) {
+			t.Errorf("%s: expected event %q, got %q", test.name, test.event, event)
+		}
+	}
+}
@@ -40,6 +40,26 @@ func (f *FakeCache) ForgetPod(pod *v1.Pod) error {
 	return nil
 }
 
+func (f *FakeCache) AssumePods(pods []*v1.Pod) error {
+	for _, pod := range pods {
+		f.AssumeFunc(pod)
+	}
+	return nil
+}
+
+func (f *FakeCache) CommitPods(pods []*v1.Pod, generations map[string]int64) error {
+	return f.AssumePods(pods)
+}
+
+func (f *FakeCache) FinishBindings(pods []*v1.Pod) error { return nil }
+
+func (f *FakeCache) ForgetPods(pods []*v1.Pod) error {
+	for _, pod := range pods {
+		f.ForgetFunc(pod)
+	}
+	return nil
+}
+
 func (f *FakeCache) AddPod(pod *v1.Pod) error { return nil }
 
 func (f *FakeCache) UpdatePod(oldPod, newPod *v1.Pod) error { return nil }
@@ -29,6 +29,14 @@ func (p PodsToCache) AssumePod(pod *v1.Pod) error { return nil }
 
 func (p PodsToCache) ForgetPod(pod *v1.Pod) error { return nil }
 
+func (p PodsToCache) AssumePods(pods []*v1.Pod) error { return nil }
+
+func (p PodsToCache) CommitPods(pods []*v1.Pod, generations map[string]int64) error { return nil }
+
+func (p PodsToCache) FinishBindings(pods []*v1.Pod) error { return nil }
+
+func (p PodsToCache) ForgetPods(pods []*v1.Pod) error { return nil }
+
 func (p PodsToCache) AddPod(pod *v1.Pod) error { return nil }
 
 func (p PodsToCache) UpdatePod(oldPod, newPod *v1.Pod) error { return nil }
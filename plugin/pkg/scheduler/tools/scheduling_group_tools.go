@@ -1,15 +1,36 @@
 package tools
 
 import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
 	"github.com/golang/glog"
 	"k8s.io/kubernetes/pkg/api/v1"
 	schedulerapi "k8s.io/kubernetes/plugin/pkg/scheduler/api"
+	"k8s.io/kubernetes/plugin/pkg/scheduler/schedulercache"
 	"k8s.io/kubernetes/staging/src/k8s.io/apimachinery/pkg/util/json"
 )
 
 const (
 	SchedulingGroup = "ecp-scheduling-group"
 	DefaultRole     = "default-role"
+	// GroupNodeScores is the annotation holding the scores the group extenders gave the nodes
+	// for the group of a pod. It is set on the copies of the members being placed only, for
+	// GroupNodeScorePriority, and never written to the apiserver.
+	GroupNodeScores = "ecp-group-node-scores"
+
+	// PodPriority is the annotation holding the priority of a pod. The pod API the scheduler
+	// is built against predates PodSpec.Priority and PriorityClasses, so the scheduler does
+	// not resolve them: whoever creates the pod has to set the priority of its PriorityClass
+	// in this annotation. Pods without it have priority 0, or the system critical priority.
+	PodPriority = "ecp-pod-priority"
+	// criticalPod is the annotation marking the pods critical to the cluster.
+	criticalPod = "scheduler.alpha.kubernetes.io/critical-pod"
+	// SystemCriticalPriority is the priority of the pods critical to the cluster without
+	// a priority of their own.
+	SystemCriticalPriority = int32(2000000000)
 )
 
 func KeyFunc(obj interface{}) (string, error) {
@@ -38,6 +59,47 @@ func GetSchedulingGroup(pod *v1.Pod) *schedulerapi.MiniGroup {
 	return &miniGroup
 }
 
+// HasSchedulingGroup returns whether the pod is annotated with the group it is a member of.
+func HasSchedulingGroup(pod *v1.Pod) bool {
+	_, ok := pod.Annotations[SchedulingGroup]
+	return ok
+}
+
+// WithGroupNodeScores returns a copy of the pod annotated with the scores of the nodes for its
+// group, or the pod itself if there are no scores.
+func WithGroupNodeScores(pod *v1.Pod, scores map[string]int) *v1.Pod {
+	if len(scores) == 0 {
+		return pod
+	}
+	data, err := json.Marshal(scores)
+	if err != nil {
+		glog.Errorf("Failed to marshal node scores of pod %s/%s: %v", pod.Namespace, pod.Name, err)
+		return pod
+	}
+	scored := *pod
+	scored.Annotations = make(map[string]string, len(pod.Annotations)+1)
+	for key, value := range pod.Annotations {
+		scored.Annotations[key] = value
+	}
+	scored.Annotations[GroupNodeScores] = string(data)
+	return &scored
+}
+
+// GetGroupNodeScores returns the scores of the nodes for the group of the pod, or nil if the
+// pod is not annotated with them.
+func GetGroupNodeScores(pod *v1.Pod) map[string]int {
+	data, ok := pod.Annotations[GroupNodeScores]
+	if !ok {
+		return nil
+	}
+	var scores map[string]int
+	if err := json.Unmarshal([]byte(data), &scores); err != nil {
+		glog.Errorf("Failed to unmarshal node scores: %v", data)
+		return nil
+	}
+	return scores
+}
+
 func NewMiniSchedulerGroup(pod *v1.Pod) *schedulerapi.MiniGroup {
 	return &schedulerapi.MiniGroup{
 		Group:       GetKeyOfPod(pod),
@@ -49,10 +111,15 @@ func NewMiniSchedulerGroup(pod *v1.Pod) *schedulerapi.MiniGroup {
 	}
 }
 
-func MiniGroupToGroup(miniGroup *schedulerapi.MiniGroup) *schedulerapi.SchedulingGroup {
+// MiniGroupToGroup creates the group of the first member seen, which is scheduled by the
+// scheduler of that member.
+func MiniGroupToGroup(miniGroup *schedulerapi.MiniGroup, pod *v1.Pod) *schedulerapi.SchedulingGroup {
 	return &schedulerapi.SchedulingGroup{
 		Group:         miniGroup.Group,
 		ResourceCount: miniGroup.RoleCount,
+		SchedulerName: pod.Spec.SchedulerName,
+		Topology:      miniGroup.Topology,
+		Profile:       miniGroup.Profile,
 		Resources:     []*schedulerapi.ResourceObject{},
 		Status: &schedulerapi.SchedulerGroupState{
 			State:      schedulerapi.Started,
@@ -65,24 +132,28 @@ func GetKeyOfPod(pod *v1.Pod) string {
 	return pod.Namespace + "/" + pod.Name
 }
 
-func SortOtherPendingPods(group *schedulerapi.SchedulingGroup) []*v1.Pod {
+// SortOtherPendingPods returns the pending pods beyond Min of the given roles, interleaved
+// by role priority. The roles are expected to be in placement order, see SortGroupResources.
+func SortOtherPendingPods(group *schedulerapi.SchedulingGroup, resources []*schedulerapi.ResourceObject) []*v1.Pod {
 	result := []*v1.Pod{}
 
 	finished := 0
-	targetCount := len(group.Resources)
+	targetCount := len(resources)
 	posMap := make(map[int]int, targetCount)
 	podsMap := make(map[int][]*v1.Pod, targetCount)
 
-	sortGroupResource(group)
-
-	for index, resource := range group.Resources {
+	for index, resource := range resources {
 		posMap[index] = 0
 		podsMap[index] = getResourcePendingPods(resource.PendingPods, group.Status.PodsToBind)
 	}
 
-	for {
-		for index, pods := range podsMap {
-			step := group.Resources[index].Priority
+	for finished < targetCount {
+		for index := range resources {
+			pods, ok := podsMap[index]
+			if !ok {
+				continue
+			}
+			step := resources[index].Priority
 			for step > 0 && posMap[index] < len(pods) {
 				result = append(result, pods[posMap[index]])
 				posMap[index]++
@@ -95,24 +166,130 @@ func SortOtherPendingPods(group *schedulerapi.SchedulingGroup) []*v1.Pod {
 				finished++
 			}
 		}
-		if finished >= targetCount {
-			break
-		}
 	}
 	return result
 }
 
+// SortGroupResources returns the roles of the group in placement order: every role comes
+// after the roles it depends on, and roles that are free to go are ordered by priority.
+// An error is returned if a role depends on an unknown role or the dependencies form a cycle.
+func SortGroupResources(group *schedulerapi.SchedulingGroup) ([]*schedulerapi.ResourceObject, error) {
+	sortGroupResource(group)
+
+	roles := make(map[string]*schedulerapi.ResourceObject, len(group.Resources))
+	for _, resource := range group.Resources {
+		roles[resource.Role] = resource
+	}
+
+	inDegree := make(map[string]int, len(group.Resources))
+	dependents := make(map[string][]string, len(group.Resources))
+	for _, resource := range group.Resources {
+		for _, dep := range resource.DependsOn {
+			if _, ok := roles[dep.Role]; !ok {
+				return nil, fmt.Errorf("role %s of group %s depends on unknown role %s", resource.Role, group.Group, dep.Role)
+			}
+			inDegree[resource.Role]++
+			dependents[dep.Role] = append(dependents[dep.Role], resource.Role)
+		}
+	}
+
+	result := make([]*schedulerapi.ResourceObject, 0, len(group.Resources))
+	placed := make(map[string]bool, len(group.Resources))
+	for len(result) < len(group.Resources) {
+		var next *schedulerapi.ResourceObject
+		// group.Resources is sorted by priority, so the first free role wins.
+		for _, resource := range group.Resources {
+			if !placed[resource.Role] && inDegree[resource.Role] == 0 {
+				next = resource
+				break
+			}
+		}
+		if next == nil {
+			return nil, fmt.Errorf("role dependencies of group %s contain a cycle", group.Group)
+		}
+		placed[next.Role] = true
+		result = append(result, next)
+		for _, role := range dependents[next.Role] {
+			inDegree[role]--
+		}
+	}
+	return result, nil
+}
+
+// sortGroupResource orders the roles of the group by priority. Roles of the same priority are
+// ordered by the priority of their members.
 func sortGroupResource(group *schedulerapi.SchedulingGroup) {
+	podPriorities := make(map[*schedulerapi.ResourceObject]int32, len(group.Resources))
+	for _, resource := range group.Resources {
+		podPriorities[resource] = GetRolePriority(resource)
+	}
+	less := func(a, b *schedulerapi.ResourceObject) bool {
+		if a.Priority != b.Priority {
+			return a.Priority < b.Priority
+		}
+		return podPriorities[a] < podPriorities[b]
+	}
 	l := len(group.Resources)
 	for i := 0; i < l; i++ {
 		for j := 0; j < l-i-1; j++ {
-			if group.Resources[j].Priority < group.Resources[j+1].Priority {
+			if less(group.Resources[j], group.Resources[j+1]) {
 				group.Resources[j], group.Resources[j+1] = group.Resources[j+1], group.Resources[j]
 			}
 		}
 	}
 }
 
+// GetPodPriority returns the priority of the pod: the priority it is annotated with, else the
+// priority of critical pods if it is one, else 0.
+func GetPodPriority(pod *v1.Pod) int32 {
+	if value, ok := pod.Annotations[PodPriority]; ok {
+		priority, err := strconv.ParseInt(value, 10, 32)
+		if err == nil {
+			return int32(priority)
+		}
+		glog.Warningf("Invalid priority %q of pod %s/%s: %v", value, pod.Namespace, pod.Name, err)
+	}
+	if _, ok := pod.Annotations[criticalPod]; ok {
+		return SystemCriticalPriority
+	}
+	return 0
+}
+
+// GetRolePriority returns the highest priority of the pending members of the role.
+func GetRolePriority(resource *schedulerapi.ResourceObject) int32 {
+	var priority int32
+	first := true
+	for _, pod := range resource.PendingPods {
+		if podPriority := GetPodPriority(pod); first || podPriority > priority {
+			priority, first = podPriority, false
+		}
+	}
+	return priority
+}
+
+// GetGroupPriority returns the priority of the group, the highest priority of its pending
+// members. Members are expected to share a priority, the highest one is used otherwise so that
+// a group is not held back by a member created with a lower priority.
+func GetGroupPriority(group *schedulerapi.SchedulingGroup) int32 {
+	var priority int32
+	first := true
+	for _, resource := range group.Resources {
+		if len(resource.PendingPods) == 0 {
+			continue
+		}
+		if rolePriority := GetRolePriority(resource); first || rolePriority > priority {
+			priority, first = rolePriority, false
+		}
+	}
+	return priority
+}
+
+// IsMemberOfRole returns whether the pod belongs to the given role of the given group.
+func IsMemberOfRole(pod *v1.Pod, group, role string) bool {
+	miniGroup := getAnnotatedGroup(pod, group)
+	return miniGroup != nil && miniGroup.Role == role
+}
+
 func getResourcePendingPods(pods map[string]*v1.Pod, podsToBind map[string]*v1.Pod) []*v1.Pod {
 	result := []*v1.Pod{}
 	for key, val := range pods {
@@ -121,5 +298,79 @@ func getResourcePendingPods(pods map[string]*v1.Pod, podsToBind map[string]*v1.P
 		}
 		result = append(result, val)
 	}
+	// Members of a higher priority are placed first.
+	sort.Slice(result, func(i, j int) bool {
+		pi, pj := GetPodPriority(result[i]), GetPodPriority(result[j])
+		if pi != pj {
+			return pi > pj
+		}
+		return result[i].Name < result[j].Name
+	})
 	return result
 }
+
+// GroupMember is a pod of a scheduling group which is placed on a node, either assumed
+// in the scheduler cache or bound.
+type GroupMember struct {
+	Pod  *v1.Pod
+	Role string
+	Node *v1.Node
+}
+
+// GetGroupMembers returns the members of the group found on the given nodes.
+func GetGroupMembers(group string, nodeNameToInfo map[string]*schedulercache.NodeInfo) []GroupMember {
+	var members []GroupMember
+	for _, info := range nodeNameToInfo {
+		node := info.Node()
+		if node == nil {
+			continue
+		}
+		for _, pod := range info.Pods() {
+			if miniGroup := getAnnotatedGroup(pod, group); miniGroup != nil {
+				members = append(members, GroupMember{Pod: pod, Role: miniGroup.Role, Node: node})
+			}
+		}
+	}
+	return members
+}
+
+// CountRoleMembers returns the number of members of the role in each domain of the topology
+// key, skipping the given pod. Domains of the given nodes without members are counted as 0,
+// members on nodes without the key are left out.
+func CountRoleMembers(pod *v1.Pod, members []GroupMember, role, key string, nodes []*v1.Node) map[string]int {
+	counts := map[string]int{}
+	for _, node := range nodes {
+		if value, ok := node.Labels[key]; ok {
+			if _, ok := counts[value]; !ok {
+				counts[value] = 0
+			}
+		}
+	}
+	for _, member := range members {
+		if member.Role != role || (member.Pod.Namespace == pod.Namespace && member.Pod.Name == pod.Name) {
+			continue
+		}
+		if value, ok := member.Node.Labels[key]; ok {
+			counts[value]++
+		}
+	}
+	return counts
+}
+
+// getAnnotatedGroup returns the scheduling group of the pod if it is annotated as a member
+// of the given group, nil otherwise. Pods without the annotation are never members.
+func getAnnotatedGroup(pod *v1.Pod, group string) *schedulerapi.MiniGroup {
+	if pod.Annotations == nil {
+		return nil
+	}
+	data, ok := pod.Annotations[SchedulingGroup]
+	// Avoid decoding the annotation of pods which can't belong to the group.
+	if !ok || !strings.Contains(data, group) {
+		return nil
+	}
+	miniGroup := GetSchedulingGroup(pod)
+	if miniGroup == nil || miniGroup.Group != group {
+		return nil
+	}
+	return miniGroup
+}
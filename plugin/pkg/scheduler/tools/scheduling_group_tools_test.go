@@ -0,0 +1,144 @@
+package tools
+
+import (
+	"reflect"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kubernetes/pkg/api/v1"
+	schedulerapi "k8s.io/kubernetes/plugin/pkg/scheduler/api"
+)
+
+func TestSortGroupResources(t *testing.T) {
+	tests := []struct {
+		name      string
+		resources []*schedulerapi.ResourceObject
+		expected  []string
+		expectErr bool
+	}{
+		{
+			name: "no dependencies are ordered by priority",
+			resources: []*schedulerapi.ResourceObject{
+				{Role: "worker", Priority: 1},
+				{Role: "ps", Priority: 3},
+				{Role: "launcher", Priority: 2},
+			},
+			expected: []string{"ps", "launcher", "worker"},
+		},
+		{
+			name: "dependencies override priority",
+			resources: []*schedulerapi.ResourceObject{
+				{Role: "launcher", Priority: 3, DependsOn: []schedulerapi.RoleDependency{{Role: "worker"}}},
+				{Role: "worker", Priority: 2, DependsOn: []schedulerapi.RoleDependency{{Role: "ps"}}},
+				{Role: "ps", Priority: 1},
+			},
+			expected: []string{"ps", "worker", "launcher"},
+		},
+		{
+			name: "unknown role",
+			resources: []*schedulerapi.ResourceObject{
+				{Role: "worker", DependsOn: []schedulerapi.RoleDependency{{Role: "ps"}}},
+			},
+			expectErr: true,
+		},
+		{
+			name: "cycle",
+			resources: []*schedulerapi.ResourceObject{
+				{Role: "ps", DependsOn: []schedulerapi.RoleDependency{{Role: "worker"}}},
+				{Role: "worker", DependsOn: []schedulerapi.RoleDependency{{Role: "ps", Type: schedulerapi.DependencyStarted}}},
+			},
+			expectErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		group := &schedulerapi.SchedulingGroup{Group: "ns/job", Resources: test.resources}
+		roles, err := SortGroupResources(group)
+		if test.expectErr {
+			if err == nil {
+				t.Errorf("%s: expected an error", test.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.name, err)
+			continue
+		}
+		names := []string{}
+		for _, role := range roles {
+			names = append(names, role.Role)
+		}
+		if !reflect.DeepEqual(names, test.expected) {
+			t.Errorf("%s: expected %v, got %v", test.name, test.expected, names)
+		}
+	}
+}
+
+func TestGetPodPriority(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		expected    int32
+	}{
+		{name: "no priority"},
+		{name: "priority", annotations: map[string]string{PodPriority: "1000"}, expected: 1000},
+		{name: "negative priority", annotations: map[string]string{PodPriority: "-5"}, expected: -5},
+		{name: "invalid priority", annotations: map[string]string{PodPriority: "high"}},
+		{name: "critical pod", annotations: map[string]string{criticalPod: ""}, expected: SystemCriticalPriority},
+		{name: "critical pod with priority", annotations: map[string]string{criticalPod: "", PodPriority: "10"}, expected: 10},
+	}
+	for _, test := range tests {
+		pod := &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "foo", Annotations: test.annotations}}
+		if priority := GetPodPriority(pod); priority != test.expected {
+			t.Errorf("%s: expected priority %d, got %d", test.name, test.expected, priority)
+		}
+	}
+}
+
+func TestGroupNodeScores(t *testing.T) {
+	pod := &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "worker-0", Namespace: "bar", Annotations: map[string]string{"foo": "bar"}}}
+	if WithGroupNodeScores(pod, nil) != pod {
+		t.Errorf("expected the pod itself without scores")
+	}
+
+	scores := map[string]int{"machine1": 10, "machine2": 5}
+	scored := WithGroupNodeScores(pod, scores)
+	if _, ok := pod.Annotations[GroupNodeScores]; ok {
+		t.Errorf("expected the pod not to be modified")
+	}
+	if scored.Annotations["foo"] != "bar" {
+		t.Errorf("expected the annotations of the pod to be kept, got %v", scored.Annotations)
+	}
+	if got := GetGroupNodeScores(scored); !reflect.DeepEqual(got, scores) {
+		t.Errorf("expected scores %v, got %v", scores, got)
+	}
+	if got := GetGroupNodeScores(pod); got != nil {
+		t.Errorf("expected no scores for the pod, got %v", got)
+	}
+}
+
+func TestSortGroupResourcesByPodPriority(t *testing.T) {
+	role := func(name string, priority int, podPriority string) *schedulerapi.ResourceObject {
+		pod := &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name + "-0", Annotations: map[string]string{PodPriority: podPriority}}}
+		return &schedulerapi.ResourceObject{Role: name, Priority: priority, PendingPods: map[string]*v1.Pod{pod.Name: pod}}
+	}
+	group := &schedulerapi.SchedulingGroup{Group: "ns/job", Resources: []*schedulerapi.ResourceObject{
+		role("worker", 1, "100"),
+		role("evaluator", 1, "1000"),
+		role("ps", 2, "0"),
+	}}
+	roles, err := SortGroupResources(group)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	names := []string{}
+	for _, role := range roles {
+		names = append(names, role.Role)
+	}
+	if expected := []string{"ps", "evaluator", "worker"}; !reflect.DeepEqual(names, expected) {
+		t.Errorf("Expected %v, got %v", expected, names)
+	}
+	if priority := GetGroupPriority(group); priority != 1000 {
+		t.Errorf("Expected the group priority to be the highest of its members, got %d", priority)
+	}
+}